@@ -1,6 +1,9 @@
 package main
 
 import (
+	"strings"
+	"time"
+
 	ceTypes "github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
 	"github.com/spf13/pflag"
 )
@@ -75,8 +78,66 @@ const (
 	defaultLoggingLevel = "debug"
 	
 	// Cost
+	defaultCostProvider = "aws" // aws, mock, file, cur or composite
+	defaultCostFilePath = "deploy/cost.json"
+	defaultCostCurPath = "" // local directory or s3://bucket/prefix
+	defaultCostCurEndpoint = ""
+	defaultCostCurRegion = ""
+	defaultCostCurCostColumn = "line_item_unblended_cost"
+	defaultCostCurTtl = 6 * time.Hour
+	defaultCostCompositeDefault = "aws"
 	defaultCostRoundFlag = true
+	defaultCostFiscalStartMonth = 1
+	defaultCostFiscalPattern = "calendar" // calendar, 445, 454 or 544
+	defaultCostTrendlineMethod = "least_squares" // least_squares or theil_sen
+	defaultCostForecastHistoryDays = 90
+	defaultCostForecastConfidence = 0.8
+	defaultCostForecastCrossCheck = false
+	defaultCostCommitmentServices = "EC2,Lambda,RDS"
+	defaultCostProductsLookback = "P3M"
+	defaultCostProductsTtl = 24 * time.Hour
+	defaultCostInsightsGroupBy = "tag:Product"
+	defaultCostAlertsAnomalyHistoryDays = 56
+	defaultCostAlertsAnomalyRecentDays = 7
+	defaultCostAlertsAnomalyThreshold = 3.0
+	defaultCostAlertsAnomalyMinExcess = 10.0
+	defaultCostAlertsUnlabeledTagKey = ""
+	defaultCostAlertsBudgetPath = ""
+	defaultCostAlertsBudgetWarning = 0.8
+	defaultCostAlertsBudgetProjected = 1.0
+	defaultCostAlertsBudgetBreach = 1.0
+	defaultCostAlertsSnooze = "P7D"
+	defaultCostAlertsDismiss = "P90D"
+	defaultCostAlertsHideInactive = false
+	defaultCostAlertsSavingsTerm = "ONE_YEAR"
+	defaultCostAlertsSavingsPaymentOption = "NO_UPFRONT"
+	defaultCostAlertsSavingsPlans = "COMPUTE_SP"
+	defaultCostAlertsSavingsReservations = "EC2"
+	defaultCostAlertsRightsizingTarget = "SAME_INSTANCE_FAMILY"
+	defaultCostAlertsRightsizingBenefitsConsidered = true
 	defaultCostAwsDatasets = string(ceTypes.MetricNetAmortizedCost)
+	defaultCostAwsClient = "live" // live, record, replay or store
+	defaultCostAwsFixtures = "pkg/svc/testdata/fixtures"
+	defaultCostAccountsDirectory = "" // organizations, file or none
+	defaultCostAccountsFile = "deploy/accounts.yaml"
+	defaultCostAccountsOwnerTag = "owner"
+	defaultCostAccountsTtl = time.Hour
+	defaultCostCacheBackend = "" // lru, redis or none
+	defaultCostCacheSize = 1000
+	defaultCostCacheRedisAddress = "localhost:6379"
+	defaultCostCacheRedisPassword = ""
+	defaultCostCacheRedisDb = 0
+	defaultCostCacheOpenDays = 3
+	defaultCostCacheOpenTtl = time.Hour
+	defaultCostCacheClosedTtl = 24 * time.Hour
+
+	// Ingestion
+	defaultIngestStart = "" // the first day of the month 12 months ago
+	defaultIngestEnd = "" // today
+	defaultIngestTag = ""
+	defaultIngestChunkDays = 7
+	defaultIngestRestateDays = 3
+	defaultIngestInterval = time.Duration(0)
 	defaultCostAwsSupport = false
 	defaultCostAccountType = "DeveloperAccount"
 	//TODO: Add a config option for the AWS EDP, default is none; match year with discount and return savings
@@ -98,7 +159,7 @@ var (
 
 	flagDatabaseEnable   = pflag.Bool("database.enable", defaultDatabaseEnable, "enable database")
 	flagDatabaseDSN      = pflag.String("database.dsn", defaultDatabaseDSN, "DSN of the database")
-	flagDatabaseType     = pflag.String("database.type", defaultDatabaseType, "type of the database")
+	flagDatabaseType     = pflag.String("database.type", defaultDatabaseType, "type of the database: postgres or sqlite3")
 	flagDatabaseAddress  = pflag.String("database.address", defaultDatabaseAddress, "address of the database")
 	flagDatabasePort     = pflag.String("database.port", defaultDatabasePort, "port of the database")
 	flagDatabaseName     = pflag.String("database.name", defaultDatabaseName, "name of the database")
@@ -142,8 +203,65 @@ var (
 
 	flagLoggingLevel = pflag.String("logging.level", defaultLoggingLevel, "log level of application")
 	
+	flagCostProvider = pflag.String("cost.provider", defaultCostProvider, "backend serving cost insights: aws, mock, file, cur or composite")
+	flagCostFilePath = pflag.String("cost.file.path", defaultCostFilePath, "path of the cost records served by the file provider")
+	flagCostCurPath = pflag.String("cost.cur.path", defaultCostCurPath, "local directory or s3://bucket/prefix of the cost and usage report export served by the cur provider")
+	flagCostCurEndpoint = pflag.String("cost.cur.endpoint", defaultCostCurEndpoint, "endpoint of an S3-compatible store holding the cost and usage report export")
+	flagCostCurRegion = pflag.String("cost.cur.region", defaultCostCurRegion, "region of the S3 bucket holding the cost and usage report export")
+	flagCostCurCostColumn = pflag.String("cost.cur.cost_column", defaultCostCurCostColumn, "cost and usage report column of the cost, such as line_item_net_unblended_cost")
+	flagCostCurTtl = pflag.Duration("cost.cur.ttl", defaultCostCurTtl, "time after which the cost and usage report export is read again, 0 reads it once")
+	flagCostCompositeDefault = pflag.String("cost.composite.default", defaultCostCompositeDefault, "provider used by the composite provider when a call has no provider configured")
+	flagCostFiscalStartMonth = pflag.Int("cost.fiscal.start_month", defaultCostFiscalStartMonth, "first month (1-12) of the fiscal year")
+	flagCostFiscalPattern = pflag.String("cost.fiscal.pattern", defaultCostFiscalPattern, "fiscal months: calendar months or the 445, 454 or 544 week patterns")
+	flagCostTrendlineMethod = pflag.String("cost.trendline.method", defaultCostTrendlineMethod, "trendline fit: least_squares or the outlier resistant theil_sen")
+	flagCostForecastHistoryDays = pflag.Int("cost.forecast.history_days", defaultCostForecastHistoryDays, "days of daily cost history the cost forecast is fit on")
+	flagCostForecastConfidence = pflag.Float64("cost.forecast.confidence", defaultCostForecastConfidence, "default probability of the cost forecast confidence band")
+	flagCostForecastCrossCheck = pflag.Bool("cost.forecast.cross_check", defaultCostForecastCrossCheck, "also return the Cost Explorer forecast of the period for comparison")
+	flagCostCommitmentServices = pflag.StringSlice("cost.commitment.services", strings.Split(defaultCostCommitmentServices, ","), "services of the commitment coverage and utilization without a service")
+	flagCostProductsLookback = pflag.String("cost.products.lookback", defaultCostProductsLookback, "ISO 8601 duration of the cost products are discovered from without intervals")
+	flagCostProductsTtl = pflag.Duration("cost.products.ttl", defaultCostProductsTtl, "time the discovered products of a period are cached")
+	flagCostInsightsGroupBy = pflag.String("cost.insights.group_by", defaultCostInsightsGroupBy, "breakdown of the product insights: a dimension, tag:key or cost_category:name")
+	flagCostAlertsAnomalyHistoryDays = pflag.Int("cost.alerts.anomaly.history_days", defaultCostAlertsAnomalyHistoryDays, "days of daily cost per account and service scanned for cost anomalies")
+	flagCostAlertsAnomalyRecentDays = pflag.Int("cost.alerts.anomaly.recent_days", defaultCostAlertsAnomalyRecentDays, "last days of the history alerted on, the days before are the baseline")
+	flagCostAlertsAnomalyThreshold = pflag.Float64("cost.alerts.anomaly.threshold", defaultCostAlertsAnomalyThreshold, "robust z-score of the daily cost above the expected cost of an anomaly")
+	flagCostAlertsAnomalyMinExcess = pflag.Float64("cost.alerts.anomaly.min_excess", defaultCostAlertsAnomalyMinExcess, "smallest cost above the expected cost of an alerted anomaly")
+	flagCostAlertsUnlabeledTagKey = pflag.String("cost.alerts.unlabeled.tag_key", defaultCostAlertsUnlabeledTagKey, "cost allocation tag key whose untagged cost is alerted on without alert rules")
+	flagCostAlertsBudgetPath = pflag.String("cost.alerts.budget.path", defaultCostAlertsBudgetPath, "JSON file of the monthly budgets by group or project of budget alert rules without a budget")
+	flagCostAlertsBudgetWarning = pflag.Float64("cost.alerts.budget.warning", defaultCostAlertsBudgetWarning, "default share of the budget spent month to date of a budget warning")
+	flagCostAlertsBudgetProjected = pflag.Float64("cost.alerts.budget.projected", defaultCostAlertsBudgetProjected, "share of the budget projected by the run rate of a burn rate alert, below 1 alerts before any overspend")
+	flagCostAlertsBudgetBreach = pflag.Float64("cost.alerts.budget.breach", defaultCostAlertsBudgetBreach, "share of the budget spent month to date of a budget breach alert, below 1 alerts before any overspend")
+	flagCostAlertsSnooze = pflag.String("cost.alerts.snooze", defaultCostAlertsSnooze, "ISO 8601 duration of an alert snooze without a duration")
+	flagCostAlertsDismiss = pflag.String("cost.alerts.dismiss", defaultCostAlertsDismiss, "ISO 8601 duration of an alert dismissal without a duration")
+	flagCostAlertsHideInactive = pflag.Bool("cost.alerts.hide_inactive", defaultCostAlertsHideInactive, "leave snoozed and dismissed alerts out of GetAlerts instead of flagging them")
+	flagCostAlertsSavingsTerm = pflag.String("cost.alerts.savings.term", defaultCostAlertsSavingsTerm, "term of the recommended savings purchases: ONE_YEAR or THREE_YEARS")
+	flagCostAlertsSavingsPaymentOption = pflag.String("cost.alerts.savings.payment_option", defaultCostAlertsSavingsPaymentOption, "payment option of the recommended savings purchases: NO_UPFRONT, PARTIAL_UPFRONT or ALL_UPFRONT")
+	flagCostAlertsSavingsPlans = pflag.StringSlice("cost.alerts.savings.plans", []string{defaultCostAlertsSavingsPlans}, "savings plans types recommended by savings rules: COMPUTE_SP, EC2_INSTANCE_SP or SAGEMAKER_SP")
+	flagCostAlertsSavingsReservations = pflag.StringSlice("cost.alerts.savings.reservations", []string{defaultCostAlertsSavingsReservations}, "services whose reserved instances are recommended by savings rules")
+	flagCostAlertsRightsizingTarget = pflag.String("cost.alerts.rightsizing.target", defaultCostAlertsRightsizingTarget, "instance types recommended by rightsizing rules: SAME_INSTANCE_FAMILY or CROSS_INSTANCE_FAMILY")
+	flagCostAlertsRightsizingBenefitsConsidered = pflag.Bool("cost.alerts.rightsizing.benefits_considered", defaultCostAlertsRightsizingBenefitsConsidered, "include the Savings Plans and reservations of the instances in the rightsizing savings")
 	flagCostRoundFlag = pflag.Bool("cost.round", defaultCostRoundFlag, "rounds cost to nearest whole number")
 	flagCostAwsDatasets = pflag.String("cost.aws.datasets", defaultCostAwsDatasets, "selects the dataset for displaying costs")
+	flagCostAwsClient = pflag.String("cost.aws.client", defaultCostAwsClient, "cost explorer client: live, record responses to fixtures replay fixtures or store to serve the database snapshots")
+	flagCostAwsFixtures = pflag.String("cost.aws.fixtures", defaultCostAwsFixtures, "directory of the recorded cost explorer fixtures")
+	flagCostAccountsDirectory = pflag.String("cost.accounts.directory", defaultCostAccountsDirectory, "directory resolving AWS Account names: organizations, file or empty for none")
+	flagCostAccountsFile = pflag.String("cost.accounts.file", defaultCostAccountsFile, "path of the accounts listed by the file account directory")
+	flagCostAccountsOwnerTag = pflag.String("cost.accounts.owner_tag", defaultCostAccountsOwnerTag, "AWS Organizations account tag holding the account owner")
+	flagCostAccountsTtl = pflag.Duration("cost.accounts.ttl", defaultCostAccountsTtl, "time the AWS Organizations accounts are cached")
+	flagCostCacheBackend = pflag.String("cost.cache.backend", defaultCostCacheBackend, "cache of cost explorer queries: lru, redis or empty for none")
+	flagCostCacheSize = pflag.Int("cost.cache.size", defaultCostCacheSize, "number of queries kept by the lru cache")
+	flagCostCacheRedisAddress = pflag.String("cost.cache.redis.address", defaultCostCacheRedisAddress, "address of the redis cache")
+	flagCostCacheRedisPassword = pflag.String("cost.cache.redis.password", defaultCostCacheRedisPassword, "password of the redis cache")
+	flagCostCacheRedisDb = pflag.Int("cost.cache.redis.db", defaultCostCacheRedisDb, "database of the redis cache")
+	flagCostCacheOpenDays = pflag.Int("cost.cache.open_days", defaultCostCacheOpenDays, "recent billing days whose cost may still change")
+	flagCostCacheOpenTtl = pflag.Duration("cost.cache.open_ttl", defaultCostCacheOpenTtl, "time queries over the open billing days are cached")
+	flagCostCacheClosedTtl = pflag.Duration("cost.cache.closed_ttl", defaultCostCacheClosedTtl, "time queries over closed billing days are cached")
+
+	flagIngestStart = pflag.String("ingest.start", defaultIngestStart, "first day (YYYY-MM-DD) of a backfill, default the checkpoint or the first day of the month 12 months ago")
+	flagIngestEnd = pflag.String("ingest.end", defaultIngestEnd, "day (YYYY-MM-DD) after the ingested costs, default today")
+	flagIngestTag = pflag.String("ingest.tag", defaultIngestTag, "tag key breaking the ingested costs down further, e.g. Product")
+	flagIngestChunkDays = pflag.Int("ingest.chunk_days", defaultIngestChunkDays, "days of costs fetched and saved at a time")
+	flagIngestRestateDays = pflag.Int("ingest.restate_days", defaultIngestRestateDays, "trailing days fetched again as AWS restates them")
+	flagIngestInterval = pflag.Duration("ingest.interval", defaultIngestInterval, "interval of the background ingestion, 0 disables it")
 	flagCostAwsSupport = pflag.Bool("support.cost", defaultCostAwsSupport, "adds a support cost to the aggregation")
 	flagCostAccountType = pflag.String("account.type", defaultCostAccountType, "defines an account type")
 )
@@ -8,9 +8,11 @@ import (
 	"github.com/infobloxopen/atlas-app-toolkit/requestid"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
+
+	"github.com/seizadi/cost-insights-backend/pkg/store"
 )
 
-func NewGRPCServer(logger *logrus.Logger) (*grpc.Server, error) {
+func NewGRPCServer(logger *logrus.Logger, s store.Store) (*grpc.Server, error) {
 	interceptors := []grpc.UnaryServerInterceptor{
 			// logging middleware
 			grpc_logrus.UnaryServerInterceptor(logrus.NewEntry(logger)),
@@ -28,5 +30,5 @@ func NewGRPCServer(logger *logrus.Logger) (*grpc.Server, error) {
 			gateway.UnaryServerInterceptor(),
 	}
 	
-	return CreateServer(logger, interceptors)
+	return CreateServer(logger, interceptors, s)
 }
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+
+	"github.com/seizadi/cost-insights-backend/pkg/ingest"
+	"github.com/seizadi/cost-insights-backend/pkg/store"
+	"github.com/seizadi/cost-insights-backend/pkg/svc"
+)
+
+// IngestCommand runs a single ingestion instead of the server, e.g. "server ingest --ingest.start=2021-01-01"
+const IngestCommand = "ingest"
+
+// NewIngester returns the ingester of the daily costs into the store s of the database.* configuration
+func NewIngester(logger *logrus.Logger, s store.Store) (*ingest.Ingester, error) {
+	if s == nil {
+		return nil, errors.New("ingestion requires database.enable")
+	}
+	client, err := svc.NewAwsCeClient()
+	if err != nil {
+		return nil, err
+	}
+	return ingest.NewIngester(client, s, logger), nil
+}
+
+// RunIngest ingests the daily costs of the ingest.* configuration into the database
+func RunIngest(logger *logrus.Logger) error {
+	s, err := store.NewStore()
+	if err != nil {
+		return err
+	}
+	if s != nil {
+		defer s.Close()
+	}
+	ingester, err := NewIngester(logger, s)
+	if err != nil {
+		return err
+	}
+
+	options, err := ingest.NewOptions(time.Now())
+	if err != nil {
+		return err
+	}
+	logger.Printf("ingesting daily costs from %s to %s", options.Start.Format("2006-01-02"), options.End.Format("2006-01-02"))
+	return ingester.Run(context.Background(), options)
+}
+
+// ServeIngest ingests the daily costs into the store of the server every ingest.interval
+func ServeIngest(logger *logrus.Logger, s store.Store) error {
+	ingester, err := NewIngester(logger, s)
+	if err != nil {
+		return err
+	}
+
+	logger.Printf("ingesting daily costs every %s", viper.GetDuration("ingest.interval"))
+	return ingester.Serve(context.Background(), viper.GetDuration("ingest.interval"))
+}
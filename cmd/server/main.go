@@ -22,17 +22,39 @@ import (
 	
 	"github.com/infobloxopen/atlas-app-toolkit/gorm/resource"
 	"github.com/infobloxopen/atlas-app-toolkit/health"
+
+	"github.com/seizadi/cost-insights-backend/pkg/store"
 )
 
 func main() {
 	doneC := make(chan error)
 	logger := NewLogger()
 
+	if pflag.Arg(0) == IngestCommand {
+		if err := RunIngest(logger); err != nil {
+			logger.Fatal(err)
+		}
+		return
+	}
+
+	// The cost providers and the ingester share one pool of the database
+	s, err := store.NewStore()
+	if err != nil {
+		logger.Fatal(err)
+	}
+	if s != nil {
+		defer s.Close()
+	}
+
+	if viper.GetDuration("ingest.interval") > 0 {
+		go func() { doneC <- ServeIngest(logger, s) }()
+	}
+
 	if viper.GetBool("internal.enable") {
 		go func() { doneC <- ServeInternal(logger) }()
 	}
 
-	go func() { doneC <- ServeExternal(logger) }()
+	go func() { doneC <- ServeExternal(logger, s) }()
 
 	if err := <-doneC; err != nil {
 		logger.Fatal(err)
@@ -90,9 +112,9 @@ func ServeInternal(logger *logrus.Logger) error {
 }
 
 // ServeExternal builds and runs the server that listens on ServerAddress and GatewayAddress
-func ServeExternal(logger *logrus.Logger) error {
+func ServeExternal(logger *logrus.Logger, db store.Store) error {
 
-	grpcServer, err := NewGRPCServer(logger)
+	grpcServer, err := NewGRPCServer(logger, db)
 	if err != nil {
 		logger.Fatalln(err)
 	}
@@ -10,10 +10,11 @@ import (
 	"google.golang.org/grpc/keepalive"
 	
 	"github.com/seizadi/cost-insights-backend/pkg/pb"
+	"github.com/seizadi/cost-insights-backend/pkg/store"
 	"github.com/seizadi/cost-insights-backend/pkg/svc"
 )
 
-func CreateServer(logger *logrus.Logger, interceptors []grpc.UnaryServerInterceptor) (*grpc.Server, error) {
+func CreateServer(logger *logrus.Logger, interceptors []grpc.UnaryServerInterceptor, s store.Store) (*grpc.Server, error) {
 	// create new gRPC grpcServer with middleware chain
 	grpcServer := grpc.NewServer(
 		grpc.KeepaliveParams(
@@ -24,18 +25,17 @@ func CreateServer(logger *logrus.Logger, interceptors []grpc.UnaryServerIntercep
 		), grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(interceptors...)))
 	
 	// register all of our services into the grpcServer
-	s, err := svc.NewBasicServer()
+	bs, err := svc.NewBasicServer()
 	if err != nil {
 		return nil, err
 	}
-	pb.RegisterAwsCostServer(grpcServer, s)
+	pb.RegisterAwsCostServer(grpcServer, bs)
 	
-	// TODO - Make the selection of backend configurable from app config
-	//cs, err := svc.NewCostInsightsApiMockServer()
-	cs, err := svc.NewCostInsightsApiAwsServer()
+	cs, err := svc.NewCostProvider(viper.GetString("cost.provider"), s)
 	if err != nil {
 		return nil, err
 	}
+	logger.Printf("serving cost insights from %s provider", cs.Name())
 	pb.RegisterCostInsightsApiServer(grpcServer, cs)
 	
 	return grpcServer, nil
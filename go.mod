@@ -3,23 +3,29 @@ module github.com/seizadi/cost-insights-backend
 go 1.16
 
 require (
-	github.com/aws/aws-sdk-go-v2 v1.9.0 // indirect
+	github.com/aws/aws-sdk-go-v2 v1.16.16
 	github.com/aws/aws-sdk-go-v2/config v1.7.0
+	github.com/aws/aws-sdk-go-v2/credentials v1.4.0
 	github.com/aws/aws-sdk-go-v2/service/costexplorer v1.9.0
+	github.com/aws/aws-sdk-go-v2/service/organizations v1.16.13
+	github.com/aws/aws-sdk-go-v2/service/s3 v1.27.11
 	github.com/envoyproxy/protoc-gen-validate v0.6.1
+	github.com/go-redis/redis/v8 v8.8.0
 	github.com/golang/protobuf v1.5.2
 	github.com/grpc-ecosystem/go-grpc-middleware v1.3.0
 	github.com/grpc-ecosystem/go-grpc-prometheus v1.2.0
 	github.com/grpc-ecosystem/grpc-gateway v1.16.0
 	github.com/infobloxopen/atlas-app-toolkit v0.25.2
+	github.com/lib/pq v1.10.2
+	github.com/mattn/go-sqlite3 v1.14.6
 	github.com/pkg/errors v0.9.1
 	github.com/prometheus/client_golang v1.11.0
-	github.com/sajari/regression v1.0.1
+	github.com/prometheus/client_model v0.2.0
 	github.com/senseyeio/duration v0.0.0-20180430131211-7c2a214ada46
 	github.com/sirupsen/logrus v1.8.1
 	github.com/spf13/pflag v1.0.5
 	github.com/spf13/viper v1.8.1
-	gonum.org/v1/gonum v0.9.3 // indirect
+	github.com/xitongsys/parquet-go v1.6.2
 	google.golang.org/genproto v0.0.0-20210811021853-ddbe55d93216
 	google.golang.org/grpc v1.40.0
 )
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"os"
+	"sort"
 	"time"
 	
 	"github.com/seizadi/cost-insights-backend/pkg/pb"
@@ -20,6 +21,9 @@ type CustomMetric struct {
 	DAC   float64 `json:"DAC"`
 }
 
+// Budget
+// is the budget in effect from Date, BudgetTotal is the budget of a month, split into its
+// capital and operating expenses
 type Budget struct {
 	Date   string `json:"Date"`
 	BudgetCAPEX    float64    `json:"BudgetCAPEX"`
@@ -50,25 +54,62 @@ func getMockMetrics() (*[]CustomMetric, error) {
 }
 
 func getMockBudget() (*[]Budget, error) {
-	// Open our jsonFile
-	budgetFile, err := os.Open("metrics/budget.json")
+	budget, err := LoadBudgets("metrics/budget.json")
 	if err != nil {
 		return nil, err
 	}
-	defer budgetFile.Close()
-	
-	byteValue, err := ioutil.ReadAll(budgetFile)
-	if err != nil {
+	return &budget, nil
+}
+
+// LoadBudgets
+// reads the budgets of the JSON file at path, a list of Budget in date order
+func LoadBudgets(path string) ([]Budget, error) {
+	var budget []Budget
+	if err := readJSON(path, &budget); err != nil {
 		return nil, err
 	}
-	
-	var budget []Budget
-	err = json.Unmarshal(byteValue, &budget)
-	if err != nil {
+	sort.Slice(budget, func(i, j int) bool { return budget[i].Date < budget[j].Date })
+	return budget, nil
+}
+
+// LoadGroupBudgets
+// reads the budgets of the JSON file at path keyed by the group, or project, they budget, each
+// a list of Budget in date order
+func LoadGroupBudgets(path string) (map[string][]Budget, error) {
+	budgets := map[string][]Budget{}
+	if err := readJSON(path, &budgets); err != nil {
 		return nil, err
 	}
-	
-	return &budget, nil
+	for _, budget := range budgets {
+		sort.Slice(budget, func(i, j int) bool { return budget[i].Date < budget[j].Date })
+	}
+	return budgets, nil
+}
+
+// readJSON decodes the JSON file at path into v
+func readJSON(path string, v interface{}) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	byteValue, err := ioutil.ReadAll(file)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(byteValue, v)
+}
+
+// BudgetOn
+// returns the budget in effect on date, the latest budget dated on or before it
+func BudgetOn(budgets []Budget, date string) (Budget, bool) {
+	for i := len(budgets) - 1; i >= 0; i-- {
+		if budgets[i].Date <= date {
+			return budgets[i], true
+		}
+	}
+	return Budget{}, false
 }
 
 func getMetricKeyIndex(metrics *[]CustomMetric) map[string]int {
@@ -0,0 +1,191 @@
+package accounts
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Account
+// describes an AWS Account: its name, the owner and the path of organizational units (OU) it
+// belongs to, e.g. /Engineering/Platform.
+type Account struct {
+	Id     string `mapstructure:"id"`
+	Name   string `mapstructure:"name"`
+	Email  string `mapstructure:"email"`
+	Owner  string `mapstructure:"owner"`
+	OUPath string `mapstructure:"ou_path"`
+}
+
+// Directory
+// resolves AWS Account Ids to the account details
+type Directory interface {
+	// Accounts returns every account in the directory sorted by Id
+	Accounts(ctx context.Context) ([]Account, error)
+	// Account returns the account with the Id, the bool is false when it is unknown
+	Account(ctx context.Context, id string) (Account, bool, error)
+}
+
+// NewDirectory
+// returns the account directory selected by the cost.accounts.directory configuration:
+// organizations queries the AWS Organizations API, file reads cost.accounts.file and an empty
+// value returns nil as no directory is configured.
+func NewDirectory() (Directory, error) {
+	switch viper.GetString("cost.accounts.directory") {
+	case "":
+		return nil, nil
+	case "file":
+		return NewFileDirectory(viper.GetString("cost.accounts.file"))
+	case "organizations":
+		client, err := NewOrganizationsClient()
+		if err != nil {
+			return nil, err
+		}
+		return NewOrganizationsDirectory(client, viper.GetString("cost.accounts.owner_tag"), viper.GetDuration("cost.accounts.ttl")), nil
+	}
+	return nil, errors.New("unknown account directory: " + viper.GetString("cost.accounts.directory"))
+}
+
+// NameOf
+// returns the name of an account, or the Id when there is no directory or the account is unknown
+func NameOf(ctx context.Context, directory Directory, id string) string {
+	if directory == nil {
+		return id
+	}
+	account, ok, err := directory.Account(ctx, id)
+	if err != nil || !ok || account.Name == "" {
+		return id
+	}
+	return account.Name
+}
+
+// memoryDirectory
+// is a directory of a fixed set of accounts, it backs the file directory and serves as a local
+// fake of the Organizations directory.
+type memoryDirectory struct {
+	accounts map[string]Account
+}
+
+// NewMemoryDirectory
+// returns a directory of the given accounts
+func NewMemoryDirectory(accounts []Account) Directory {
+	d := &memoryDirectory{accounts: map[string]Account{}}
+	for _, account := range accounts {
+		d.accounts[account.Id] = account
+	}
+	return d
+}
+
+func (d memoryDirectory) Accounts(ctx context.Context) ([]Account, error) {
+	accounts := []Account{}
+	for _, account := range d.accounts {
+		accounts = append(accounts, account)
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].Id < accounts[j].Id
+	})
+	return accounts, nil
+}
+
+func (d memoryDirectory) Account(ctx context.Context, id string) (Account, bool, error) {
+	account, ok := d.accounts[id]
+	return account, ok, nil
+}
+
+// cachedDirectory
+// loads all accounts with the load function in the background and serves them until the ttl
+// expires. An expired directory is reloaded in the background while the stale accounts are
+// served, only the calls before the first load completes wait for it.
+type cachedDirectory struct {
+	load func(ctx context.Context) ([]Account, error)
+	ttl  time.Duration
+
+	mu       sync.Mutex
+	loaded   time.Time
+	accounts Directory
+	loading  chan struct{} // closed when the running load ends, nil without one
+	err      error         // error of the last load
+}
+
+// newCachedDirectory
+// returns a directory of the accounts of the load function, the first load starts right away
+func newCachedDirectory(ttl time.Duration, load func(ctx context.Context) ([]Account, error)) *cachedDirectory {
+	d := &cachedDirectory{ttl: ttl, load: load}
+	d.mu.Lock()
+	d.refresh()
+	d.mu.Unlock()
+	return d
+}
+
+// refresh
+// starts loading the accounts in the background unless a load is running, and returns the
+// channel closed when the load ends. A failed reload keeps the stale accounts until the ttl
+// expires again. Must be called with d.mu held.
+func (d *cachedDirectory) refresh() chan struct{} {
+	if d.loading != nil {
+		return d.loading
+	}
+	done := make(chan struct{})
+	d.loading = done
+	go func() {
+		accounts, err := d.load(context.Background())
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		d.err = err
+		if err == nil {
+			d.accounts = NewMemoryDirectory(accounts)
+		}
+		if d.accounts != nil {
+			d.loaded = time.Now()
+		}
+		d.loading = nil
+		close(done)
+	}()
+	return done
+}
+
+func (d *cachedDirectory) directory(ctx context.Context) (Directory, error) {
+	d.mu.Lock()
+	if d.accounts != nil {
+		if d.ttl > 0 && time.Since(d.loaded) >= d.ttl {
+			d.refresh()
+		}
+		accounts := d.accounts
+		d.mu.Unlock()
+		return accounts, nil
+	}
+	done := d.refresh()
+	d.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-done:
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.accounts == nil {
+		return nil, d.err
+	}
+	return d.accounts, nil
+}
+
+func (d *cachedDirectory) Accounts(ctx context.Context) ([]Account, error) {
+	directory, err := d.directory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return directory.Accounts(ctx)
+}
+
+func (d *cachedDirectory) Account(ctx context.Context, id string) (Account, bool, error) {
+	directory, err := d.directory(ctx)
+	if err != nil {
+		return Account{}, false, err
+	}
+	return directory.Account(ctx, id)
+}
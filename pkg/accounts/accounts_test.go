@@ -0,0 +1,263 @@
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	orgTypes "github.com/aws/aws-sdk-go-v2/service/organizations/types"
+)
+
+// fakeOrganizationsClient
+// serves an organization of Root -> Engineering -> Platform with accounts in both OUs, listed
+// one account per page.
+type fakeOrganizationsClient struct {
+	describeCalls int
+	parentCalls   int
+}
+
+var fakeOrganizationsAccounts = []orgTypes.Account{
+	{Id: aws.String("111111111111"), Name: aws.String("platform-prod"), Email: aws.String("prod@example.com"), Status: orgTypes.AccountStatusActive},
+	{Id: aws.String("222222222222"), Name: aws.String("engineering-shared"), Email: aws.String("shared@example.com"), Status: orgTypes.AccountStatusActive},
+	{Id: aws.String("333333333333"), Name: aws.String("management"), Email: aws.String("root@example.com"), Status: orgTypes.AccountStatusActive},
+}
+
+var fakeOrganizationsParents = map[string]orgTypes.Parent{
+	"111111111111":        {Id: aws.String("ou-root-platform"), Type: orgTypes.ParentTypeOrganizationalUnit},
+	"222222222222":        {Id: aws.String("ou-root-engineering"), Type: orgTypes.ParentTypeOrganizationalUnit},
+	"333333333333":        {Id: aws.String("r-root"), Type: orgTypes.ParentTypeRoot},
+	"ou-root-platform":    {Id: aws.String("ou-root-engineering"), Type: orgTypes.ParentTypeOrganizationalUnit},
+	"ou-root-engineering": {Id: aws.String("r-root"), Type: orgTypes.ParentTypeRoot},
+}
+
+var fakeOrganizationsUnits = map[string]string{
+	"ou-root-platform":    "Platform",
+	"ou-root-engineering": "Engineering",
+}
+
+func (c *fakeOrganizationsClient) ListAccounts(ctx context.Context, params *organizations.ListAccountsInput, optFns ...func(*organizations.Options)) (*organizations.ListAccountsOutput, error) {
+	index := 0
+	if params.NextToken != nil {
+		for i, account := range fakeOrganizationsAccounts {
+			if *account.Id == *params.NextToken {
+				index = i
+			}
+		}
+	}
+	output := &organizations.ListAccountsOutput{Accounts: fakeOrganizationsAccounts[index : index+1]}
+	if index+1 < len(fakeOrganizationsAccounts) {
+		output.NextToken = fakeOrganizationsAccounts[index+1].Id
+	}
+	return output, nil
+}
+
+func (c *fakeOrganizationsClient) ListParents(ctx context.Context, params *organizations.ListParentsInput, optFns ...func(*organizations.Options)) (*organizations.ListParentsOutput, error) {
+	c.parentCalls++
+	return &organizations.ListParentsOutput{Parents: []orgTypes.Parent{fakeOrganizationsParents[*params.ChildId]}}, nil
+}
+
+func (c *fakeOrganizationsClient) DescribeOrganizationalUnit(ctx context.Context, params *organizations.DescribeOrganizationalUnitInput, optFns ...func(*organizations.Options)) (*organizations.DescribeOrganizationalUnitOutput, error) {
+	c.describeCalls++
+	id := *params.OrganizationalUnitId
+	return &organizations.DescribeOrganizationalUnitOutput{OrganizationalUnit: &orgTypes.OrganizationalUnit{Id: &id, Name: aws.String(fakeOrganizationsUnits[id])}}, nil
+}
+
+func (c *fakeOrganizationsClient) ListTagsForResource(ctx context.Context, params *organizations.ListTagsForResourceInput, optFns ...func(*organizations.Options)) (*organizations.ListTagsForResourceOutput, error) {
+	if *params.ResourceId != "111111111111" {
+		return &organizations.ListTagsForResourceOutput{}, nil
+	}
+	if params.NextToken == nil {
+		return &organizations.ListTagsForResourceOutput{Tags: []orgTypes.Tag{{Key: aws.String("cost-center"), Value: aws.String("42")}}, NextToken: aws.String("page-2")}, nil
+	}
+	return &organizations.ListTagsForResourceOutput{Tags: []orgTypes.Tag{{Key: aws.String("owner"), Value: aws.String("platform-team")}}}, nil
+}
+
+func TestOrganizationsDirectory(t *testing.T) {
+	client := &fakeOrganizationsClient{}
+	directory := NewOrganizationsDirectory(client, "owner", time.Hour)
+
+	accounts, err := directory.Accounts(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []Account{
+		{Id: "111111111111", Name: "platform-prod", Email: "prod@example.com", Owner: "platform-team", OUPath: "/Engineering/Platform"},
+		{Id: "222222222222", Name: "engineering-shared", Email: "shared@example.com", OUPath: "/Engineering"},
+		{Id: "333333333333", Name: "management", Email: "root@example.com", OUPath: "/"},
+	}
+	if len(accounts) != len(expected) {
+		t.Fatalf("Output %v not equal to expected %v", accounts, expected)
+	}
+	for i := range expected {
+		if accounts[i] != expected[i] {
+			t.Errorf("Output %v not equal to expected %v", accounts[i], expected[i])
+		}
+	}
+	if client.describeCalls != 2 {
+		t.Errorf("Output %d OU lookups not equal to expected 2", client.describeCalls)
+	}
+	// The parents of the Engineering OU are listed once for both of its accounts
+	if client.parentCalls != 5 {
+		t.Errorf("Output %d parent lookups not equal to expected 5", client.parentCalls)
+	}
+
+	// The accounts are cached until the ttl expires
+	if _, _, err := directory.Account(context.Background(), "111111111111"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if client.describeCalls != 2 {
+		t.Errorf("Output %d OU lookups not equal to expected 2 after a cached lookup", client.describeCalls)
+	}
+}
+
+func TestCachedDirectoryReload(t *testing.T) {
+	loads := make(chan []Account)
+	directory := newCachedDirectory(time.Millisecond, func(ctx context.Context) ([]Account, error) {
+		return <-loads, nil
+	})
+	ctx := context.Background()
+
+	// The first calls wait for the load started with the directory
+	go func() { loads <- []Account{{Id: "111111111111", Name: "platform-prod"}} }()
+	if name := NameOf(ctx, directory, "111111111111"); name != "platform-prod" {
+		t.Errorf("Output %q not equal to expected platform-prod", name)
+	}
+
+	// An expired directory serves the stale accounts while it reloads
+	time.Sleep(2 * time.Millisecond)
+	if name := NameOf(ctx, directory, "111111111111"); name != "platform-prod" {
+		t.Errorf("Output %q not equal to the stale platform-prod", name)
+	}
+	directory.mu.Lock()
+	done := directory.loading
+	directory.mu.Unlock()
+	if done == nil {
+		t.Fatal("Expired directory not reloading")
+	}
+	loads <- []Account{{Id: "111111111111", Name: "platform-production"}}
+	<-done
+	if name := NameOf(ctx, directory, "111111111111"); name != "platform-production" {
+		t.Errorf("Output %q not equal to the reloaded platform-production", name)
+	}
+
+	// A call waiting for the first load ends with its context
+	waiting := newCachedDirectory(time.Hour, func(ctx context.Context) ([]Account, error) {
+		return <-loads, nil
+	})
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := waiting.Accounts(cancelled); err != context.Canceled {
+		t.Errorf("Output error %v not equal to expected %v", err, context.Canceled)
+	}
+	loads <- nil
+}
+
+func TestOrganizationsClientRetry(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Header.Get("X-Amz-Target") != "AWSOrganizationsV20161128.ListAccounts" || r.Header.Get("Authorization") == "" {
+			t.Errorf("Unexpected request %v", r.Header)
+		}
+		if calls < 3 {
+			w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"__type":"com.amazonaws.organizations.v20161128#TooManyRequestsException","message":"Rate exceeded"}`)
+			return
+		}
+		fmt.Fprint(w, `{"Accounts":[{"Id":"111111111111","Name":"platform-prod"}]}`)
+	}))
+	defer server.Close()
+
+	noDelay := retry.BackoffDelayerFunc(func(int, error) (time.Duration, error) { return 0, nil })
+	cfg := aws.Config{
+		Credentials: credentials.NewStaticCredentialsProvider("key", "secret", ""),
+		HTTPClient:  server.Client(),
+		Retryer: func() aws.Retryer {
+			return retry.NewStandard(func(o *retry.StandardOptions) {
+				o.MaxAttempts = 3
+				o.Backoff = noDelay
+			})
+		},
+	}
+	client := newOrganizationsClient(cfg, organizations.WithEndpointResolver(organizations.EndpointResolverFromURL(server.URL)))
+
+	// The throttled calls are retried
+	resp, err := client.ListAccounts(context.Background(), &organizations.ListAccountsInput{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if calls != 3 || len(resp.Accounts) != 1 || aws.ToString(resp.Accounts[0].Name) != "platform-prod" {
+		t.Errorf("Output %v after %d calls not equal to the account after 3 calls", resp.Accounts, calls)
+	}
+
+	// A call still throttled after the attempts of the retryer fails
+	calls = -10
+	if _, err := client.ListAccounts(context.Background(), &organizations.ListAccountsInput{}); err == nil || !strings.Contains(err.Error(), "TooManyRequestsException") {
+		t.Errorf("Output error %v not equal to the throttling error", err)
+	}
+}
+
+func TestFileDirectory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "accounts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "accounts.yaml")
+	data := `accounts:
+  - id: "111111111111"
+    name: platform-prod
+    owner: platform-team
+    ou_path: /Engineering/Platform
+`
+	if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	directory, err := NewFileDirectory(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	account, ok, err := directory.Account(context.Background(), "111111111111")
+	if err != nil || !ok {
+		t.Fatalf("Account not found: %v", err)
+	}
+	expected := Account{Id: "111111111111", Name: "platform-prod", Owner: "platform-team", OUPath: "/Engineering/Platform"}
+	if account != expected {
+		t.Errorf("Output %v not equal to expected %v", account, expected)
+	}
+}
+
+func TestNameOf(t *testing.T) {
+	directory := NewMemoryDirectory([]Account{{Id: "111111111111", Name: "platform-prod"}})
+	var tests = []struct {
+		name      string
+		directory Directory
+		id        string
+		expected  string
+	}{
+		{name: "known account", directory: directory, id: "111111111111", expected: "platform-prod"},
+		{name: "unknown account", directory: directory, id: "222222222222", expected: "222222222222"},
+		{name: "no directory", directory: nil, id: "111111111111", expected: "111111111111"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if name := NameOf(context.Background(), test.directory, test.id); name != test.expected {
+				t.Errorf("Output %q not equal to expected %q", name, test.expected)
+			}
+		})
+	}
+}
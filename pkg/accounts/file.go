@@ -0,0 +1,27 @@
+package accounts
+
+import (
+	"github.com/spf13/viper"
+)
+
+// NewFileDirectory
+// returns a directory of the accounts listed in a YAML (or JSON) file, e.g.
+//
+//	accounts:
+//	  - id: "111111111111"
+//	    name: platform-prod
+//	    owner: platform-team
+//	    ou_path: /Engineering/Platform
+func NewFileDirectory(path string) (Directory, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, err
+	}
+
+	var accounts []Account
+	if err := v.UnmarshalKey("accounts", &accounts); err != nil {
+		return nil, err
+	}
+	return NewMemoryDirectory(accounts), nil
+}
@@ -0,0 +1,147 @@
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	orgTypes "github.com/aws/aws-sdk-go-v2/service/organizations/types"
+)
+
+// The AWS Organizations API is global and served from us-east-1
+const organizationsRegion = "us-east-1"
+
+// The Organizations API throttles at a few requests a second, a directory load retries a
+// throttled call with backoff this many times
+const (
+	organizationsMaxAttempts = 8
+	organizationsMaxBackoff  = 20 * time.Second
+)
+
+// OrganizationsClient
+// is the subset of the AWS Organizations API used by the account directory, satisfied by
+// *organizations.Client
+type OrganizationsClient interface {
+	organizations.ListAccountsAPIClient
+	organizations.ListParentsAPIClient
+	organizations.ListTagsForResourceAPIClient
+	DescribeOrganizationalUnit(ctx context.Context, params *organizations.DescribeOrganizationalUnitInput, optFns ...func(*organizations.Options)) (*organizations.DescribeOrganizationalUnitOutput, error)
+}
+
+// NewOrganizationsClient
+// returns an Organizations client using the default AWS configuration, the credentials need
+// to be of the management account or a delegated administrator.
+func NewOrganizationsClient() (OrganizationsClient, error) {
+	cfg, err := config.LoadDefaultConfig(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+	return newOrganizationsClient(cfg), nil
+}
+
+// newOrganizationsClient
+// returns the Organizations client of the AWS configuration in us-east-1, retrying the throttled
+// calls with the retryer of the configuration or else organizationsMaxAttempts times
+func newOrganizationsClient(cfg aws.Config, optFns ...func(*organizations.Options)) *organizations.Client {
+	return organizations.NewFromConfig(cfg, append([]func(*organizations.Options){func(o *organizations.Options) {
+		o.Region = organizationsRegion
+		if cfg.Retryer == nil {
+			o.Retryer = retry.NewStandard(func(r *retry.StandardOptions) {
+				r.MaxAttempts = organizationsMaxAttempts
+				r.MaxBackoff = organizationsMaxBackoff
+			})
+		}
+	}}, optFns...)...)
+}
+
+// NewOrganizationsDirectory
+// returns a directory of the accounts in the AWS Organization. The owner of an account is the
+// value of its ownerTag tag. The accounts are loaded in the background from the start and
+// reloaded after ttl, the stale accounts are served during a reload.
+func NewOrganizationsDirectory(client OrganizationsClient, ownerTag string, ttl time.Duration) Directory {
+	return newCachedDirectory(ttl, func(ctx context.Context) ([]Account, error) {
+		return loadOrganizationsAccounts(ctx, client, ownerTag)
+	})
+}
+
+func loadOrganizationsAccounts(ctx context.Context, client OrganizationsClient, ownerTag string) ([]Account, error) {
+	ouPaths := map[string]string{}
+	accounts := []Account{}
+
+	pages := organizations.NewListAccountsPaginator(client, &organizations.ListAccountsInput{})
+	for pages.HasMorePages() {
+		resp, err := pages.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range resp.Accounts {
+			id := aws.ToString(a.Id)
+			account := Account{Id: id, Name: aws.ToString(a.Name), Email: aws.ToString(a.Email)}
+			account.OUPath, err = organizationsOUPath(ctx, client, id, ouPaths)
+			if err != nil {
+				return nil, err
+			}
+			if ownerTag != "" {
+				account.Owner, err = organizationsTag(ctx, client, id, ownerTag)
+				if err != nil {
+					return nil, err
+				}
+			}
+			accounts = append(accounts, account)
+		}
+	}
+	return accounts, nil
+}
+
+// organizationsOUPath
+// returns the path of the OU names from the root to the parent of an account or OU. The paths
+// of the OUs are cached in ouPaths, so the parents of an account are listed once however deep
+// it is nested.
+func organizationsOUPath(ctx context.Context, client OrganizationsClient, id string, ouPaths map[string]string) (string, error) {
+	resp, err := client.ListParents(ctx, &organizations.ListParentsInput{ChildId: aws.String(id)})
+	if err != nil {
+		return "", err
+	}
+	if len(resp.Parents) == 0 || resp.Parents[0].Type != orgTypes.ParentTypeOrganizationalUnit {
+		return "/", nil
+	}
+	parent := aws.ToString(resp.Parents[0].Id)
+	if path, ok := ouPaths[parent]; ok {
+		return path, nil
+	}
+	ou, err := client.DescribeOrganizationalUnit(ctx, &organizations.DescribeOrganizationalUnitInput{OrganizationalUnitId: aws.String(parent)})
+	if err != nil {
+		return "", err
+	}
+	if ou.OrganizationalUnit == nil {
+		return "", fmt.Errorf("organizational unit %s not found", parent)
+	}
+	path, err := organizationsOUPath(ctx, client, parent, ouPaths)
+	if err != nil {
+		return "", err
+	}
+	path = strings.TrimSuffix(path, "/") + "/" + aws.ToString(ou.OrganizationalUnit.Name)
+	ouPaths[parent] = path
+	return path, nil
+}
+
+func organizationsTag(ctx context.Context, client OrganizationsClient, id string, key string) (string, error) {
+	pages := organizations.NewListTagsForResourcePaginator(client, &organizations.ListTagsForResourceInput{ResourceId: aws.String(id)})
+	for pages.HasMorePages() {
+		resp, err := pages.NextPage(ctx)
+		if err != nil {
+			return "", err
+		}
+		for _, tag := range resp.Tags {
+			if aws.ToString(tag.Key) == key {
+				return aws.ToString(tag.Value), nil
+			}
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,44 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Backends of the query cache selected with the cost.cache.backend configuration
+const (
+	LRUCache   = "lru"
+	RedisCache = "redis"
+)
+
+// Cache
+// stores opaque values by key until their ttl expires
+type Cache interface {
+	// Get returns the value of the key, the bool is false when the key is missing or expired
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores the value of the key for ttl
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// NewCache
+// returns the cache selected by the cost.cache.backend configuration: lru keeps up to
+// cost.cache.size values in memory, redis stores them in the Redis server at
+// cost.cache.redis.address and an empty value returns nil as caching is disabled.
+func NewCache() (Cache, error) {
+	switch viper.GetString("cost.cache.backend") {
+	case "":
+		return nil, nil
+	case LRUCache:
+		return NewLRUCache(viper.GetInt("cost.cache.size")), nil
+	case RedisCache:
+		return NewRedisCache(
+			viper.GetString("cost.cache.redis.address"),
+			viper.GetString("cost.cache.redis.password"),
+			viper.GetInt("cost.cache.redis.db"),
+		), nil
+	}
+	return nil, errors.New("unknown cache backend: " + viper.GetString("cost.cache.backend"))
+}
@@ -0,0 +1,136 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLRUCache(t *testing.T) {
+	now := time.Date(2021, 9, 1, 0, 0, 0, 0, time.UTC)
+	c := NewLRUCache(2).(*lruCache)
+	c.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	c.Set(ctx, "a", []byte("1"), time.Hour)
+	c.Set(ctx, "b", []byte("2"), time.Minute)
+	// a is used more recently than b so b is evicted
+	c.Get(ctx, "a")
+	c.Set(ctx, "c", []byte("3"), time.Hour)
+
+	if _, ok, _ := c.Get(ctx, "b"); ok {
+		t.Error("Expected the least recently used key to be evicted")
+	}
+	if value, ok, _ := c.Get(ctx, "a"); !ok || string(value) != "1" {
+		t.Errorf("Output %q not equal to expected %q", value, "1")
+	}
+
+	now = now.Add(2 * time.Hour)
+	if _, ok, _ := c.Get(ctx, "c"); ok {
+		t.Error("Expected the key to expire after its ttl")
+	}
+}
+
+// fakeRedisServer
+// answers GET and SET of the Redis protocol from a map, it ignores the expiry
+type fakeRedisServer struct {
+	listener net.Listener
+	mu       sync.Mutex
+	values   map[string]string
+	commands []string
+}
+
+func newFakeRedisServer(t *testing.T) *fakeRedisServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &fakeRedisServer{listener: listener, values: map[string]string{}}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeRedisServer) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		count, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+		args := []string{}
+		for i := 0; i < count; i++ {
+			reader.ReadString('\n')
+			arg, _ := reader.ReadString('\n')
+			args = append(args, strings.TrimSuffix(arg, "\r\n"))
+		}
+
+		s.mu.Lock()
+		s.commands = append(s.commands, strings.Join(args, " "))
+		switch strings.ToUpper(args[0]) {
+		case "GET":
+			value, ok := s.values[args[1]]
+			if ok {
+				conn.Write([]byte("$" + strconv.Itoa(len(value)) + "\r\n" + value + "\r\n"))
+			} else {
+				conn.Write([]byte("$-1\r\n"))
+			}
+		case "SET":
+			s.values[args[1]] = args[2]
+			conn.Write([]byte("+OK\r\n"))
+		default:
+			conn.Write([]byte("+OK\r\n"))
+		}
+		s.mu.Unlock()
+	}
+}
+
+func TestRedisCache(t *testing.T) {
+	server := newFakeRedisServer(t)
+	defer server.listener.Close()
+	ctx := context.Background()
+
+	c := NewRedisCache(server.listener.Addr().String(), "secret", 2)
+	if _, ok, err := c.Get(ctx, "key"); err != nil || ok {
+		t.Fatalf("Expected a missing key, got %v %v", ok, err)
+	}
+	if err := c.Set(ctx, "key", []byte("value"), 90*time.Second); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	value, ok, err := c.Get(ctx, "key")
+	if err != nil || !ok || string(value) != "value" {
+		t.Errorf("Output %q not equal to expected %q", value, "value")
+	}
+
+	expected := []string{"auth secret", "select 2", "get key", "set key value ex 90", "get key"}
+	if strings.ToLower(strings.Join(server.commands, ",")) != strings.Join(expected, ",") {
+		t.Errorf("Output %v not equal to expected %v", server.commands, expected)
+	}
+
+	// Concurrent calls are served by the pool of connections
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if value, ok, err := c.Get(ctx, "key"); err != nil || !ok || string(value) != "value" {
+				t.Errorf("Output %q not equal to expected %q: %v", value, "value", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
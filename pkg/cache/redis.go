@@ -0,0 +1,36 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// redisCache
+// stores the values in Redis with a go-redis client, whose pool of connections serves
+// concurrent calls and replaces the connections that fail
+type redisCache struct {
+	client *redis.Client
+}
+
+// NewRedisCache
+// returns a cache in the Redis server at address, the connections are opened on first use
+func NewRedisCache(address string, password string, db int) Cache {
+	return &redisCache{client: redis.NewClient(&redis.Options{Addr: address, Password: password, DB: db})}
+}
+
+func (c *redisCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := c.client.Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (c *redisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.client.Set(ctx, key, value, ttl).Err()
+}
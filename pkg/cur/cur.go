@@ -0,0 +1,287 @@
+package cur
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Columns of the line items, named as in the Parquet exports
+const (
+	UsageStartDateColumn = "line_item_usage_start_date"
+	UsageAccountIdColumn = "line_item_usage_account_id"
+	ProductCodeColumn    = "line_item_product_code"
+	ProductNameColumn    = "product_product_name"
+	UsageTypeColumn      = "line_item_usage_type"
+	ResourceIdColumn     = "line_item_resource_id"
+	UnblendedCostColumn  = "line_item_unblended_cost"
+)
+
+const manifestSuffix = "-Manifest.json"
+
+// LineItem
+// is the daily cost of an entity of a product, the sum of the line items of the export with the
+// same date, account, product and entity. The product is the product name of the line items, or
+// their product code for line items without a name such as taxes, and the entity their resource,
+// or their usage type when they have no resource.
+type LineItem struct {
+	Date    string
+	Account string
+	Product string
+	Entity  string
+	Cost    float64
+}
+
+// ColumnName
+// normalizes the column name of a CSV export, such as lineItem/UsageStartDate, to the name of
+// the column in the Parquet exports, line_item_usage_start_date.
+func ColumnName(name string) string {
+	var b strings.Builder
+	previous := '_'
+	for _, r := range name {
+		switch {
+		case unicode.IsUpper(r):
+			if previous != '_' && !unicode.IsUpper(previous) {
+				b.WriteRune('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		default:
+			r = '_'
+			if previous != '_' {
+				b.WriteRune(r)
+			}
+		}
+		previous = r
+	}
+	return strings.TrimSuffix(b.String(), "_")
+}
+
+type manifest struct {
+	AssemblyId    string `json:"assemblyId"`
+	BillingPeriod struct {
+		Start string `json:"start"`
+	} `json:"billingPeriod"`
+	ReportKeys []string `json:"reportKeys"`
+}
+
+// isDataFile
+// returns whether the key is a CSV or Parquet file of the export
+func isDataFile(key string) bool {
+	return strings.HasSuffix(key, ".csv") || strings.HasSuffix(key, ".csv.gz") || strings.HasSuffix(key, ".parquet")
+}
+
+// reportKeys
+// returns the keys of the data files of the export. The CUR exports to S3 rewrite the files of
+// a billing period on every update into a new assembly, the manifest at the top of the period
+// lists the files of the current assembly. Without manifests, such as in the Parquet exports
+// for Athena, every data file is read.
+func reportKeys(ctx context.Context, source Source, keys []string) ([]string, error) {
+	manifests := map[string]manifest{}
+	manifestKeys := map[string]string{}
+	for _, key := range keys {
+		if !strings.HasSuffix(key, manifestSuffix) {
+			continue
+		}
+		m, err := readManifest(ctx, source, key)
+		if err != nil {
+			return nil, fmt.Errorf("cur manifest %s: %v", key, err)
+		}
+		// The manifests in the assembly directories are copies of the period manifest at the time
+		period := m.BillingPeriod.Start
+		if current, ok := manifestKeys[period]; ok && len(current) <= len(key) {
+			continue
+		}
+		manifests[period], manifestKeys[period] = m, key
+	}
+
+	dataKeys := []string{}
+	if len(manifests) == 0 {
+		for _, key := range keys {
+			if isDataFile(key) {
+				dataKeys = append(dataKeys, key)
+			}
+		}
+		return dataKeys, nil
+	}
+
+	reportKeys := map[string]bool{}
+	for _, m := range manifests {
+		for _, reportKey := range m.ReportKeys {
+			reportKeys[reportKey] = true
+		}
+	}
+	// The report keys are the full keys in the bucket, the source keys are relative to the export
+	for _, key := range keys {
+		if !isDataFile(key) {
+			continue
+		}
+		for reportKey := range reportKeys {
+			if reportKey == key || strings.HasSuffix(reportKey, "/"+key) {
+				dataKeys = append(dataKeys, key)
+				break
+			}
+		}
+	}
+	return dataKeys, nil
+}
+
+func readManifest(ctx context.Context, source Source, key string) (manifest, error) {
+	var m manifest
+	reader, err := source.Open(ctx, key)
+	if err != nil {
+		return m, err
+	}
+	defer reader.Close()
+	err = json.NewDecoder(reader).Decode(&m)
+	return m, err
+}
+
+// Read
+// returns the daily line items of the export in the source sorted by date, account, product and
+// entity. costColumn is the column of the cost, such as line_item_unblended_cost or
+// line_item_net_unblended_cost. The files are streamed, only the sums of the line items are kept.
+func Read(ctx context.Context, source Source, costColumn string) ([]LineItem, error) {
+	keys, err := source.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	keys, err = reportKeys(ctx, source, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	costColumn = ColumnName(costColumn)
+	columns := map[string]bool{
+		UsageStartDateColumn: true,
+		UsageAccountIdColumn: true,
+		ProductCodeColumn:    true,
+		ProductNameColumn:    true,
+		UsageTypeColumn:      true,
+		ResourceIdColumn:     true,
+		costColumn:           true,
+	}
+	items := map[LineItem]float64{}
+	row := func(values map[string]string) error {
+		date := values[UsageStartDateColumn]
+		if len(date) < 10 {
+			return fmt.Errorf("invalid usage start date %q", date)
+		}
+		cost := 0.0
+		if value := values[costColumn]; value != "" {
+			var err error
+			if cost, err = strconv.ParseFloat(value, 64); err != nil {
+				return err
+			}
+		}
+		item := LineItem{
+			Date:    date[:10],
+			Account: values[UsageAccountIdColumn],
+			Product: values[ProductNameColumn],
+			Entity:  values[ResourceIdColumn],
+		}
+		if item.Product == "" {
+			item.Product = values[ProductCodeColumn]
+		}
+		if item.Entity == "" {
+			item.Entity = values[UsageTypeColumn]
+		}
+		items[item] += cost
+		return nil
+	}
+
+	for _, key := range keys {
+		if err := readFile(ctx, source, key, columns, row); err != nil {
+			return nil, fmt.Errorf("cur file %s: %v", key, err)
+		}
+	}
+
+	lineItems := []LineItem{}
+	for item, cost := range items {
+		item.Cost = cost
+		lineItems = append(lineItems, item)
+	}
+	sort.Slice(lineItems, func(i, j int) bool {
+		a, b := lineItems[i], lineItems[j]
+		if a.Date != b.Date {
+			return a.Date < b.Date
+		}
+		if a.Account != b.Account {
+			return a.Account < b.Account
+		}
+		if a.Product != b.Product {
+			return a.Product < b.Product
+		}
+		return a.Entity < b.Entity
+	})
+	return lineItems, nil
+}
+
+// readFile
+// calls row with the values of every row of a data file of the export
+func readFile(ctx context.Context, source Source, key string, columns map[string]bool, row func(map[string]string) error) error {
+	if strings.HasSuffix(key, ".parquet") {
+		file, err := openFile(ctx, source, key)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		return readParquet(file, file.Size(), columns, row)
+	}
+
+	reader, err := source.Open(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	return readCsv(reader, strings.HasSuffix(key, ".gz"), row)
+}
+
+// readCsv
+// calls row with the values of every row of a CSV export by normalized column name
+func readCsv(reader io.Reader, compressed bool, row func(map[string]string) error) error {
+	if compressed {
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	r := csv.NewReader(reader)
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for i := range header {
+		header[i] = ColumnName(header[i])
+	}
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		values := map[string]string{}
+		for i, value := range record {
+			values[header[i]] = value
+		}
+		if err := row(values); err != nil {
+			return err
+		}
+	}
+}
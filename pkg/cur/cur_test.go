@@ -0,0 +1,424 @@
+package cur
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+var update = flag.Bool("update", false, "rewrite the sample CUR exports in testdata")
+
+const (
+	csvExport     = "testdata/csv"
+	parquetExport = "testdata/parquet"
+	csvPeriod     = "cur/20210901-20211001"
+	parquetKey    = "cur/year=2021/month=9/cur-00001.snappy.parquet"
+)
+
+// sampleRow
+// is a line item of the sample exports
+type sampleRow struct {
+	date        time.Time
+	account     string
+	productCode string
+	productName string
+	usageType   string
+	resourceId  string
+	cost        float64
+}
+
+// sampleRows
+// returns two hourly line items of an instance and a daily line item of a bucket in two
+// accounts for four days, and a daily tax line item without product name or resource
+func sampleRows(scale float64) []sampleRow {
+	rows := []sampleRow{}
+	for day := 1; day <= 4; day++ {
+		for i, account := range []string{"111111111111", "222222222222"} {
+			for hour := 0; hour < 2; hour++ {
+				rows = append(rows, sampleRow{
+					date:        time.Date(2021, 9, day, hour, 0, 0, 0, time.UTC),
+					account:     account,
+					productCode: "AmazonEC2",
+					productName: "Amazon Elastic Compute Cloud",
+					usageType:   "BoxUsage:t3.micro",
+					resourceId:  fmt.Sprintf("i-%d", i+1),
+					cost:        scale * float64(day*(i+1)),
+				})
+			}
+			rows = append(rows, sampleRow{
+				date:        time.Date(2021, 9, day, 0, 0, 0, 0, time.UTC),
+				account:     account,
+				productCode: "AmazonS3",
+				productName: "Amazon Simple Storage Service",
+				usageType:   "TimedStorage-ByteHrs",
+				resourceId:  fmt.Sprintf("bucket-%d", i+1),
+				cost:        scale * 0.25,
+			})
+		}
+		rows = append(rows, sampleRow{
+			date:        time.Date(2021, 9, day, 0, 0, 0, 0, time.UTC),
+			account:     "111111111111",
+			productCode: "AmazonEC2",
+			usageType:   "",
+			cost:        scale * 0.5,
+		})
+	}
+	return rows
+}
+
+func sampleCsv(t *testing.T, rows []sampleRow) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	w := csv.NewWriter(gz)
+	w.Write([]string{"identity/LineItemId", "lineItem/UsageAccountId", "lineItem/UsageStartDate",
+		"lineItem/ProductCode", "lineItem/UsageType", "lineItem/ResourceId", "lineItem/UnblendedCost",
+		"product/ProductName", "resourceTags/user:Product"})
+	for i, row := range rows {
+		w.Write([]string{strconv.Itoa(i), row.account, row.date.Format(time.RFC3339), row.productCode,
+			row.usageType, row.resourceId, strconv.FormatFloat(row.cost, 'f', -1, 64), row.productName, ""})
+	}
+	w.Flush()
+	if err := gz.Close(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func sampleManifest(t *testing.T, assembly string) []byte {
+	data, err := json.Marshal(map[string]interface{}{
+		"assemblyId":    assembly,
+		"billingPeriod": map[string]string{"start": "20210901T000000.000Z", "end": "20211001T000000.000Z"},
+		"reportKeys":    []string{"reports/" + csvPeriod + "/" + assembly + "/cur-00001.csv.gz"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return data
+}
+
+func writeSample(t *testing.T, path string, data []byte) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+// updateSamples
+// writes a CSV export with a stale and a current assembly and a Parquet export of the rows
+func updateSamples(t *testing.T) {
+	for _, export := range []string{csvExport, parquetExport} {
+		if err := os.RemoveAll(export); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	period := filepath.Join(csvExport, csvPeriod)
+	writeSample(t, filepath.Join(period, "cur-Manifest.json"), sampleManifest(t, "assembly-2"))
+	for i, assembly := range []string{"assembly-1", "assembly-2"} {
+		// The stale assembly has twice the costs of the current one
+		writeSample(t, filepath.Join(period, assembly, "cur-Manifest.json"), sampleManifest(t, assembly))
+		writeSample(t, filepath.Join(period, assembly, "cur-00001.csv.gz"), sampleCsv(t, sampleRows(float64(2-i))))
+	}
+	writeSample(t, filepath.Join(parquetExport, parquetKey), sampleParquet(t, sampleRows(1)))
+}
+
+func TestColumnName(t *testing.T) {
+	for name, expected := range map[string]string{
+		"lineItem/UsageStartDate":    UsageStartDateColumn,
+		"lineItem/UsageAccountId":    UsageAccountIdColumn,
+		"product/ProductName":        ProductNameColumn,
+		"resourceTags/user:Product":  "resource_tags_user_product",
+		"line_item_unblended_cost":   UnblendedCostColumn,
+		"lineItem/NetUnblendedCost":  "line_item_net_unblended_cost",
+		"reservation/EffectiveCost ": "reservation_effective_cost",
+	} {
+		if actual := ColumnName(name); actual != expected {
+			t.Errorf("Output %q not equal to expected %q", actual, expected)
+		}
+	}
+}
+
+func checkLineItems(t *testing.T, items []LineItem) {
+	// Four days of two instances, two buckets and the tax
+	if len(items) != 20 {
+		t.Fatalf("Output %d line items not equal to expected 20", len(items))
+	}
+	expected := LineItem{
+		Date:    "2021-09-02",
+		Account: "222222222222",
+		Product: "Amazon Elastic Compute Cloud",
+		Entity:  "i-2",
+		Cost:    8,
+	}
+	found := false
+	total := 0.0
+	taxes := 0
+	for _, item := range items {
+		total += item.Cost
+		if item == expected {
+			found = true
+		}
+		// The tax line items have no product name, usage type or resource
+		if item.Product == "AmazonEC2" {
+			taxes++
+			if item.Entity != "" || item.Cost != 0.5 {
+				t.Errorf("Output %v not equal to a tax line item", item)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("Output %v does not contain expected %v", items, expected)
+	}
+	// 2 * (1+2+3+4) * (1+2) for the instances, 4 * 2 * 0.25 for the buckets and 4 * 0.5 for the tax
+	if total != 64 {
+		t.Errorf("Output total %v not equal to expected 64", total)
+	}
+	if taxes != 4 {
+		t.Errorf("Output %d tax line items not equal to expected 4", taxes)
+	}
+}
+
+func TestRead(t *testing.T) {
+	if *update {
+		updateSamples(t)
+	}
+
+	ctx := context.Background()
+	csvItems, err := Read(ctx, NewDirectorySource(csvExport), "lineItem/UnblendedCost")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkLineItems(t, csvItems)
+
+	parquetItems, err := Read(ctx, NewDirectorySource(parquetExport), UnblendedCostColumn)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkLineItems(t, parquetItems)
+	if fmt.Sprint(csvItems) != fmt.Sprint(parquetItems) {
+		t.Errorf("Output %v not equal to expected %v", parquetItems, csvItems)
+	}
+}
+
+func TestReadS3(t *testing.T) {
+	for _, export := range []string{csvExport, parquetExport} {
+		t.Run(filepath.Base(export), func(t *testing.T) {
+			testReadS3(t, export)
+		})
+	}
+}
+
+func testReadS3(t *testing.T, export string) {
+	keys, err := NewDirectorySource(export).List(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	objects := []string{}
+	for _, key := range keys {
+		objects = append(objects, "reports/"+key)
+	}
+
+	// A bucket listing two objects per page, the objects are served with ranges
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=key/") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.URL.Path == "/bucket" || r.URL.Path == "/bucket/" {
+			start, _ := strconv.Atoi(r.URL.Query().Get("continuation-token"))
+			var result struct {
+				XMLName  xml.Name `xml:"ListBucketResult"`
+				Contents []struct {
+					Key string `xml:"Key"`
+				} `xml:"Contents"`
+				IsTruncated           bool   `xml:"IsTruncated"`
+				NextContinuationToken string `xml:"NextContinuationToken"`
+			}
+			for i := start; i < len(objects) && i < start+2; i++ {
+				if strings.HasPrefix(objects[i], r.URL.Query().Get("prefix")) {
+					result.Contents = append(result.Contents, struct {
+						Key string `xml:"Key"`
+					}{Key: objects[i]})
+				}
+			}
+			if start+2 < len(objects) {
+				result.IsTruncated = true
+				result.NextContinuationToken = strconv.Itoa(start + 2)
+			}
+			xml.NewEncoder(w).Encode(result)
+			return
+		}
+		data, err := ioutil.ReadFile(filepath.Join(export, strings.TrimPrefix(r.URL.Path, "/bucket/reports/")))
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(data))
+	}))
+	defer server.Close()
+
+	cfg := aws.Config{Region: "us-east-1", Credentials: credentials.NewStaticCredentialsProvider("key", "secret", "")}
+	source := NewS3Source(cfg, server.URL, "bucket", "reports")
+	listed, err := source.List(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if fmt.Sprint(listed) != fmt.Sprint(keys) {
+		t.Errorf("Output %v not equal to expected %v", listed, keys)
+	}
+	items, err := Read(context.Background(), source, UnblendedCostColumn)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkLineItems(t, items)
+}
+
+func TestReadParquetErrors(t *testing.T) {
+	data := sampleParquet(t, sampleRows(1))
+	corrupt := func(offset int, value byte) []byte {
+		corrupt := append([]byte{}, data...)
+		for i := offset; i < offset+16; i++ {
+			corrupt[i] = value
+		}
+		return corrupt
+	}
+	var tests = []struct {
+		name string
+		data []byte
+	}{
+		{name: "magic only", data: []byte("PAR1")},
+		{name: "truncated", data: data[:len(data)/2]},
+		// The file starts with the magic and the header of the first page of the line item ids
+		{name: "corrupt page header", data: corrupt(4, 0xff)},
+		{name: "corrupt footer", data: corrupt(len(data)-40, 0xff)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := readParquet(bytes.NewReader(test.data), int64(len(test.data)), map[string]bool{
+				"identity_line_item_id": true,
+				UsageAccountIdColumn:    true,
+				UnblendedCostColumn:     true,
+			}, func(map[string]string) error { return nil })
+			if err == nil {
+				t.Errorf("Expected an error for a %s file", test.name)
+			}
+		})
+	}
+}
+
+func TestReadParquetNestedColumns(t *testing.T) {
+	// The sample export has the map columns of CUR 2.0
+	data := sampleParquet(t, sampleRows(1))
+	var accounts []string
+	err := readParquet(bytes.NewReader(data), int64(len(data)), map[string]bool{UsageAccountIdColumn: true, "resource_tags": true}, func(row map[string]string) error {
+		if _, ok := row["resource_tags"]; ok {
+			t.Errorf("Unexpected value of a nested column: %v", row)
+		}
+		accounts = append(accounts, row[UsageAccountIdColumn])
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(accounts) != len(sampleRows(1)) || accounts[0] != "111111111111" {
+		t.Errorf("Output %v not equal to the sample accounts", accounts)
+	}
+}
+
+func TestOpenFile(t *testing.T) {
+	info, err := os.Stat(filepath.Join(parquetExport, parquetKey))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// A source without random access is copied to a temporary file
+	source := struct{ Source }{NewDirectorySource(parquetExport)}
+	file, err := openFile(context.Background(), source, parquetKey)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	name := file.(*tempFile).Name()
+	if file.Size() != info.Size() {
+		t.Errorf("Output size %d not equal to expected %d", file.Size(), info.Size())
+	}
+	if err := file.Close(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := os.Stat(name); !os.IsNotExist(err) {
+		t.Errorf("Expected the temporary file %s to be removed", name)
+	}
+}
+
+// parquetRow
+// is a line item of the sample Parquet export, with the map columns of CUR 2.0
+type parquetRow struct {
+	LineItemId   string            `parquet:"name=identity_line_item_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Date         *int64            `parquet:"name=line_item_usage_start_date, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL"`
+	Account      *string           `parquet:"name=line_item_usage_account_id, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY, repetitiontype=OPTIONAL"`
+	ProductCode  *string           `parquet:"name=line_item_product_code, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY, repetitiontype=OPTIONAL"`
+	UsageType    *string           `parquet:"name=line_item_usage_type, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY, repetitiontype=OPTIONAL"`
+	ResourceId   *string           `parquet:"name=line_item_resource_id, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"`
+	Cost         *float64          `parquet:"name=line_item_unblended_cost, type=DOUBLE, repetitiontype=OPTIONAL"`
+	ProductName  *string           `parquet:"name=product_product_name, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY, repetitiontype=OPTIONAL"`
+	ResourceTags map[string]string `parquet:"name=resource_tags, type=MAP, convertedtype=MAP, keytype=BYTE_ARRAY, keyconvertedtype=UTF8, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8, repetitiontype=OPTIONAL"`
+}
+
+// sampleParquet
+// returns a Parquet file of the rows sorted by account, as in the exports for Athena
+func sampleParquet(t *testing.T, rows []sampleRow) []byte {
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].account < rows[j].account })
+	optional := func(value string) *string {
+		if value == "" {
+			return nil
+		}
+		return &value
+	}
+
+	var file bytes.Buffer
+	w, err := writer.NewParquetWriterFromWriter(&file, new(parquetRow), 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for i, row := range rows {
+		date, cost := row.date.UnixNano()/int64(time.Millisecond), row.cost
+		record := parquetRow{
+			LineItemId:  strconv.Itoa(i),
+			Date:        &date,
+			Account:     optional(row.account),
+			ProductCode: optional(row.productCode),
+			UsageType:   optional(row.usageType),
+			ResourceId:  optional(row.resourceId),
+			Cost:        &cost,
+			ProductName: optional(row.productName),
+		}
+		if row.resourceId != "" {
+			record.ResourceTags = map[string]string{"user_product": "cost-insights"}
+		}
+		if err := w.Write(record); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if err := w.WriteStop(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return file.Bytes()
+}
@@ -0,0 +1,196 @@
+package cur
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/xitongsys/parquet-go/common"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/reader"
+	"github.com/xitongsys/parquet-go/source"
+	"github.com/xitongsys/parquet-go/types"
+)
+
+// parquetBatchRows is the number of rows read from every column at a time
+const parquetBatchRows = 4096
+
+// parquetFile
+// is a read only source.ParquetFile of a file opened for random access, every Open returns a
+// reader of its own for the column chunks read side by side
+type parquetFile struct {
+	*io.SectionReader
+	file io.ReaderAt
+	size int64
+}
+
+func newParquetFile(file io.ReaderAt, size int64) *parquetFile {
+	return &parquetFile{SectionReader: io.NewSectionReader(file, 0, size), file: file, size: size}
+}
+
+func (f *parquetFile) Open(name string) (source.ParquetFile, error) {
+	if name != "" {
+		return nil, fmt.Errorf("parquet column chunk in another file %s", name)
+	}
+	return newParquetFile(f.file, f.size), nil
+}
+
+func (f *parquetFile) Create(name string) (source.ParquetFile, error) {
+	return nil, errors.New("parquet file is read only")
+}
+
+func (f *parquetFile) Write(p []byte) (int, error) {
+	return 0, errors.New("parquet file is read only")
+}
+
+func (f *parquetFile) Close() error {
+	return nil
+}
+
+// parquetColumn
+// is a top level leaf column of the Parquet schema and the buffer of its pages
+type parquetColumn struct {
+	name    string
+	element *parquet.SchemaElement
+	buffer  *reader.ColumnBufferType
+}
+
+// readParquet
+// calls row with the values of the columns of every row in a Parquet file of the given size,
+// the values are formatted as in a CSV file and a null value is an empty string. Only the
+// chunks of the columns are read, a batch of rows at a time. Nested columns, such as the map
+// columns of CUR 2.0, are skipped.
+func readParquet(file io.ReaderAt, size int64, columns map[string]bool, row func(map[string]string) error) (err error) {
+	// The decoder of the pages panics on some corrupt pages
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("corrupt parquet file: %v", r)
+		}
+	}()
+
+	pr, err := reader.NewParquetColumnReader(newParquetFile(file, size), 1)
+	if err != nil {
+		return err
+	}
+	schema := pr.SchemaHandler
+	selected := []*parquetColumn{}
+	for i, element := range schema.SchemaElements {
+		path := common.StrToPath(schema.IndexMap[int32(i)])
+		if len(path) != 2 || element.GetNumChildren() > 0 || !columns[schema.Infos[i].ExName] {
+			continue
+		}
+		column := &parquetColumn{name: schema.Infos[i].ExName, element: element}
+		if column.buffer, err = reader.NewColumnBuffer(pr.PFile, pr.Footer, schema, schema.IndexMap[int32(i)]); err != nil {
+			return fmt.Errorf("parquet column %s: %v", column.name, err)
+		}
+		selected = append(selected, column)
+	}
+
+	numRows := pr.GetNumRows()
+	values := make([][]interface{}, len(selected))
+	for read := int64(0); read < numRows; read += parquetBatchRows {
+		batch := numRows - read
+		if batch > parquetBatchRows {
+			batch = parquetBatchRows
+		}
+		for c, column := range selected {
+			if values[c], err = column.read(batch); err != nil {
+				return fmt.Errorf("parquet column %s: %v", column.name, err)
+			}
+		}
+		for i := int64(0); i < batch; i++ {
+			r := map[string]string{}
+			for c, column := range selected {
+				r[column.name] = parquetValue(column.element, values[c][i])
+			}
+			if err := row(r); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// read
+// returns the next num values of the column, nil for a null value
+func (c *parquetColumn) read(num int64) ([]interface{}, error) {
+	for c.buffer.DataTableNumRows < num {
+		if err := c.buffer.ReadPage(); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	table, read := c.buffer.ReadRows(num)
+	if read != num || int64(len(table.Values)) != num {
+		return nil, fmt.Errorf("%d values for %d rows", len(table.Values), num)
+	}
+	return table.Values, nil
+}
+
+// parquetValue
+// formats a value of a column as in a CSV export, dates and timestamps in RFC 3339
+func parquetValue(element *parquet.SchemaElement, value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case bool:
+		return strconv.FormatBool(v)
+	case int32:
+		if parquetDate(element) {
+			return time.Unix(int64(v)*86400, 0).UTC().Format(time.RFC3339)
+		}
+		return strconv.FormatInt(int64(v), 10)
+	case int64:
+		if unit := parquetTimeUnit(element); unit != 0 {
+			return time.Unix(0, v*int64(unit)).UTC().Format(time.RFC3339)
+		}
+		return strconv.FormatInt(v, 10)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		if element.GetType() == parquet.Type_INT96 {
+			return types.INT96ToTime(v).UTC().Format(time.RFC3339)
+		}
+		return v
+	}
+	return fmt.Sprint(value)
+}
+
+// parquetDate
+// returns whether the column is a date
+func parquetDate(element *parquet.SchemaElement) bool {
+	if element.IsSetLogicalType() && element.LogicalType.IsSetDATE() {
+		return true
+	}
+	return element.IsSetConvertedType() && element.GetConvertedType() == parquet.ConvertedType_DATE
+}
+
+// parquetTimeUnit
+// returns the unit of a timestamp column, zero when the column is not a timestamp
+func parquetTimeUnit(element *parquet.SchemaElement) time.Duration {
+	if element.IsSetLogicalType() && element.LogicalType.IsSetTIMESTAMP() {
+		unit := element.LogicalType.TIMESTAMP.GetUnit()
+		switch {
+		case unit.IsSetMILLIS():
+			return time.Millisecond
+		case unit.IsSetMICROS():
+			return time.Microsecond
+		case unit.IsSetNANOS():
+			return time.Nanosecond
+		}
+	}
+	if element.IsSetConvertedType() {
+		switch element.GetConvertedType() {
+		case parquet.ConvertedType_TIMESTAMP_MILLIS:
+			return time.Millisecond
+		case parquet.ConvertedType_TIMESTAMP_MICROS:
+			return time.Microsecond
+		}
+	}
+	return 0
+}
@@ -0,0 +1,290 @@
+package cur
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// Source
+// lists and opens the files of a Cost and Usage Report export
+type Source interface {
+	// List returns the keys of the files in the export sorted by key
+	List(ctx context.Context) ([]string, error)
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+}
+
+// File
+// is a file of an export opened for random access
+type File interface {
+	io.ReaderAt
+	io.Closer
+	Size() int64
+}
+
+// fileSource
+// is a source that opens the files of the export for random access, such as the Parquet files
+// that are read from their footer
+type fileSource interface {
+	OpenFile(ctx context.Context, key string) (File, error)
+}
+
+// openFile
+// opens the file with key for random access, the file is copied to a temporary file when the
+// source does not support it
+func openFile(ctx context.Context, source Source, key string) (File, error) {
+	if s, ok := source.(fileSource); ok {
+		return s.OpenFile(ctx, key)
+	}
+	reader, err := source.Open(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	file, err := ioutil.TempFile("", "cur-*"+filepath.Ext(key))
+	if err != nil {
+		return nil, err
+	}
+	temp := &tempFile{localFile{File: file}}
+	if temp.size, err = io.Copy(file, reader); err != nil {
+		temp.Close()
+		return nil, err
+	}
+	return temp, nil
+}
+
+// localFile
+// is a File of the local file system
+type localFile struct {
+	*os.File
+	size int64
+}
+
+func (f *localFile) Size() int64 {
+	return f.size
+}
+
+// tempFile
+// is a local copy of a file that is removed on close
+type tempFile struct {
+	localFile
+}
+
+func (f *tempFile) Close() error {
+	err := f.File.Close()
+	if removeErr := os.Remove(f.Name()); err == nil {
+		err = removeErr
+	}
+	return err
+}
+
+// NewSource
+// returns the source of the export at path, a local directory or an s3://bucket/prefix URL.
+// An S3 source uses the default AWS configuration, endpoint selects an S3-compatible store.
+func NewSource(path string, endpoint string, region string) (Source, error) {
+	if !strings.HasPrefix(path, "s3://") {
+		return NewDirectorySource(path), nil
+	}
+	location, err := url.Parse(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := config.LoadDefaultConfig(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+	if region != "" {
+		cfg.Region = region
+	}
+	return NewS3Source(cfg, endpoint, location.Host, strings.TrimPrefix(location.Path, "/")), nil
+}
+
+// directorySource
+// reads the export from a local directory
+type directorySource struct {
+	root string
+}
+
+// NewDirectorySource
+// returns a source of the files under the directory root
+func NewDirectorySource(root string) Source {
+	return &directorySource{root: root}
+}
+
+func (s directorySource) List(ctx context.Context) ([]string, error) {
+	keys := []string{}
+	err := filepath.Walk(s.root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		key, err := filepath.Rel(s.root, path)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, filepath.ToSlash(key))
+		return nil
+	})
+	sort.Strings(keys)
+	return keys, err
+}
+
+func (s directorySource) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	return os.Open(filepath.Join(s.root, filepath.FromSlash(key)))
+}
+
+func (s directorySource) OpenFile(ctx context.Context, key string) (File, error) {
+	file, err := os.Open(filepath.Join(s.root, filepath.FromSlash(key)))
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &localFile{File: file, size: info.Size()}, nil
+}
+
+// s3Source
+// reads the export from an S3 bucket, or from an S3-compatible store such as MinIO with
+// path-style requests to its endpoint
+type s3Source struct {
+	client *s3.Client
+	bucket string
+	prefix string
+}
+
+// NewS3Source
+// returns a source of the objects under prefix in the bucket. Without an endpoint the
+// regional AWS endpoint of the configuration is used.
+func NewS3Source(cfg aws.Config, endpoint string, bucket string, prefix string) Source {
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		if endpoint != "" {
+			o.EndpointResolver = s3.EndpointResolverFromURL(strings.TrimSuffix(endpoint, "/"))
+			o.UsePathStyle = true
+		}
+	})
+	return &s3Source{client: client, bucket: bucket, prefix: prefix}
+}
+
+func (s s3Source) List(ctx context.Context) ([]string, error) {
+	keys := []string{}
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{Bucket: &s.bucket, Prefix: &s.prefix})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, object := range page.Contents {
+			if object.Key == nil || strings.HasSuffix(*object.Key, "/") {
+				continue
+			}
+			keys = append(keys, strings.TrimPrefix(strings.TrimPrefix(*object.Key, s.prefix), "/"))
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (s s3Source) object(key string) string {
+	if s.prefix == "" {
+		return key
+	}
+	return strings.TrimSuffix(s.prefix, "/") + "/" + key
+}
+
+func (s s3Source) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	object := s.object(key)
+	resp, err := s.client.GetObject(ctx, &s3.GetObjectInput{Bucket: &s.bucket, Key: &object})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (s s3Source) OpenFile(ctx context.Context, key string) (File, error) {
+	object := s.object(key)
+	resp, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{Bucket: &s.bucket, Key: &object})
+	if err != nil {
+		return nil, err
+	}
+	return &s3File{ctx: ctx, client: s.client, bucket: s.bucket, key: object, size: resp.ContentLength}, nil
+}
+
+// s3BlockSize is the least number of bytes of an object fetched by a ranged read
+const s3BlockSize = 1 << 20
+
+// s3File
+// reads an object with ranged requests. The last block read is kept, as the decoders read the
+// metadata of a file a few bytes at a time.
+type s3File struct {
+	ctx    context.Context
+	client *s3.Client
+	bucket string
+	key    string
+	size   int64
+
+	mu     sync.Mutex
+	offset int64
+	block  []byte
+}
+
+func (f *s3File) Size() int64 {
+	return f.size
+}
+
+func (f *s3File) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, fmt.Errorf("s3 object %s: negative offset", f.key)
+	}
+	if off >= f.size {
+		return 0, io.EOF
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if off < f.offset || off+int64(len(p)) > f.offset+int64(len(f.block)) {
+		end := off + int64(len(p))
+		if end < off+s3BlockSize {
+			end = off + s3BlockSize
+		}
+		if end > f.size {
+			end = f.size
+		}
+		resp, err := f.client.GetObject(f.ctx, &s3.GetObjectInput{
+			Bucket: &f.bucket,
+			Key:    &f.key,
+			Range:  aws.String(fmt.Sprintf("bytes=%d-%d", off, end-1)),
+		})
+		if err != nil {
+			return 0, err
+		}
+		defer resp.Body.Close()
+		block := make([]byte, end-off)
+		if _, err := io.ReadFull(resp.Body, block); err != nil {
+			return 0, err
+		}
+		f.offset, f.block = off, block
+	}
+
+	n := copy(p, f.block[off-f.offset:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (f *s3File) Close() error {
+	return nil
+}
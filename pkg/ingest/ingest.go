@@ -0,0 +1,245 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	ceTypes "github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+
+	"github.com/seizadi/cost-insights-backend/pkg/store"
+	"github.com/seizadi/cost-insights-backend/pkg/types"
+)
+
+// DailyCostCheckpoint is the checkpoint of the last day ingested into the daily costs
+const DailyCostCheckpoint = "daily_cost"
+
+// Options
+// of an ingestion run. The costs from Start (inclusive) to End (exclusive) are fetched in
+// chunks of ChunkDays days. With Resume a run starts from the checkpoint of the last run
+// instead, when it is later than Start, and fetches the trailing RestateDays days again as
+// AWS restates the recent costs.
+type Options struct {
+	Start       time.Time
+	End         time.Time
+	Metrics     []string
+	Tag         string
+	ChunkDays   int
+	RestateDays int
+	Resume      bool
+}
+
+// NewOptions
+// returns the options of the ingest.* configuration. Without ingest.start the run resumes from
+// the checkpoint, or starts on the first day of the month 12 months ago, the oldest month of the
+// 13 months Cost Explorer retains, on the first run. An explicit ingest.start backfills from that
+// day. Without ingest.end the run ends today.
+func NewOptions(now time.Time) (Options, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	options := Options{
+		Start:       time.Date(today.Year(), today.Month()-12, 1, 0, 0, 0, 0, time.UTC),
+		End:         today,
+		Metrics:     []string{viper.GetString("cost.aws.datasets")},
+		Tag:         viper.GetString("ingest.tag"),
+		ChunkDays:   viper.GetInt("ingest.chunk_days"),
+		RestateDays: viper.GetInt("ingest.restate_days"),
+		Resume:      true,
+	}
+
+	var err error
+	if start := viper.GetString("ingest.start"); start != "" {
+		options.Resume = false
+		if options.Start, err = time.Parse(types.DEFAULT_DATE_FORMAT, start); err != nil {
+			return options, err
+		}
+	}
+	if end := viper.GetString("ingest.end"); end != "" {
+		if options.End, err = time.Parse(types.DEFAULT_DATE_FORMAT, end); err != nil {
+			return options, err
+		}
+	}
+	return options, nil
+}
+
+// CostAndUsageClient
+// is the Cost Explorer query of the daily costs, the only query of the ingester. It is satisfied
+// by *costexplorer.Client and by the svc.CostExplorerClient of the AWS provider.
+type CostAndUsageClient interface {
+	GetCostAndUsage(ctx context.Context, params *costexplorer.GetCostAndUsageInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostAndUsageOutput, error)
+}
+
+// Ingester
+// copies the daily costs of Cost Explorer into the store. Every chunk replaces the costs of
+// its days, which makes a run idempotent, and then advances the checkpoint.
+type Ingester struct {
+	client CostAndUsageClient
+	store  store.Store
+	logger *logrus.Logger
+}
+
+// NewIngester
+// returns an ingester of the costs queried with client into s
+func NewIngester(client CostAndUsageClient, s store.Store, logger *logrus.Logger) *Ingester {
+	return &Ingester{client: client, store: s, logger: logger}
+}
+
+// Run
+// ingests the daily costs of the options
+func (i Ingester) Run(ctx context.Context, options Options) error {
+	if options.ChunkDays <= 0 {
+		return errors.New("ingest chunk days must be positive")
+	}
+
+	start := options.Start
+	checkpoint, err := i.store.Checkpoint(ctx, DailyCostCheckpoint)
+	if err != nil {
+		return err
+	}
+	if options.Resume && checkpoint != "" {
+		last, err := time.Parse(types.DEFAULT_DATE_FORMAT, checkpoint)
+		if err != nil {
+			return err
+		}
+		if resume := last.AddDate(0, 0, -options.RestateDays); resume.After(start) {
+			start = resume
+		}
+	}
+
+	for chunkStart := start; chunkStart.Before(options.End); chunkStart = chunkStart.AddDate(0, 0, options.ChunkDays) {
+		chunkEnd := chunkStart.AddDate(0, 0, options.ChunkDays)
+		if chunkEnd.After(options.End) {
+			chunkEnd = options.End
+		}
+		startDate := chunkStart.Format(types.DEFAULT_DATE_FORMAT)
+		endDate := chunkEnd.Format(types.DEFAULT_DATE_FORMAT)
+
+		for _, metric := range options.Metrics {
+			costs, err := i.dailyCosts(ctx, startDate, endDate, metric, options.Tag)
+			if err != nil {
+				return err
+			}
+			if err := i.store.Replace(ctx, startDate, endDate, metric, costs); err != nil {
+				return err
+			}
+			i.logger.Infof("ingested %d %s costs from %s to %s", len(costs), metric, startDate, endDate)
+		}
+		// A backfill of earlier days does not move the checkpoint back
+		if endDate > checkpoint {
+			if err := i.store.SaveCheckpoint(ctx, DailyCostCheckpoint, endDate); err != nil {
+				return err
+			}
+			checkpoint = endDate
+		}
+	}
+	return nil
+}
+
+// dailyCosts
+// queries the costs by account and service. Cost Explorer groups by two keys at most, so with a
+// tag the costs of every service are queried by account and tag.
+func (i Ingester) dailyCosts(ctx context.Context, start string, end string, metric string, tag string) ([]store.DailyCost, error) {
+	accountKey := string(ceTypes.DimensionLinkedAccount)
+	serviceKey := string(ceTypes.DimensionService)
+	input := &costexplorer.GetCostAndUsageInput{
+		TimePeriod:  &ceTypes.DateInterval{Start: &start, End: &end},
+		Metrics:     []string{metric},
+		Granularity: ceTypes.GranularityDaily,
+		GroupBy: []ceTypes.GroupDefinition{
+			{Key: &accountKey, Type: ceTypes.GroupDefinitionTypeDimension},
+			{Key: &serviceKey, Type: ceTypes.GroupDefinitionTypeDimension},
+		},
+	}
+	costs, err := i.query(ctx, input, metric, func(cost *store.DailyCost, keys []string) {
+		cost.Account, cost.Service = keys[0], keys[1]
+	})
+	if err != nil || tag == "" {
+		return costs, err
+	}
+
+	services := []string{}
+	seen := map[string]bool{}
+	for _, cost := range costs {
+		if !seen[cost.Service] {
+			seen[cost.Service] = true
+			services = append(services, cost.Service)
+		}
+	}
+	sort.Strings(services)
+	costs = []store.DailyCost{}
+	for _, service := range services {
+		input.GroupBy = []ceTypes.GroupDefinition{
+			{Key: &accountKey, Type: ceTypes.GroupDefinitionTypeDimension},
+			{Key: &tag, Type: ceTypes.GroupDefinitionTypeTag},
+		}
+		input.Filter = &ceTypes.Expression{
+			Dimensions: &ceTypes.DimensionValues{Key: ceTypes.DimensionService, Values: []string{service}},
+		}
+		serviceCosts, err := i.query(ctx, input, metric, func(cost *store.DailyCost, keys []string) {
+			cost.Account, cost.Service, cost.Tag = keys[0], service, keys[1]
+		})
+		if err != nil {
+			return nil, err
+		}
+		costs = append(costs, serviceCosts...)
+	}
+	return costs, nil
+}
+
+// query
+// pages through the results of the input and returns a daily cost for every group, set sets
+// the columns of the cost from the group keys
+func (i Ingester) query(ctx context.Context, input *costexplorer.GetCostAndUsageInput, metric string, set func(cost *store.DailyCost, keys []string)) ([]store.DailyCost, error) {
+	costs := []store.DailyCost{}
+	params := *input
+	for {
+		resp, err := i.client.GetCostAndUsage(ctx, &params)
+		if err != nil {
+			return nil, err
+		}
+		for _, result := range resp.ResultsByTime {
+			for _, group := range result.Groups {
+				value, ok := group.Metrics[metric]
+				if !ok || value.Amount == nil {
+					continue
+				}
+				amount, err := strconv.ParseFloat(*value.Amount, 64)
+				if err != nil {
+					return nil, err
+				}
+				cost := store.DailyCost{Date: *result.TimePeriod.Start, Metric: metric, Amount: amount}
+				set(&cost, group.Keys)
+				costs = append(costs, cost)
+			}
+		}
+		if resp.NextPageToken == nil || *resp.NextPageToken == "" {
+			return costs, nil
+		}
+		params.NextPageToken = resp.NextPageToken
+	}
+}
+
+// Serve
+// runs the ingestion every interval until the context is done
+func (i Ingester) Serve(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		options, err := NewOptions(time.Now())
+		if err != nil {
+			return err
+		}
+		if err := i.Run(ctx, options); err != nil {
+			i.logger.Errorf("ingestion failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	ceTypes "github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+
+	"github.com/seizadi/cost-insights-backend/pkg/store"
+	"github.com/seizadi/cost-insights-backend/pkg/types"
+)
+
+const testMetric = "NetAmortizedCost"
+
+// fakeCeClient
+// returns one day per page for two accounts and two services, the cost of every service is
+// split evenly between the untagged and the tagged cost. The cost of every group is restated
+// by adding restatement to it.
+type fakeCeClient struct {
+	restatement float64
+	queries     []string
+}
+
+var (
+	fakeAccounts = []string{"111111111111", "222222222222"}
+	fakeServices = []string{"AWS Lambda", "Amazon Simple Storage Service"}
+	fakeTags     = []string{"Product$", "Product$service-a"}
+)
+
+func (c *fakeCeClient) GetCostAndUsage(ctx context.Context, params *costexplorer.GetCostAndUsageInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostAndUsageOutput, error) {
+	date := *params.TimePeriod.Start
+	if params.NextPageToken != nil {
+		date = *params.NextPageToken
+	}
+	c.queries = append(c.queries, date)
+	day, err := time.Parse(types.DEFAULT_DATE_FORMAT, date)
+	if err != nil {
+		return nil, err
+	}
+	next := day.AddDate(0, 0, 1).Format(types.DEFAULT_DATE_FORMAT)
+
+	result := ceTypes.ResultByTime{TimePeriod: &ceTypes.DateInterval{Start: &date, End: &next}}
+	group := func(amount float64, keys ...string) {
+		value := strconv.FormatFloat(amount, 'f', -1, 64)
+		result.Groups = append(result.Groups, ceTypes.Group{
+			Keys:    keys,
+			Metrics: map[string]ceTypes.MetricValue{testMetric: {Amount: &value}},
+		})
+	}
+	for i, account := range fakeAccounts {
+		if params.Filter == nil {
+			for j, service := range fakeServices {
+				group(float64(200*i+20*j+day.Day())+c.restatement, account, service)
+			}
+			continue
+		}
+		j := 0
+		if params.Filter.Dimensions.Values[0] == fakeServices[1] {
+			j = 1
+		}
+		for _, tag := range fakeTags {
+			group((float64(200*i+20*j+day.Day())+c.restatement)/2, account, tag)
+		}
+	}
+
+	resp := &costexplorer.GetCostAndUsageOutput{ResultsByTime: []ceTypes.ResultByTime{result}}
+	if next < *params.TimePeriod.End {
+		resp.NextPageToken = &next
+	}
+	return resp, nil
+}
+
+func newTestIngester(t *testing.T, client *fakeCeClient) (*Ingester, store.Store) {
+	if !store.SqliteSupported {
+		t.Skip("the SQLite driver needs cgo")
+	}
+	s, err := store.Open(store.SqliteStore, ":memory:")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+	return NewIngester(client, s, logger), s
+}
+
+func testOptions(tag string) Options {
+	return Options{
+		Start:       time.Date(2021, 9, 1, 0, 0, 0, 0, time.UTC),
+		End:         time.Date(2021, 9, 11, 0, 0, 0, 0, time.UTC),
+		Metrics:     []string{testMetric},
+		Tag:         tag,
+		ChunkDays:   4,
+		RestateDays: 3,
+		Resume:      true,
+	}
+}
+
+// totals returns the total cost of every day
+func totals(t *testing.T, s store.Store) map[string]float64 {
+	costs, err := s.DailyCosts(context.Background(), store.Query{Start: "2021-09-01", End: "2021-09-11", Metric: testMetric})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	totals := map[string]float64{}
+	for _, cost := range costs {
+		totals[cost.Date] += cost.Amount
+	}
+	return totals
+}
+
+func TestIngesterRun(t *testing.T) {
+	client := &fakeCeClient{}
+	ingester, s := newTestIngester(t, client)
+	defer s.Close()
+	ctx := context.Background()
+
+	if err := ingester.Run(ctx, testOptions("")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(client.queries) != 10 {
+		t.Errorf("Output %d pages not equal to expected 10", len(client.queries))
+	}
+	if checkpoint, _ := s.Checkpoint(ctx, DailyCostCheckpoint); checkpoint != "2021-09-11" {
+		t.Errorf("Output checkpoint %q not equal to expected 2021-09-11", checkpoint)
+	}
+	expected := totals(t, s)
+	if len(expected) != 10 || expected["2021-09-01"] != 4*1+(0+20+200+220) {
+		t.Errorf("Output %v not equal to the costs of 10 days", expected)
+	}
+
+	// A run resumes from the checkpoint and restates the trailing days
+	client.queries = nil
+	client.restatement = 1
+	if err := ingester.Run(ctx, testOptions("")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if fmt.Sprint(client.queries) != "[2021-09-08 2021-09-09 2021-09-10]" {
+		t.Errorf("Output %v not equal to the restated days", client.queries)
+	}
+	actual := totals(t, s)
+	for date, amount := range expected {
+		if date >= "2021-09-08" {
+			amount += 4
+		}
+		if actual[date] != amount {
+			t.Errorf("Output %s %v not equal to expected %v", date, actual[date], amount)
+		}
+	}
+}
+
+func TestIngesterRunBackfill(t *testing.T) {
+	client := &fakeCeClient{}
+	ingester, s := newTestIngester(t, client)
+	defer s.Close()
+	ctx := context.Background()
+
+	if err := s.SaveCheckpoint(ctx, DailyCostCheckpoint, "2021-09-11"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// A run resumes from the checkpoint
+	if err := ingester.Run(ctx, testOptions("")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(client.queries) != 3 {
+		t.Errorf("Output %v not equal to the restated days", client.queries)
+	}
+
+	// An explicit start backfills the days before the checkpoint
+	client.queries = nil
+	options := testOptions("")
+	options.Resume = false
+	if err := ingester.Run(ctx, options); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(client.queries) != 10 || client.queries[0] != "2021-09-01" {
+		t.Errorf("Output %v not equal to the 10 days of the backfill", client.queries)
+	}
+	if actual := totals(t, s); len(actual) != 10 {
+		t.Errorf("Output %v not equal to the costs of 10 days", actual)
+	}
+
+	// A backfill of earlier days keeps the checkpoint
+	client.queries = nil
+	options.Start = time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC)
+	options.End = time.Date(2021, 8, 5, 0, 0, 0, 0, time.UTC)
+	if err := ingester.Run(ctx, options); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if checkpoint, _ := s.Checkpoint(ctx, DailyCostCheckpoint); checkpoint != "2021-09-11" {
+		t.Errorf("Output checkpoint %q not equal to expected 2021-09-11", checkpoint)
+	}
+}
+
+func TestNewOptions(t *testing.T) {
+	defer viper.Reset()
+	now := time.Date(2021, 10, 15, 12, 0, 0, 0, time.UTC)
+
+	options, err := NewOptions(now)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !options.Resume || options.Start.Format(types.DEFAULT_DATE_FORMAT) != "2020-10-01" {
+		t.Errorf("Output %v, %v not equal to a resumed run from 2020-10-01", options.Resume, options.Start)
+	}
+
+	viper.Set("ingest.start", "2021-01-01")
+	options, err = NewOptions(now)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if options.Resume || options.Start.Format(types.DEFAULT_DATE_FORMAT) != "2021-01-01" {
+		t.Errorf("Output %v, %v not equal to a backfill from 2021-01-01", options.Resume, options.Start)
+	}
+}
+
+func TestIngesterRunTag(t *testing.T) {
+	client := &fakeCeClient{}
+	ingester, s := newTestIngester(t, client)
+	defer s.Close()
+
+	if err := ingester.Run(context.Background(), testOptions("Product")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	costs, err := s.DailyCosts(context.Background(), store.Query{Start: "2021-09-01", End: "2021-09-02", Metric: testMetric})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// Two accounts, two services and two tag values
+	if len(costs) != 8 {
+		t.Fatalf("Output %v not equal to expected 8 costs", costs)
+	}
+	expected := store.DailyCost{Date: "2021-09-01", Account: "111111111111", Service: "AWS Lambda", Tag: "Product$service-a", Metric: testMetric, Amount: 0.5}
+	if costs[1] != expected {
+		t.Errorf("Output %v not equal to expected %v", costs[1], expected)
+	}
+	if total := totals(t, s)["2021-09-01"]; total != 4*1+(0+20+200+220) {
+		t.Errorf("Output total %v not equal to the untagged total", total)
+	}
+}
@@ -541,11 +541,13 @@ func (m *ProductCost) GetAggregation() []*DateAggregation {
 }
 
 type ProjectCost struct {
-	Id                   string             `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Aggregation          []*DateAggregation `protobuf:"bytes,2,rep,name=aggregation,proto3" json:"aggregation,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
-	XXX_unrecognized     []byte             `json:"-"`
-	XXX_sizecache        int32              `json:"-"`
+	Id          string             `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Aggregation []*DateAggregation `protobuf:"bytes,2,rep,name=aggregation,proto3" json:"aggregation,omitempty"`
+	// The display name of the project, the account name of an AWS account id
+	Name                 string   `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *ProjectCost) Reset()         { *m = ProjectCost{} }
@@ -587,6 +589,13 @@ func (m *ProjectCost) GetAggregation() []*DateAggregation {
 	return nil
 }
 
+func (m *ProjectCost) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
 type GroupedCosts struct {
 	Product              []*ProductCost `protobuf:"bytes,1,rep,name=product,proto3" json:"product,omitempty"`
 	Project              []*ProjectCost `protobuf:"bytes,2,rep,name=project,proto3" json:"project,omitempty"`
@@ -1168,24 +1177,46 @@ func (m *Record) GetProduct() []*Entity {
 
 // TODO - Eliminate camel-case paramters
 type Entity struct {
-	Type                 string           `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
-	Id                   string           `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
-	Aggregation          []float64        `protobuf:"fixed64,3,rep,packed,name=aggregation,proto3" json:"aggregation,omitempty"`
-	Entities             *Record          `protobuf:"bytes,4,opt,name=entities,proto3" json:"entities,omitempty"`
-	Change               *ChangeStatistic `protobuf:"bytes,5,opt,name=change,proto3" json:"change,omitempty"`
-	StartDate            string           `protobuf:"bytes,6,opt,name=startDate,proto3" json:"startDate,omitempty"`
-	EndDate              string           `protobuf:"bytes,7,opt,name=endDate,proto3" json:"endDate,omitempty"`
-	Project              string           `protobuf:"bytes,8,opt,name=project,proto3" json:"project,omitempty"`
-	PeriodStart          string           `protobuf:"bytes,9,opt,name=periodStart,proto3" json:"periodStart,omitempty"`
-	PeriodEnd            string           `protobuf:"bytes,10,opt,name=periodEnd,proto3" json:"periodEnd,omitempty"`
-	LabeledCost          float64          `protobuf:"fixed64,11,opt,name=labeledCost,proto3" json:"labeledCost,omitempty"`
-	UnlabeledCost        float64          `protobuf:"fixed64,12,opt,name=unlabeledCost,proto3" json:"unlabeledCost,omitempty"`
-	Projects             []*Entity        `protobuf:"bytes,13,rep,name=projects,proto3" json:"projects,omitempty"`
-	Products             []*Entity        `protobuf:"bytes,14,rep,name=products,proto3" json:"products,omitempty"`
-	Services             []*Entity        `protobuf:"bytes,15,rep,name=services,proto3" json:"services,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
-	XXX_unrecognized     []byte           `json:"-"`
-	XXX_sizecache        int32            `json:"-"`
+	Type          string           `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Id            string           `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Aggregation   []float64        `protobuf:"fixed64,3,rep,packed,name=aggregation,proto3" json:"aggregation,omitempty"`
+	Entities      *Record          `protobuf:"bytes,4,opt,name=entities,proto3" json:"entities,omitempty"`
+	Change        *ChangeStatistic `protobuf:"bytes,5,opt,name=change,proto3" json:"change,omitempty"`
+	StartDate     string           `protobuf:"bytes,6,opt,name=startDate,proto3" json:"startDate,omitempty"`
+	EndDate       string           `protobuf:"bytes,7,opt,name=endDate,proto3" json:"endDate,omitempty"`
+	Project       string           `protobuf:"bytes,8,opt,name=project,proto3" json:"project,omitempty"`
+	PeriodStart   string           `protobuf:"bytes,9,opt,name=periodStart,proto3" json:"periodStart,omitempty"`
+	PeriodEnd     string           `protobuf:"bytes,10,opt,name=periodEnd,proto3" json:"periodEnd,omitempty"`
+	LabeledCost   float64          `protobuf:"fixed64,11,opt,name=labeledCost,proto3" json:"labeledCost,omitempty"`
+	UnlabeledCost float64          `protobuf:"fixed64,12,opt,name=unlabeledCost,proto3" json:"unlabeledCost,omitempty"`
+	Projects      []*Entity        `protobuf:"bytes,13,rep,name=projects,proto3" json:"projects,omitempty"`
+	Products      []*Entity        `protobuf:"bytes,14,rep,name=products,proto3" json:"products,omitempty"`
+	Services      []*Entity        `protobuf:"bytes,15,rep,name=services,proto3" json:"services,omitempty"`
+	// The severity of an alert from the rule raising it: info, warning or critical
+	Severity string `protobuf:"bytes,16,opt,name=severity,proto3" json:"severity,omitempty"`
+	// The days of a budget alert left in the budget period after the last complete billing date
+	DaysRemaining int32 `protobuf:"varint,17,opt,name=daysRemaining,proto3" json:"daysRemaining,omitempty"`
+	// The cost of a budget alert over the budget, spent or projected by the run rate
+	Overspend float64 `protobuf:"fixed64,18,opt,name=overspend,proto3" json:"overspend,omitempty"`
+	// The stable id of an alert, the same alert of later getAlerts calls keeps it
+	AlertId string `protobuf:"bytes,19,opt,name=alertId,proto3" json:"alertId,omitempty"`
+	// The status of an alert set with setAlertStatus: snoozed, accepted or dismissed, empty for
+	// an active alert
+	Status string `protobuf:"bytes,20,opt,name=status,proto3" json:"status,omitempty"`
+	// The date the status of an alert expires, exclusive, empty when it does not expire
+	StatusUntil string `protobuf:"bytes,21,opt,name=statusUntil,proto3" json:"statusUntil,omitempty"`
+	// The term of the purchase recommended by a savings alert as an ISO 8601 duration, P1Y or P3Y
+	Term string `protobuf:"bytes,22,opt,name=term,proto3" json:"term,omitempty"`
+	// The estimated monthly savings of the purchase recommended by a savings alert
+	MonthlySavings float64 `protobuf:"fixed64,23,opt,name=monthlySavings,proto3" json:"monthlySavings,omitempty"`
+	// The current instance type of an instance of a rightsizing alert
+	InstanceType string `protobuf:"bytes,24,opt,name=instanceType,proto3" json:"instanceType,omitempty"`
+	// The instance type recommended for an instance of a rightsizing alert, empty when the
+	// instance is recommended to be terminated
+	RecommendedInstanceType string   `protobuf:"bytes,25,opt,name=recommendedInstanceType,proto3" json:"recommendedInstanceType,omitempty"`
+	XXX_NoUnkeyedLiteral    struct{} `json:"-"`
+	XXX_unrecognized        []byte   `json:"-"`
+	XXX_sizecache           int32    `json:"-"`
 }
 
 func (m *Entity) Reset()         { *m = Entity{} }
@@ -1318,6 +1349,76 @@ func (m *Entity) GetServices() []*Entity {
 	return nil
 }
 
+func (m *Entity) GetSeverity() string {
+	if m != nil {
+		return m.Severity
+	}
+	return ""
+}
+
+func (m *Entity) GetDaysRemaining() int32 {
+	if m != nil {
+		return m.DaysRemaining
+	}
+	return 0
+}
+
+func (m *Entity) GetOverspend() float64 {
+	if m != nil {
+		return m.Overspend
+	}
+	return 0
+}
+
+func (m *Entity) GetAlertId() string {
+	if m != nil {
+		return m.AlertId
+	}
+	return ""
+}
+
+func (m *Entity) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *Entity) GetStatusUntil() string {
+	if m != nil {
+		return m.StatusUntil
+	}
+	return ""
+}
+
+func (m *Entity) GetTerm() string {
+	if m != nil {
+		return m.Term
+	}
+	return ""
+}
+
+func (m *Entity) GetMonthlySavings() float64 {
+	if m != nil {
+		return m.MonthlySavings
+	}
+	return 0
+}
+
+func (m *Entity) GetInstanceType() string {
+	if m != nil {
+		return m.InstanceType
+	}
+	return ""
+}
+
+func (m *Entity) GetRecommendedInstanceType() string {
+	if m != nil {
+		return m.RecommendedInstanceType
+	}
+	return ""
+}
+
 type AlertRequest struct {
 	Group                string   `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -1398,265 +1499,1162 @@ func (m *AlertResponse) GetAlerts() []*Entity {
 	return nil
 }
 
-func init() {
-	proto.RegisterType((*VersionResponse)(nil), "awscost.VersionResponse")
-	proto.RegisterType((*LastCompleteBillingDateResponse)(nil), "awscost.LastCompleteBillingDateResponse")
-	proto.RegisterType((*Group)(nil), "awscost.Group")
-	proto.RegisterType((*UserGroupsRequest)(nil), "awscost.UserGroupsRequest")
-	proto.RegisterType((*UserGroupsResponse)(nil), "awscost.UserGroupsResponse")
-	proto.RegisterType((*Project)(nil), "awscost.Project")
-	proto.RegisterType((*GroupProjectsRequest)(nil), "awscost.GroupProjectsRequest")
-	proto.RegisterType((*GroupProjectsResponse)(nil), "awscost.GroupProjectsResponse")
-	proto.RegisterType((*DateAggregation)(nil), "awscost.DateAggregation")
-	proto.RegisterType((*ChangeStatistic)(nil), "awscost.ChangeStatistic")
-	proto.RegisterType((*Trendline)(nil), "awscost.Trendline")
-	proto.RegisterType((*ProductCost)(nil), "awscost.ProductCost")
-	proto.RegisterType((*ProjectCost)(nil), "awscost.ProjectCost")
-	proto.RegisterType((*GroupedCosts)(nil), "awscost.GroupedCosts")
-	proto.RegisterType((*GroupDailyCostRequest)(nil), "awscost.GroupDailyCostRequest")
-	proto.RegisterType((*GroupDailyCostResponse)(nil), "awscost.GroupDailyCostResponse")
-	proto.RegisterType((*ProjectDailyCostRequest)(nil), "awscost.ProjectDailyCostRequest")
-	proto.RegisterType((*ProjectDailyCostResponse)(nil), "awscost.ProjectDailyCostResponse")
-	proto.RegisterType((*DailyMetricDataRequest)(nil), "awscost.DailyMetricDataRequest")
-	proto.RegisterType((*DailyMetricDataResponse)(nil), "awscost.DailyMetricDataResponse")
-	proto.RegisterType((*ProductInsightsRequest)(nil), "awscost.ProductInsightsRequest")
-	proto.RegisterType((*Record)(nil), "awscost.Record")
-	proto.RegisterType((*Entity)(nil), "awscost.Entity")
-	proto.RegisterType((*AlertRequest)(nil), "awscost.AlertRequest")
-	proto.RegisterType((*AlertResponse)(nil), "awscost.AlertResponse")
+type CostForecastRequest struct {
+	// The group id from getUserGroups, the forecast is for the costs of the group
+	Group string `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	// (optional) The project id from getGroupProjects, the forecast is for the costs of the
+	// project instead of the group
+	Project string `protobuf:"bytes,2,opt,name=project,proto3" json:"project,omitempty"`
+	// The period to forecast to the end of: month, quarter or year of the fiscal calendar,
+	// month when not set
+	Horizon string `protobuf:"bytes,3,opt,name=horizon,proto3" json:"horizon,omitempty"`
+	// (optional) The last day of actual costs in YYYY-MM-DD format, the last complete billing
+	// date when not set
+	Date string `protobuf:"bytes,4,opt,name=date,proto3" json:"date,omitempty"`
+	// (optional) The probability of the confidence band of the forecast, cost.forecast.confidence
+	// when not set
+	Confidence           float64  `protobuf:"fixed64,5,opt,name=confidence,proto3" json:"confidence,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func init() {
-	proto.RegisterFile("github.com/seizadi/cost-insights-backend/pkg/pb/service.proto", fileDescriptor_e7502069f31e39f7)
+func (m *CostForecastRequest) Reset()         { *m = CostForecastRequest{} }
+func (m *CostForecastRequest) String() string { return proto.CompactTextString(m) }
+func (*CostForecastRequest) ProtoMessage()    {}
+func (*CostForecastRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e7502069f31e39f7, []int{25}
 }
 
-var fileDescriptor_e7502069f31e39f7 = []byte{
-	// 1375 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x58, 0x5b, 0x8f, 0xdb, 0xd4,
-	0x16, 0x56, 0x32, 0x33, 0xce, 0x64, 0xcd, 0x25, 0x9d, 0xdd, 0x69, 0xc6, 0x27, 0xd3, 0x9e, 0xa6,
-	0x3e, 0x3d, 0xa7, 0x3d, 0xea, 0x4c, 0x5c, 0x0d, 0x20, 0x71, 0xab, 0xd0, 0xb4, 0x1d, 0x45, 0x55,
-	0x01, 0x15, 0x97, 0x22, 0x01, 0x12, 0xd1, 0x8e, 0xbd, 0xea, 0xba, 0x75, 0x6c, 0xe3, 0xbd, 0x33,
-	0x43, 0x78, 0x42, 0xf0, 0xce, 0x0b, 0x8f, 0x88, 0x5f, 0xc5, 0x1b, 0x6f, 0x48, 0xfc, 0x02, 0x7e,
-	0x01, 0xda, 0xdb, 0xcb, 0x8e, 0xe3, 0x24, 0x53, 0x2a, 0xf1, 0x84, 0x78, 0xcb, 0x5e, 0xeb, 0x5b,
-	0xf7, 0xcf, 0xfb, 0x12, 0xb8, 0xe3, 0x07, 0xf2, 0xd9, 0x78, 0xd8, 0x73, 0xe3, 0x91, 0x2d, 0x30,
-	0xf8, 0x9a, 0x7b, 0x81, 0xed, 0xc6, 0x42, 0x1e, 0x06, 0x91, 0x08, 0xfc, 0x67, 0x52, 0x1c, 0x0e,
-	0xb9, 0xfb, 0x02, 0x23, 0xcf, 0x4e, 0x5e, 0xf8, 0x76, 0x32, 0xb4, 0x05, 0xa6, 0xa7, 0x81, 0x8b,
-	0xbd, 0x24, 0x8d, 0x65, 0xcc, 0x1a, 0xfc, 0x4c, 0x28, 0x78, 0x67, 0xdf, 0x8f, 0x63, 0x3f, 0x44,
-	0x5b, 0x8b, 0x87, 0xe3, 0xa7, 0x36, 0x8e, 0x12, 0x39, 0xc9, 0x50, 0x9d, 0xcb, 0xa4, 0xe4, 0x49,
-	0x60, 0xf3, 0x28, 0x8a, 0x25, 0x97, 0x41, 0x1c, 0x09, 0xd2, 0x1e, 0x97, 0x52, 0xc0, 0xe8, 0x34,
-	0x9e, 0x24, 0x69, 0xfc, 0xd5, 0x24, 0xf3, 0xe4, 0x1e, 0xfa, 0x18, 0x1d, 0x9e, 0xf2, 0x30, 0xf0,
-	0xb8, 0x44, 0x7b, 0xee, 0x07, 0xb9, 0x38, 0x28, 0x81, 0xc5, 0x19, 0xf7, 0x7d, 0x4c, 0xed, 0x38,
-	0xd1, 0x41, 0xe6, 0x03, 0x5a, 0xb7, 0xa0, 0xf5, 0x09, 0xa6, 0x22, 0x88, 0x23, 0x07, 0x45, 0x12,
-	0x47, 0x02, 0x99, 0x09, 0x8d, 0xd3, 0x4c, 0x64, 0xd6, 0xba, 0xb5, 0x9b, 0x4d, 0x27, 0x5f, 0x5a,
-	0x6f, 0xc0, 0xd5, 0xf7, 0xb9, 0x90, 0xf7, 0xe2, 0x51, 0x12, 0xa2, 0xc4, 0xbb, 0x41, 0x18, 0x06,
-	0x91, 0x7f, 0x9f, 0x4b, 0x2c, 0x8c, 0x19, 0xac, 0xaa, 0x5c, 0xc8, 0x52, 0xff, 0xb6, 0xf6, 0x60,
-	0xad, 0x9f, 0xc6, 0xe3, 0x84, 0x6d, 0x43, 0x3d, 0xf0, 0x48, 0x55, 0x0f, 0x3c, 0xeb, 0x00, 0x76,
-	0x9e, 0x08, 0x4c, 0xb5, 0x52, 0x38, 0xf8, 0xe5, 0x18, 0x85, 0x64, 0x7b, 0xd0, 0x18, 0x0b, 0x4c,
-	0x07, 0x05, 0xd2, 0x50, 0xcb, 0x07, 0x9e, 0xf5, 0x2e, 0xb0, 0x32, 0x9a, 0x02, 0xfe, 0x0f, 0x0c,
-	0x5f, 0x4b, 0xcc, 0x5a, 0x77, 0xe5, 0xe6, 0xc6, 0xd1, 0x76, 0x8f, 0xc6, 0xd0, 0xd3, 0x40, 0x87,
-	0xb4, 0xd6, 0x21, 0x34, 0x1e, 0xa5, 0xf1, 0x73, 0x74, 0x65, 0x35, 0x0d, 0x95, 0x73, 0xc4, 0x47,
-	0x68, 0xd6, 0xb3, 0x9c, 0xd5, 0x6f, 0xeb, 0x00, 0x76, 0xb5, 0x3d, 0xd9, 0x14, 0xd9, 0xed, 0xc2,
-	0x9a, 0x76, 0x48, 0xe6, 0xd9, 0xc2, 0x3a, 0x81, 0x4b, 0x15, 0x34, 0x65, 0x77, 0x00, 0xeb, 0x09,
-	0xc9, 0x28, 0xbf, 0x0b, 0x45, 0x7e, 0x04, 0x76, 0x0a, 0x84, 0x75, 0x07, 0x5a, 0xaa, 0x99, 0xc7,
-	0xbe, 0x9f, 0xa2, 0xaf, 0xc7, 0xb4, 0xa8, 0x9f, 0xac, 0x0d, 0x06, 0x1f, 0xc5, 0xe3, 0x48, 0xea,
-	0x8c, 0x6b, 0x0e, 0xad, 0xac, 0xf7, 0xa0, 0x75, 0xef, 0x19, 0x8f, 0x7c, 0x7c, 0xac, 0x66, 0x2c,
-	0x64, 0xe0, 0xaa, 0x74, 0x53, 0xe5, 0x48, 0xdb, 0xd7, 0x9d, 0x6c, 0x71, 0x8e, 0x83, 0xe6, 0xc7,
-	0x29, 0x46, 0x5e, 0x18, 0x44, 0xa8, 0x4c, 0x45, 0x18, 0x27, 0x98, 0x9b, 0xea, 0x05, 0xbb, 0x0c,
-	0xcd, 0x20, 0x92, 0x98, 0xba, 0x98, 0x64, 0xd6, 0x75, 0x67, 0x2a, 0xb0, 0x3e, 0x85, 0x8d, 0x47,
-	0x69, 0xec, 0x8d, 0x5d, 0x79, 0x2f, 0x16, 0xf3, 0x8d, 0x7e, 0x1b, 0x36, 0xf8, 0xb4, 0x36, 0xb3,
-	0xae, 0x1b, 0x62, 0x16, 0x0d, 0xa9, 0xd4, 0xee, 0x94, 0xc1, 0xe4, 0x5a, 0xf5, 0xe9, 0x2f, 0x77,
-	0x1d, 0xc1, 0xa6, 0x9e, 0x1e, 0x7a, 0xca, 0xb5, 0x60, 0x3d, 0x68, 0x24, 0x59, 0x15, 0x34, 0xb3,
-	0xdd, 0xf2, 0xcc, 0xf2, 0xea, 0x9c, 0x1c, 0x44, 0x78, 0x95, 0x1a, 0xc5, 0xdd, 0xad, 0xce, 0xb8,
-	0xc0, 0xab, 0x85, 0xf5, 0x90, 0xd8, 0x72, 0x9f, 0x07, 0xe1, 0x44, 0xab, 0xce, 0x23, 0x57, 0xd1,
-	0xf2, 0x53, 0x1e, 0x0a, 0xe2, 0xe8, 0x54, 0x60, 0xfd, 0x58, 0x87, 0x76, 0xd5, 0x1b, 0x91, 0xaf,
-	0xda, 0xa3, 0x36, 0x18, 0x4f, 0xe3, 0x74, 0xc4, 0x25, 0x79, 0xa1, 0x55, 0xb5, 0x77, 0x2b, 0xaf,
-	0xd0, 0x3b, 0x76, 0x1b, 0x0c, 0x57, 0x73, 0xce, 0x5c, 0xed, 0xd6, 0x66, 0xcc, 0x2a, 0x54, 0x74,
-	0x08, 0xc7, 0x6e, 0x43, 0x53, 0xe6, 0x24, 0x33, 0xd7, 0xb4, 0x11, 0x2b, 0x8c, 0x0a, 0xfa, 0x39,
-	0x53, 0x10, 0x7b, 0x0b, 0x36, 0xfd, 0xd2, 0x7c, 0x4c, 0x43, 0x1b, 0x5d, 0x9a, 0xfd, 0xd0, 0x49,
-	0xe9, 0xcc, 0x40, 0xad, 0x8f, 0x60, 0x8f, 0x46, 0x30, 0xd7, 0x6c, 0x73, 0x3a, 0x35, 0xda, 0xe6,
-	0x68, 0xf9, 0x92, 0x86, 0xff, 0x54, 0x07, 0x73, 0xde, 0xe7, 0x3f, 0x2d, 0xcf, 0x5b, 0xfe, 0x21,
-	0xb4, 0x75, 0x5f, 0x3e, 0x40, 0x99, 0x06, 0xee, 0x7d, 0x2e, 0x79, 0xde, 0xf1, 0x36, 0x18, 0x23,
-	0x2d, 0xcc, 0x37, 0xf6, 0x6c, 0xf5, 0x92, 0x7e, 0xff, 0x5a, 0x83, 0xbd, 0x39, 0x87, 0x7f, 0xaf,
-	0x76, 0x5b, 0xdf, 0xd4, 0xa0, 0x4d, 0x5b, 0xcb, 0x03, 0xba, 0x6b, 0xcc, 0xd2, 0x94, 0x36, 0xa3,
-	0x9c, 0xa6, 0x7a, 0xdb, 0x29, 0x76, 0x8b, 0xfa, 0xd2, 0xdd, 0x62, 0xa5, 0xd2, 0xcc, 0x32, 0xe9,
-	0x57, 0x67, 0x48, 0x6f, 0xfd, 0x52, 0x07, 0xc3, 0x41, 0x37, 0x4e, 0x3d, 0xf6, 0x5f, 0x58, 0xc3,
-	0x53, 0x8c, 0xf2, 0xdd, 0xaf, 0x55, 0xe4, 0x7e, 0x12, 0xc9, 0x40, 0x4e, 0x9c, 0x4c, 0xcb, 0xfe,
-	0x0f, 0x0d, 0xba, 0x00, 0xd1, 0xb6, 0x37, 0x07, 0xcc, 0xf5, 0xcc, 0x06, 0xf0, 0x30, 0x09, 0xe3,
-	0xc9, 0x48, 0xb9, 0x5d, 0x59, 0x8c, 0x2e, 0x41, 0xd8, 0x35, 0x58, 0x79, 0xfc, 0xf0, 0x89, 0xb9,
-	0xba, 0x18, 0xa9, 0x74, 0xec, 0x06, 0x18, 0xc3, 0xb1, 0xfb, 0x02, 0xa5, 0xb9, 0xb6, 0x18, 0x45,
-	0x6a, 0x76, 0x0b, 0xd6, 0x93, 0x20, 0x41, 0x3d, 0x0d, 0x63, 0x31, 0xb4, 0x00, 0xa8, 0xa2, 0x3c,
-	0x2e, 0xb9, 0x40, 0x69, 0x36, 0x96, 0x14, 0x45, 0x7a, 0x05, 0xcd, 0x27, 0xb3, 0xbe, 0x04, 0x4a,
-	0x7a, 0xeb, 0xfb, 0x55, 0x30, 0x32, 0x99, 0x3a, 0xd0, 0xe5, 0x24, 0x29, 0x0e, 0x74, 0xf5, 0x9b,
-	0x68, 0x5c, 0x2f, 0x68, 0xdc, 0x9d, 0xa7, 0x6b, 0x6d, 0x96, 0x94, 0xb7, 0x60, 0x1d, 0x95, 0xbf,
-	0x00, 0x05, 0xd1, 0x72, 0x1a, 0x3c, 0x9b, 0xa2, 0x53, 0x00, 0x4a, 0x0c, 0x5e, 0xfb, 0x93, 0x0c,
-	0xbe, 0x0c, 0x4d, 0x21, 0x79, 0x2a, 0xd5, 0x87, 0xa1, 0xbf, 0xfd, 0xa6, 0x33, 0x15, 0x28, 0x12,
-	0x61, 0xe4, 0x69, 0x5d, 0x23, 0x23, 0x11, 0x2d, 0xcb, 0xf4, 0x5a, 0x9f, 0xdd, 0x53, 0xbb, 0xb0,
-	0x91, 0x60, 0x1a, 0xc4, 0xde, 0x63, 0xe5, 0xc6, 0x6c, 0x6a, 0x6d, 0x59, 0xa4, 0x62, 0x66, 0xcb,
-	0x93, 0xc8, 0x33, 0x21, 0x8b, 0x59, 0x08, 0x94, 0x7d, 0xc8, 0x87, 0x18, 0x66, 0xbb, 0x8c, 0xb9,
-	0xa1, 0xef, 0x2d, 0x65, 0x11, 0xbb, 0x0e, 0x5b, 0xe3, 0xa8, 0x8c, 0xd9, 0xd4, 0x98, 0x59, 0xa1,
-	0x26, 0x43, 0x7e, 0x21, 0xdb, 0x5a, 0x46, 0x06, 0x02, 0x10, 0x58, 0x4d, 0x50, 0x98, 0xdb, 0xcb,
-	0xc1, 0x1a, 0xa0, 0xc0, 0x44, 0x77, 0x61, 0xb6, 0x96, 0x80, 0x73, 0x80, 0x75, 0x1d, 0x36, 0x8f,
-	0x43, 0x4c, 0xcf, 0x3f, 0xf9, 0xad, 0x37, 0x61, 0x8b, 0x50, 0xb4, 0xdf, 0xdd, 0x00, 0x83, 0x2b,
-	0x81, 0x58, 0xf6, 0x69, 0x92, 0xfa, 0xe8, 0x73, 0x68, 0x1c, 0x9f, 0x09, 0x5d, 0xf1, 0x23, 0x80,
-	0x3e, 0x4a, 0xba, 0xe4, 0xb3, 0x76, 0x2f, 0x7b, 0x7f, 0xf4, 0xf2, 0xc7, 0x49, 0xef, 0x44, 0x3d,
-	0x4e, 0x3a, 0x53, 0x4e, 0x54, 0x9e, 0x03, 0xd6, 0x85, 0x6f, 0x7f, 0xfe, 0xed, 0x87, 0x3a, 0xb0,
-	0x75, 0x9b, 0x9e, 0x01, 0x47, 0xbf, 0x1b, 0xd0, 0x52, 0xae, 0xf3, 0xad, 0xea, 0x38, 0x09, 0xd8,
-	0x77, 0x35, 0xe8, 0xf4, 0x51, 0x2e, 0x79, 0x1e, 0x2c, 0x0d, 0x7b, 0xb3, 0x08, 0xfb, 0x92, 0x87,
-	0x85, 0xf5, 0x1f, 0x9d, 0xc6, 0x15, 0xb6, 0x6f, 0x87, 0x5c, 0xc8, 0x81, 0x4b, 0xd0, 0xc1, 0x30,
-	0xc3, 0x0e, 0xf4, 0xcd, 0xf8, 0x0b, 0xd8, 0xea, 0xa3, 0x9c, 0xbe, 0x12, 0x58, 0xa7, 0xf0, 0x3f,
-	0xf7, 0xd0, 0xe8, 0xec, 0x2f, 0xd4, 0x51, 0xb8, 0x5d, 0x1d, 0x6e, 0x9b, 0x6d, 0xda, 0xfa, 0x31,
-	0x92, 0x3d, 0x22, 0xd8, 0x73, 0xb8, 0xd0, 0x47, 0x39, 0x73, 0xd5, 0x67, 0x57, 0x66, 0x0f, 0xc5,
-	0xca, 0x83, 0xa1, 0xf3, 0xef, 0x65, 0x6a, 0x0a, 0xb4, 0xa7, 0x03, 0xed, 0xb0, 0x96, 0xad, 0x63,
-	0x0c, 0x0a, 0xf2, 0x09, 0x60, 0x7d, 0x94, 0x95, 0x93, 0x8f, 0x5d, 0x2d, 0x1d, 0x5a, 0x8b, 0x0e,
-	0xd9, 0x4e, 0x77, 0x39, 0x80, 0x22, 0x76, 0x74, 0xc4, 0x5d, 0xc6, 0x6c, 0x4f, 0x21, 0x06, 0xd9,
-	0x29, 0xac, 0x1a, 0xc8, 0x59, 0x0c, 0x3b, 0x79, 0x81, 0xc5, 0xe5, 0x86, 0x55, 0x4a, 0xa8, 0xde,
-	0xa4, 0x3a, 0x57, 0x97, 0xea, 0x29, 0xe2, 0xbf, 0x74, 0xc4, 0x8b, 0x6c, 0x87, 0x6a, 0xcc, 0xe2,
-	0x2a, 0x0b, 0xc6, 0x75, 0x95, 0x95, 0xb3, 0xaf, 0x54, 0xe5, 0xe2, 0x53, 0xb1, 0x53, 0x25, 0x7e,
-	0x29, 0x04, 0x7d, 0x90, 0x83, 0xfc, 0xd1, 0xce, 0xce, 0xe0, 0x62, 0x16, 0x62, 0xe6, 0xca, 0xc6,
-	0xba, 0xd5, 0x4b, 0xfa, 0x5c, 0x5d, 0xd7, 0xce, 0x41, 0x50, 0x65, 0xfb, 0x3a, 0xec, 0x25, 0x76,
-	0xd1, 0xa6, 0xb9, 0x95, 0x6b, 0x7b, 0x08, 0xcd, 0x3e, 0x4a, 0xfd, 0x05, 0x0b, 0x36, 0xbd, 0x3b,
-	0x95, 0x3f, 0xfc, 0x4e, 0xbb, 0x2a, 0x26, 0xc7, 0x2d, 0xed, 0xb8, 0xc9, 0x1a, 0x76, 0xf6, 0x45,
-	0xdf, 0x7d, 0xfd, 0xb3, 0xa3, 0x57, 0xfc, 0x7b, 0xe2, 0x9d, 0x64, 0x38, 0x34, 0xf4, 0xf7, 0xf6,
-	0xda, 0x1f, 0x01, 0x00, 0x00, 0xff, 0xff, 0x98, 0xb7, 0xf1, 0xc5, 0xdb, 0x10, 0x00, 0x00,
+func (m *CostForecastRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CostForecastRequest.Unmarshal(m, b)
+}
+func (m *CostForecastRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CostForecastRequest.Marshal(b, m, deterministic)
+}
+func (m *CostForecastRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CostForecastRequest.Merge(m, src)
+}
+func (m *CostForecastRequest) XXX_Size() int {
+	return xxx_messageInfo_CostForecastRequest.Size(m)
+}
+func (m *CostForecastRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CostForecastRequest.DiscardUnknown(m)
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+var xxx_messageInfo_CostForecastRequest proto.InternalMessageInfo
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+func (m *CostForecastRequest) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
 
-// AwsCostClient is the client API for AwsCost service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type AwsCostClient interface {
-	GetVersion(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*VersionResponse, error)
+func (m *CostForecastRequest) GetProject() string {
+	if m != nil {
+		return m.Project
+	}
+	return ""
 }
 
-type awsCostClient struct {
-	cc *grpc.ClientConn
+func (m *CostForecastRequest) GetHorizon() string {
+	if m != nil {
+		return m.Horizon
+	}
+	return ""
 }
 
-func NewAwsCostClient(cc *grpc.ClientConn) AwsCostClient {
-	return &awsCostClient{cc}
+func (m *CostForecastRequest) GetDate() string {
+	if m != nil {
+		return m.Date
+	}
+	return ""
 }
 
-func (c *awsCostClient) GetVersion(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*VersionResponse, error) {
-	out := new(VersionResponse)
-	err := c.cc.Invoke(ctx, "/awscost.AwsCost/GetVersion", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *CostForecastRequest) GetConfidence() float64 {
+	if m != nil {
+		return m.Confidence
 	}
-	return out, nil
+	return 0
 }
 
-// AwsCostServer is the server API for AwsCost service.
-type AwsCostServer interface {
-	GetVersion(context.Context, *empty.Empty) (*VersionResponse, error)
+type ForecastAggregation struct {
+	Date string `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`
+	// The forecast cost
+	Amount float64 `protobuf:"fixed64,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	// The lower and upper bound of the confidence band of the forecast
+	Lower                float64  `protobuf:"fixed64,3,opt,name=lower,proto3" json:"lower,omitempty"`
+	Upper                float64  `protobuf:"fixed64,4,opt,name=upper,proto3" json:"upper,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func RegisterAwsCostServer(s *grpc.Server, srv AwsCostServer) {
-	s.RegisterService(&_AwsCost_serviceDesc, srv)
+func (m *ForecastAggregation) Reset()         { *m = ForecastAggregation{} }
+func (m *ForecastAggregation) String() string { return proto.CompactTextString(m) }
+func (*ForecastAggregation) ProtoMessage()    {}
+func (*ForecastAggregation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e7502069f31e39f7, []int{26}
 }
 
-func _AwsCost_GetVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(empty.Empty)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(AwsCostServer).GetVersion(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/awscost.AwsCost/GetVersion",
+func (m *ForecastAggregation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ForecastAggregation.Unmarshal(m, b)
+}
+func (m *ForecastAggregation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ForecastAggregation.Marshal(b, m, deterministic)
+}
+func (m *ForecastAggregation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ForecastAggregation.Merge(m, src)
+}
+func (m *ForecastAggregation) XXX_Size() int {
+	return xxx_messageInfo_ForecastAggregation.Size(m)
+}
+func (m *ForecastAggregation) XXX_DiscardUnknown() {
+	xxx_messageInfo_ForecastAggregation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ForecastAggregation proto.InternalMessageInfo
+
+func (m *ForecastAggregation) GetDate() string {
+	if m != nil {
+		return m.Date
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AwsCostServer).GetVersion(ctx, req.(*empty.Empty))
+	return ""
+}
+
+func (m *ForecastAggregation) GetAmount() float64 {
+	if m != nil {
+		return m.Amount
 	}
-	return interceptor(ctx, in, info, handler)
+	return 0
 }
 
-var _AwsCost_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "awscost.AwsCost",
-	HandlerType: (*AwsCostServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "GetVersion",
-			Handler:    _AwsCost_GetVersion_Handler,
-		},
-	},
-	Streams:  []grpc.StreamDesc{},
-	Metadata: "github.com/seizadi/cost-insights-backend/pkg/pb/service.proto",
+func (m *ForecastAggregation) GetLower() float64 {
+	if m != nil {
+		return m.Lower
+	}
+	return 0
 }
 
-// CostInsightsApiClient is the client API for CostInsightsApi service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type CostInsightsApiClient interface {
-	GetLastCompleteBillingDate(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*LastCompleteBillingDateResponse, error)
-	GetUserGroups(ctx context.Context, in *UserGroupsRequest, opts ...grpc.CallOption) (*UserGroupsResponse, error)
-	GetGroupProjects(ctx context.Context, in *GroupProjectsRequest, opts ...grpc.CallOption) (*GroupProjectsResponse, error)
-	GetDailyMetricData(ctx context.Context, in *DailyMetricDataRequest, opts ...grpc.CallOption) (*DailyMetricDataResponse, error)
-	GetGroupDailyCost(ctx context.Context, in *GroupDailyCostRequest, opts ...grpc.CallOption) (*GroupDailyCostResponse, error)
-	GetProductInsights(ctx context.Context, in *ProductInsightsRequest, opts ...grpc.CallOption) (*Entity, error)
-	GetProjectDailyCost(ctx context.Context, in *ProjectDailyCostRequest, opts ...grpc.CallOption) (*ProjectDailyCostResponse, error)
-	GetAlerts(ctx context.Context, in *AlertRequest, opts ...grpc.CallOption) (*AlertResponse, error)
+func (m *ForecastAggregation) GetUpper() float64 {
+	if m != nil {
+		return m.Upper
+	}
+	return 0
 }
 
-type costInsightsApiClient struct {
-	cc *grpc.ClientConn
+type CostForecastResponse struct {
+	Id      string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Format  string `protobuf:"bytes,2,opt,name=format,proto3" json:"format,omitempty"`
+	Horizon string `protobuf:"bytes,3,opt,name=horizon,proto3" json:"horizon,omitempty"`
+	// The first day and the exclusive end of the forecast period
+	StartDate string `protobuf:"bytes,4,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate   string `protobuf:"bytes,5,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	// The daily costs the forecast is based on, up to the last day of actual costs
+	Aggregation []*DateAggregation `protobuf:"bytes,6,rep,name=aggregation,proto3" json:"aggregation,omitempty"`
+	// The daily forecast from the day after the last actual cost to the end of the period
+	Forecast []*ForecastAggregation `protobuf:"bytes,7,rep,name=forecast,proto3" json:"forecast,omitempty"`
+	// The actual cost of the period so far
+	Actual float64 `protobuf:"fixed64,8,opt,name=actual,proto3" json:"actual,omitempty"`
+	// The forecast cost of the whole period, the actual cost plus the forecast of the remaining days
+	Total *ForecastAggregation `protobuf:"bytes,9,opt,name=total,proto3" json:"total,omitempty"`
+	// The model of the forecast: holt_winters with weekday seasonality, holt or naive for a
+	// short history
+	Model      string  `protobuf:"bytes,10,opt,name=model,proto3" json:"model,omitempty"`
+	Confidence float64 `protobuf:"fixed64,11,opt,name=confidence,proto3" json:"confidence,omitempty"`
+	// (optional) The forecast cost of the whole period using the Cost Explorer forecast of the
+	// remaining days, when cost.forecast.cross_check is enabled
+	CrossCheck           *ForecastAggregation `protobuf:"bytes,12,opt,name=cross_check,json=crossCheck,proto3" json:"cross_check,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
 }
 
-func NewCostInsightsApiClient(cc *grpc.ClientConn) CostInsightsApiClient {
-	return &costInsightsApiClient{cc}
+func (m *CostForecastResponse) Reset()         { *m = CostForecastResponse{} }
+func (m *CostForecastResponse) String() string { return proto.CompactTextString(m) }
+func (*CostForecastResponse) ProtoMessage()    {}
+func (*CostForecastResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e7502069f31e39f7, []int{27}
 }
 
-func (c *costInsightsApiClient) GetLastCompleteBillingDate(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*LastCompleteBillingDateResponse, error) {
-	out := new(LastCompleteBillingDateResponse)
-	err := c.cc.Invoke(ctx, "/awscost.CostInsightsApi/GetLastCompleteBillingDate", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+func (m *CostForecastResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CostForecastResponse.Unmarshal(m, b)
+}
+func (m *CostForecastResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CostForecastResponse.Marshal(b, m, deterministic)
+}
+func (m *CostForecastResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CostForecastResponse.Merge(m, src)
+}
+func (m *CostForecastResponse) XXX_Size() int {
+	return xxx_messageInfo_CostForecastResponse.Size(m)
+}
+func (m *CostForecastResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CostForecastResponse.DiscardUnknown(m)
 }
 
-func (c *costInsightsApiClient) GetUserGroups(ctx context.Context, in *UserGroupsRequest, opts ...grpc.CallOption) (*UserGroupsResponse, error) {
-	out := new(UserGroupsResponse)
-	err := c.cc.Invoke(ctx, "/awscost.CostInsightsApi/GetUserGroups", in, out, opts...)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_CostForecastResponse proto.InternalMessageInfo
+
+func (m *CostForecastResponse) GetId() string {
+	if m != nil {
+		return m.Id
 	}
-	return out, nil
+	return ""
 }
 
-func (c *costInsightsApiClient) GetGroupProjects(ctx context.Context, in *GroupProjectsRequest, opts ...grpc.CallOption) (*GroupProjectsResponse, error) {
-	out := new(GroupProjectsResponse)
-	err := c.cc.Invoke(ctx, "/awscost.CostInsightsApi/GetGroupProjects", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *CostForecastResponse) GetFormat() string {
+	if m != nil {
+		return m.Format
 	}
-	return out, nil
+	return ""
 }
 
-func (c *costInsightsApiClient) GetDailyMetricData(ctx context.Context, in *DailyMetricDataRequest, opts ...grpc.CallOption) (*DailyMetricDataResponse, error) {
-	out := new(DailyMetricDataResponse)
-	err := c.cc.Invoke(ctx, "/awscost.CostInsightsApi/GetDailyMetricData", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *CostForecastResponse) GetHorizon() string {
+	if m != nil {
+		return m.Horizon
 	}
-	return out, nil
+	return ""
 }
 
-func (c *costInsightsApiClient) GetGroupDailyCost(ctx context.Context, in *GroupDailyCostRequest, opts ...grpc.CallOption) (*GroupDailyCostResponse, error) {
-	out := new(GroupDailyCostResponse)
-	err := c.cc.Invoke(ctx, "/awscost.CostInsightsApi/GetGroupDailyCost", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *CostForecastResponse) GetStartDate() string {
+	if m != nil {
+		return m.StartDate
 	}
-	return out, nil
+	return ""
+}
+
+func (m *CostForecastResponse) GetEndDate() string {
+	if m != nil {
+		return m.EndDate
+	}
+	return ""
+}
+
+func (m *CostForecastResponse) GetAggregation() []*DateAggregation {
+	if m != nil {
+		return m.Aggregation
+	}
+	return nil
+}
+
+func (m *CostForecastResponse) GetForecast() []*ForecastAggregation {
+	if m != nil {
+		return m.Forecast
+	}
+	return nil
+}
+
+func (m *CostForecastResponse) GetActual() float64 {
+	if m != nil {
+		return m.Actual
+	}
+	return 0
+}
+
+func (m *CostForecastResponse) GetTotal() *ForecastAggregation {
+	if m != nil {
+		return m.Total
+	}
+	return nil
+}
+
+func (m *CostForecastResponse) GetModel() string {
+	if m != nil {
+		return m.Model
+	}
+	return ""
+}
+
+func (m *CostForecastResponse) GetConfidence() float64 {
+	if m != nil {
+		return m.Confidence
+	}
+	return 0
+}
+
+func (m *CostForecastResponse) GetCrossCheck() *ForecastAggregation {
+	if m != nil {
+		return m.CrossCheck
+	}
+	return nil
+}
+
+type AlertStatusRequest struct {
+	// The group id from getUserGroups the alert is raised for
+	Group string `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	// The alertId of the alert from getAlerts
+	AlertId string `protobuf:"bytes,2,opt,name=alertId,proto3" json:"alertId,omitempty"`
+	// The new status of the alert: snoozed, accepted, dismissed or active to clear the status
+	Status string `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	// (optional) The reason for the status, e.g. the reason an alert is dismissed
+	Reason string `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	// (optional) Feedback on the alert
+	Feedback string `protobuf:"bytes,5,opt,name=feedback,proto3" json:"feedback,omitempty"`
+	// (optional) The ISO 8601 duration the status lasts from today, e.g. P7D. Snoozed alerts
+	// default to cost.alerts.snooze and dismissed alerts to cost.alerts.dismiss, accepted alerts
+	// do not expire without a duration.
+	Duration             string   `protobuf:"bytes,6,opt,name=duration,proto3" json:"duration,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AlertStatusRequest) Reset()         { *m = AlertStatusRequest{} }
+func (m *AlertStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*AlertStatusRequest) ProtoMessage()    {}
+func (*AlertStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e7502069f31e39f7, []int{28}
+}
+
+func (m *AlertStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AlertStatusRequest.Unmarshal(m, b)
+}
+func (m *AlertStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AlertStatusRequest.Marshal(b, m, deterministic)
+}
+func (m *AlertStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AlertStatusRequest.Merge(m, src)
+}
+func (m *AlertStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_AlertStatusRequest.Size(m)
+}
+func (m *AlertStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AlertStatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AlertStatusRequest proto.InternalMessageInfo
+
+func (m *AlertStatusRequest) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+func (m *AlertStatusRequest) GetAlertId() string {
+	if m != nil {
+		return m.AlertId
+	}
+	return ""
+}
+
+func (m *AlertStatusRequest) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *AlertStatusRequest) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *AlertStatusRequest) GetFeedback() string {
+	if m != nil {
+		return m.Feedback
+	}
+	return ""
+}
+
+func (m *AlertStatusRequest) GetDuration() string {
+	if m != nil {
+		return m.Duration
+	}
+	return ""
+}
+
+type AlertStatus struct {
+	Group    string `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	AlertId  string `protobuf:"bytes,2,opt,name=alertId,proto3" json:"alertId,omitempty"`
+	Status   string `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Reason   string `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	Feedback string `protobuf:"bytes,5,opt,name=feedback,proto3" json:"feedback,omitempty"`
+	// The date the status expires, exclusive, empty when it does not expire
+	Until                string   `protobuf:"bytes,6,opt,name=until,proto3" json:"until,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AlertStatus) Reset()         { *m = AlertStatus{} }
+func (m *AlertStatus) String() string { return proto.CompactTextString(m) }
+func (*AlertStatus) ProtoMessage()    {}
+func (*AlertStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e7502069f31e39f7, []int{29}
+}
+
+func (m *AlertStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AlertStatus.Unmarshal(m, b)
+}
+func (m *AlertStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AlertStatus.Marshal(b, m, deterministic)
+}
+func (m *AlertStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AlertStatus.Merge(m, src)
+}
+func (m *AlertStatus) XXX_Size() int {
+	return xxx_messageInfo_AlertStatus.Size(m)
+}
+func (m *AlertStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_AlertStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AlertStatus proto.InternalMessageInfo
+
+func (m *AlertStatus) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+func (m *AlertStatus) GetAlertId() string {
+	if m != nil {
+		return m.AlertId
+	}
+	return ""
+}
+
+func (m *AlertStatus) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *AlertStatus) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *AlertStatus) GetFeedback() string {
+	if m != nil {
+		return m.Feedback
+	}
+	return ""
+}
+
+func (m *AlertStatus) GetUntil() string {
+	if m != nil {
+		return m.Until
+	}
+	return ""
+}
+
+type AlertStatusResponse struct {
+	Status               *AlertStatus `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *AlertStatusResponse) Reset()         { *m = AlertStatusResponse{} }
+func (m *AlertStatusResponse) String() string { return proto.CompactTextString(m) }
+func (*AlertStatusResponse) ProtoMessage()    {}
+func (*AlertStatusResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e7502069f31e39f7, []int{30}
+}
+
+func (m *AlertStatusResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AlertStatusResponse.Unmarshal(m, b)
+}
+func (m *AlertStatusResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AlertStatusResponse.Marshal(b, m, deterministic)
+}
+func (m *AlertStatusResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AlertStatusResponse.Merge(m, src)
+}
+func (m *AlertStatusResponse) XXX_Size() int {
+	return xxx_messageInfo_AlertStatusResponse.Size(m)
+}
+func (m *AlertStatusResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AlertStatusResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AlertStatusResponse proto.InternalMessageInfo
+
+func (m *AlertStatusResponse) GetStatus() *AlertStatus {
+	if m != nil {
+		return m.Status
+	}
+	return nil
+}
+
+type CommitmentRequest struct {
+	// The group id from getUserGroups, the commitments of the accounts of the group
+	Group string `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	// (optional) The project id from getGroupProjects, the commitments of the accounts of the
+	// project instead of the group
+	Project string `protobuf:"bytes,2,opt,name=project,proto3" json:"project,omitempty"`
+	// (optional) The service, an AWS service name such as EC2 or a Cost Explorer service, the
+	// services of cost.commitment.services when not set
+	Service string `protobuf:"bytes,3,opt,name=service,proto3" json:"service,omitempty"`
+	// An ISO 8601 repeating interval string, such as R2/P30D/2021-09-01
+	Intervals            string   `protobuf:"bytes,4,opt,name=intervals,proto3" json:"intervals,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CommitmentRequest) Reset()         { *m = CommitmentRequest{} }
+func (m *CommitmentRequest) String() string { return proto.CompactTextString(m) }
+func (*CommitmentRequest) ProtoMessage()    {}
+func (*CommitmentRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e7502069f31e39f7, []int{31}
+}
+
+func (m *CommitmentRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CommitmentRequest.Unmarshal(m, b)
+}
+func (m *CommitmentRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CommitmentRequest.Marshal(b, m, deterministic)
+}
+func (m *CommitmentRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CommitmentRequest.Merge(m, src)
+}
+func (m *CommitmentRequest) XXX_Size() int {
+	return xxx_messageInfo_CommitmentRequest.Size(m)
+}
+func (m *CommitmentRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CommitmentRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CommitmentRequest proto.InternalMessageInfo
+
+func (m *CommitmentRequest) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+func (m *CommitmentRequest) GetProject() string {
+	if m != nil {
+		return m.Project
+	}
+	return ""
+}
+
+func (m *CommitmentRequest) GetService() string {
+	if m != nil {
+		return m.Service
+	}
+	return ""
+}
+
+func (m *CommitmentRequest) GetIntervals() string {
+	if m != nil {
+		return m.Intervals
+	}
+	return ""
+}
+
+type CommitmentMetricData struct {
+	// savingsPlansCoverage, reservationCoverage, savingsPlansUtilization or
+	// reservationUtilization
+	Id     string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Format string `protobuf:"bytes,2,opt,name=format,proto3" json:"format,omitempty"`
+	// The AWS Account of the daily percentages
+	Account string `protobuf:"bytes,3,opt,name=account,proto3" json:"account,omitempty"`
+	// The service of the daily percentages, empty for the Savings Plans utilization which is not
+	// by service
+	Service              string             `protobuf:"bytes,4,opt,name=service,proto3" json:"service,omitempty"`
+	Aggregation          []*DateAggregation `protobuf:"bytes,5,rep,name=aggregation,proto3" json:"aggregation,omitempty"`
+	Change               *ChangeStatistic   `protobuf:"bytes,6,opt,name=change,proto3" json:"change,omitempty"`
+	Trendline            *Trendline         `protobuf:"bytes,7,opt,name=trendline,proto3" json:"trendline,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *CommitmentMetricData) Reset()         { *m = CommitmentMetricData{} }
+func (m *CommitmentMetricData) String() string { return proto.CompactTextString(m) }
+func (*CommitmentMetricData) ProtoMessage()    {}
+func (*CommitmentMetricData) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e7502069f31e39f7, []int{32}
+}
+
+func (m *CommitmentMetricData) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CommitmentMetricData.Unmarshal(m, b)
+}
+func (m *CommitmentMetricData) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CommitmentMetricData.Marshal(b, m, deterministic)
+}
+func (m *CommitmentMetricData) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CommitmentMetricData.Merge(m, src)
+}
+func (m *CommitmentMetricData) XXX_Size() int {
+	return xxx_messageInfo_CommitmentMetricData.Size(m)
+}
+func (m *CommitmentMetricData) XXX_DiscardUnknown() {
+	xxx_messageInfo_CommitmentMetricData.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CommitmentMetricData proto.InternalMessageInfo
+
+func (m *CommitmentMetricData) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *CommitmentMetricData) GetFormat() string {
+	if m != nil {
+		return m.Format
+	}
+	return ""
+}
+
+func (m *CommitmentMetricData) GetAccount() string {
+	if m != nil {
+		return m.Account
+	}
+	return ""
+}
+
+func (m *CommitmentMetricData) GetService() string {
+	if m != nil {
+		return m.Service
+	}
+	return ""
+}
+
+func (m *CommitmentMetricData) GetAggregation() []*DateAggregation {
+	if m != nil {
+		return m.Aggregation
+	}
+	return nil
+}
+
+func (m *CommitmentMetricData) GetChange() *ChangeStatistic {
+	if m != nil {
+		return m.Change
+	}
+	return nil
+}
+
+func (m *CommitmentMetricData) GetTrendline() *Trendline {
+	if m != nil {
+		return m.Trendline
+	}
+	return nil
+}
+
+type CommitmentResponse struct {
+	Metrics              []*CommitmentMetricData `protobuf:"bytes,1,rep,name=metrics,proto3" json:"metrics,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                `json:"-"`
+	XXX_unrecognized     []byte                  `json:"-"`
+	XXX_sizecache        int32                   `json:"-"`
+}
+
+func (m *CommitmentResponse) Reset()         { *m = CommitmentResponse{} }
+func (m *CommitmentResponse) String() string { return proto.CompactTextString(m) }
+func (*CommitmentResponse) ProtoMessage()    {}
+func (*CommitmentResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e7502069f31e39f7, []int{33}
+}
+
+func (m *CommitmentResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CommitmentResponse.Unmarshal(m, b)
+}
+func (m *CommitmentResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CommitmentResponse.Marshal(b, m, deterministic)
+}
+func (m *CommitmentResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CommitmentResponse.Merge(m, src)
+}
+func (m *CommitmentResponse) XXX_Size() int {
+	return xxx_messageInfo_CommitmentResponse.Size(m)
+}
+func (m *CommitmentResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CommitmentResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CommitmentResponse proto.InternalMessageInfo
+
+func (m *CommitmentResponse) GetMetrics() []*CommitmentMetricData {
+	if m != nil {
+		return m.Metrics
+	}
+	return nil
+}
+
+type ProductsRequest struct {
+	// (optional) The group id from getUserGroups, the products of the accounts of the group
+	Group string `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	// (optional) The project id from getGroupProjects, the products of the accounts of the
+	// project instead of the group
+	Project string `protobuf:"bytes,2,opt,name=project,proto3" json:"project,omitempty"`
+	// (optional) An ISO 8601 repeating interval string, such as R2/P30D/2021-09-01, the products
+	// with cost in the last cost.products.lookback when not set
+	Intervals            string   `protobuf:"bytes,3,opt,name=intervals,proto3" json:"intervals,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ProductsRequest) Reset()         { *m = ProductsRequest{} }
+func (m *ProductsRequest) String() string { return proto.CompactTextString(m) }
+func (*ProductsRequest) ProtoMessage()    {}
+func (*ProductsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e7502069f31e39f7, []int{34}
+}
+
+func (m *ProductsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ProductsRequest.Unmarshal(m, b)
+}
+func (m *ProductsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ProductsRequest.Marshal(b, m, deterministic)
+}
+func (m *ProductsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ProductsRequest.Merge(m, src)
+}
+func (m *ProductsRequest) XXX_Size() int {
+	return xxx_messageInfo_ProductsRequest.Size(m)
+}
+func (m *ProductsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ProductsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ProductsRequest proto.InternalMessageInfo
+
+func (m *ProductsRequest) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+func (m *ProductsRequest) GetProject() string {
+	if m != nil {
+		return m.Project
+	}
+	return ""
+}
+
+func (m *ProductsRequest) GetIntervals() string {
+	if m != nil {
+		return m.Intervals
+	}
+	return ""
+}
+
+type Product struct {
+	// The stable short id of the product, the product of getProductInsights
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// The Cost Explorer service of the product
+	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Product) Reset()         { *m = Product{} }
+func (m *Product) String() string { return proto.CompactTextString(m) }
+func (*Product) ProtoMessage()    {}
+func (*Product) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e7502069f31e39f7, []int{35}
+}
+
+func (m *Product) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Product.Unmarshal(m, b)
+}
+func (m *Product) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Product.Marshal(b, m, deterministic)
+}
+func (m *Product) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Product.Merge(m, src)
+}
+func (m *Product) XXX_Size() int {
+	return xxx_messageInfo_Product.Size(m)
+}
+func (m *Product) XXX_DiscardUnknown() {
+	xxx_messageInfo_Product.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Product proto.InternalMessageInfo
+
+func (m *Product) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Product) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type ProductsResponse struct {
+	Products             []*Product `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *ProductsResponse) Reset()         { *m = ProductsResponse{} }
+func (m *ProductsResponse) String() string { return proto.CompactTextString(m) }
+func (*ProductsResponse) ProtoMessage()    {}
+func (*ProductsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e7502069f31e39f7, []int{36}
+}
+
+func (m *ProductsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ProductsResponse.Unmarshal(m, b)
+}
+func (m *ProductsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ProductsResponse.Marshal(b, m, deterministic)
+}
+func (m *ProductsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ProductsResponse.Merge(m, src)
+}
+func (m *ProductsResponse) XXX_Size() int {
+	return xxx_messageInfo_ProductsResponse.Size(m)
+}
+func (m *ProductsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ProductsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ProductsResponse proto.InternalMessageInfo
+
+func (m *ProductsResponse) GetProducts() []*Product {
+	if m != nil {
+		return m.Products
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*VersionResponse)(nil), "awscost.VersionResponse")
+	proto.RegisterType((*LastCompleteBillingDateResponse)(nil), "awscost.LastCompleteBillingDateResponse")
+	proto.RegisterType((*Group)(nil), "awscost.Group")
+	proto.RegisterType((*UserGroupsRequest)(nil), "awscost.UserGroupsRequest")
+	proto.RegisterType((*UserGroupsResponse)(nil), "awscost.UserGroupsResponse")
+	proto.RegisterType((*Project)(nil), "awscost.Project")
+	proto.RegisterType((*GroupProjectsRequest)(nil), "awscost.GroupProjectsRequest")
+	proto.RegisterType((*GroupProjectsResponse)(nil), "awscost.GroupProjectsResponse")
+	proto.RegisterType((*DateAggregation)(nil), "awscost.DateAggregation")
+	proto.RegisterType((*ChangeStatistic)(nil), "awscost.ChangeStatistic")
+	proto.RegisterType((*Trendline)(nil), "awscost.Trendline")
+	proto.RegisterType((*ProductCost)(nil), "awscost.ProductCost")
+	proto.RegisterType((*ProjectCost)(nil), "awscost.ProjectCost")
+	proto.RegisterType((*GroupedCosts)(nil), "awscost.GroupedCosts")
+	proto.RegisterType((*GroupDailyCostRequest)(nil), "awscost.GroupDailyCostRequest")
+	proto.RegisterType((*GroupDailyCostResponse)(nil), "awscost.GroupDailyCostResponse")
+	proto.RegisterType((*ProjectDailyCostRequest)(nil), "awscost.ProjectDailyCostRequest")
+	proto.RegisterType((*ProjectDailyCostResponse)(nil), "awscost.ProjectDailyCostResponse")
+	proto.RegisterType((*DailyMetricDataRequest)(nil), "awscost.DailyMetricDataRequest")
+	proto.RegisterType((*DailyMetricDataResponse)(nil), "awscost.DailyMetricDataResponse")
+	proto.RegisterType((*ProductInsightsRequest)(nil), "awscost.ProductInsightsRequest")
+	proto.RegisterType((*Record)(nil), "awscost.Record")
+	proto.RegisterType((*Entity)(nil), "awscost.Entity")
+	proto.RegisterType((*AlertRequest)(nil), "awscost.AlertRequest")
+	proto.RegisterType((*AlertResponse)(nil), "awscost.AlertResponse")
+	proto.RegisterType((*CostForecastRequest)(nil), "awscost.CostForecastRequest")
+	proto.RegisterType((*ForecastAggregation)(nil), "awscost.ForecastAggregation")
+	proto.RegisterType((*CostForecastResponse)(nil), "awscost.CostForecastResponse")
+	proto.RegisterType((*AlertStatusRequest)(nil), "awscost.AlertStatusRequest")
+	proto.RegisterType((*AlertStatus)(nil), "awscost.AlertStatus")
+	proto.RegisterType((*AlertStatusResponse)(nil), "awscost.AlertStatusResponse")
+	proto.RegisterType((*CommitmentRequest)(nil), "awscost.CommitmentRequest")
+	proto.RegisterType((*CommitmentMetricData)(nil), "awscost.CommitmentMetricData")
+	proto.RegisterType((*CommitmentResponse)(nil), "awscost.CommitmentResponse")
+	proto.RegisterType((*ProductsRequest)(nil), "awscost.ProductsRequest")
+	proto.RegisterType((*Product)(nil), "awscost.Product")
+	proto.RegisterType((*ProductsResponse)(nil), "awscost.ProductsResponse")
+}
+
+func init() {
+	proto.RegisterFile("github.com/seizadi/cost-insights-backend/pkg/pb/service.proto", fileDescriptor_e7502069f31e39f7)
+}
+
+var fileDescriptor_e7502069f31e39f7 = []byte{
+	// 2091 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x59, 0x5f, 0x6f, 0x23, 0x49,
+	0x11, 0x97, 0x9d, 0xd8, 0x8e, 0xcb, 0x49, 0x9c, 0x74, 0x12, 0x67, 0xe2, 0x24, 0xb7, 0xb9, 0xe1,
+	0xb8, 0x5b, 0xd8, 0x4d, 0xe6, 0x14, 0x40, 0x2c, 0x07, 0x2b, 0xc8, 0x65, 0x97, 0x68, 0xb5, 0x1c,
+	0x5a, 0x26, 0x17, 0x24, 0x40, 0xc2, 0xea, 0xcc, 0x74, 0x26, 0xb3, 0x3b, 0xff, 0x98, 0x6e, 0x27,
+	0x78, 0x79, 0x41, 0xf0, 0x0d, 0xb8, 0x47, 0x84, 0xf8, 0x02, 0x7c, 0x11, 0x9e, 0x79, 0x43, 0xbc,
+	0x20, 0xf1, 0x39, 0x10, 0xea, 0xee, 0x9a, 0xf1, 0xcc, 0xd8, 0x4e, 0x36, 0x01, 0x09, 0x09, 0xf1,
+	0xe6, 0xea, 0xfa, 0x75, 0xd7, 0xdf, 0xae, 0xae, 0x29, 0xc3, 0x53, 0xcf, 0x17, 0x97, 0xc3, 0xf3,
+	0x03, 0x27, 0x0e, 0x2d, 0xce, 0xfc, 0xb7, 0xd4, 0xf5, 0x2d, 0x27, 0xe6, 0x62, 0xdf, 0x8f, 0xb8,
+	0xef, 0x5d, 0x0a, 0xbe, 0x7f, 0x4e, 0x9d, 0x37, 0x2c, 0x72, 0xad, 0xe4, 0x8d, 0x67, 0x25, 0xe7,
+	0x16, 0x67, 0xe9, 0x95, 0xef, 0xb0, 0x83, 0x24, 0x8d, 0x45, 0x4c, 0x5a, 0xf4, 0x9a, 0x4b, 0x78,
+	0x7f, 0xdb, 0x8b, 0x63, 0x2f, 0x60, 0x96, 0x5a, 0x3e, 0x1f, 0x5e, 0x58, 0x2c, 0x4c, 0xc4, 0x48,
+	0xa3, 0xfa, 0x3b, 0xc8, 0xa4, 0x89, 0x6f, 0xd1, 0x28, 0x8a, 0x05, 0x15, 0x7e, 0x1c, 0x71, 0xe4,
+	0x1e, 0x15, 0x54, 0x60, 0xd1, 0x55, 0x3c, 0x4a, 0xd2, 0xf8, 0x97, 0x23, 0x7d, 0x92, 0xb3, 0xef,
+	0xb1, 0x68, 0xff, 0x8a, 0x06, 0xbe, 0x4b, 0x05, 0xb3, 0x26, 0x7e, 0xe0, 0x11, 0x8f, 0x0b, 0x60,
+	0x7e, 0x4d, 0x3d, 0x8f, 0xa5, 0x56, 0x9c, 0x28, 0x21, 0x93, 0x02, 0xcd, 0x47, 0xd0, 0xfd, 0x31,
+	0x4b, 0xb9, 0x1f, 0x47, 0x36, 0xe3, 0x49, 0x1c, 0x71, 0x46, 0x0c, 0x68, 0x5d, 0xe9, 0x25, 0xa3,
+	0xb6, 0x57, 0x7b, 0xd8, 0xb6, 0x33, 0xd2, 0xfc, 0x06, 0x3c, 0xf8, 0x01, 0xe5, 0xe2, 0x38, 0x0e,
+	0x93, 0x80, 0x09, 0xf6, 0xa9, 0x1f, 0x04, 0x7e, 0xe4, 0x3d, 0xa3, 0x82, 0xe5, 0x9b, 0x09, 0xcc,
+	0x4b, 0x5d, 0x70, 0xa7, 0xfa, 0x6d, 0x6e, 0x42, 0xe3, 0x24, 0x8d, 0x87, 0x09, 0x59, 0x86, 0xba,
+	0xef, 0x22, 0xab, 0xee, 0xbb, 0xe6, 0x63, 0x58, 0x3d, 0xe3, 0x2c, 0x55, 0x4c, 0x6e, 0xb3, 0x5f,
+	0x0c, 0x19, 0x17, 0x64, 0x13, 0x5a, 0x43, 0xce, 0xd2, 0x41, 0x8e, 0x6c, 0x4a, 0xf2, 0x85, 0x6b,
+	0x7e, 0x07, 0x48, 0x11, 0x8d, 0x02, 0x3f, 0x84, 0xa6, 0xa7, 0x56, 0x8c, 0xda, 0xde, 0xdc, 0xc3,
+	0xce, 0xe1, 0xf2, 0x01, 0x86, 0xe1, 0x40, 0x01, 0x6d, 0xe4, 0x9a, 0xfb, 0xd0, 0x7a, 0x95, 0xc6,
+	0xaf, 0x99, 0x23, 0xaa, 0x6a, 0x48, 0x9d, 0x23, 0x1a, 0x32, 0xa3, 0xae, 0x75, 0x96, 0xbf, 0xcd,
+	0xc7, 0xb0, 0xae, 0xf6, 0xe3, 0x9e, 0x5c, 0xbb, 0x75, 0x68, 0xa8, 0x03, 0x71, 0xbb, 0x26, 0xcc,
+	0xe7, 0xb0, 0x51, 0x41, 0xa3, 0x76, 0x8f, 0x61, 0x21, 0xc1, 0x35, 0xd4, 0x6f, 0x25, 0xd7, 0x0f,
+	0xc1, 0x76, 0x8e, 0x30, 0x9f, 0x42, 0x57, 0x3a, 0xf3, 0xc8, 0xf3, 0x52, 0xe6, 0xa9, 0x30, 0x4d,
+	0xf3, 0x27, 0xe9, 0x41, 0x93, 0x86, 0xf1, 0x30, 0x12, 0x4a, 0xe3, 0x9a, 0x8d, 0x94, 0xf9, 0x5d,
+	0xe8, 0x1e, 0x5f, 0xd2, 0xc8, 0x63, 0xa7, 0x32, 0xc6, 0x5c, 0xf8, 0x8e, 0x54, 0x37, 0x95, 0x07,
+	0xa9, 0xfd, 0x75, 0x5b, 0x13, 0x37, 0x1c, 0xd0, 0xfe, 0x3c, 0x65, 0x91, 0x1b, 0xf8, 0x11, 0x93,
+	0x5b, 0x79, 0x10, 0x27, 0x2c, 0xdb, 0xaa, 0x08, 0xb2, 0x03, 0x6d, 0x3f, 0x12, 0x2c, 0x75, 0x58,
+	0xa2, 0x77, 0xd7, 0xed, 0xf1, 0x82, 0xf9, 0x13, 0xe8, 0xbc, 0x4a, 0x63, 0x77, 0xe8, 0x88, 0xe3,
+	0x98, 0x4f, 0x3a, 0xfa, 0x13, 0xe8, 0xd0, 0xb1, 0x6d, 0x46, 0x5d, 0x39, 0xc4, 0xc8, 0x1d, 0x52,
+	0xb1, 0xdd, 0x2e, 0x82, 0xcd, 0x50, 0x1d, 0xfd, 0x9a, 0xfd, 0xe7, 0x8f, 0xce, 0xe3, 0x3f, 0x57,
+	0x88, 0x7f, 0x04, 0x8b, 0x2a, 0xa2, 0xcc, 0x95, 0xe2, 0x38, 0x39, 0x80, 0x56, 0xa2, 0x2d, 0xc3,
+	0x38, 0xae, 0x17, 0xe3, 0x98, 0x59, 0x6c, 0x67, 0x20, 0xc4, 0x4b, 0x75, 0x8d, 0xfa, 0x24, 0xfe,
+	0x35, 0x2b, 0xe0, 0x25, 0x61, 0xbe, 0xc4, 0x0c, 0x7a, 0x46, 0xfd, 0x60, 0xa4, 0x58, 0x37, 0x25,
+	0x5c, 0x1e, 0x86, 0x2b, 0x1a, 0x70, 0xcc, 0xdb, 0xf1, 0x82, 0xf9, 0xfb, 0x3a, 0xf4, 0xaa, 0xa7,
+	0x61, 0x42, 0x56, 0xfd, 0xd6, 0x83, 0xe6, 0x45, 0x9c, 0x86, 0x54, 0xe0, 0x29, 0x48, 0x55, 0xfd,
+	0x39, 0x77, 0x17, 0x7f, 0x7e, 0x0c, 0x4d, 0x47, 0xe5, 0xa1, 0x31, 0xbf, 0x57, 0x2b, 0x6d, 0xab,
+	0xa4, 0xa7, 0x8d, 0x38, 0xf2, 0x31, 0xb4, 0x45, 0x96, 0x78, 0x46, 0x43, 0x6d, 0x22, 0xf9, 0xa6,
+	0x3c, 0x25, 0xed, 0x31, 0x88, 0x7c, 0x0b, 0x16, 0xbd, 0x42, 0x7c, 0x8c, 0xa6, 0xda, 0xb4, 0x51,
+	0xbe, 0xfc, 0xc8, 0xb4, 0x4b, 0x50, 0xf3, 0x47, 0xb0, 0x89, 0x21, 0x98, 0x70, 0xb6, 0x31, 0x8e,
+	0x1a, 0x96, 0x3e, 0x24, 0x6f, 0x71, 0xf8, 0x1f, 0xea, 0x60, 0x4c, 0x9e, 0xf9, 0x7f, 0x97, 0x67,
+	0x2e, 0xff, 0x21, 0xf4, 0x94, 0x5f, 0x3e, 0x63, 0x22, 0xf5, 0x9d, 0x67, 0x54, 0xd0, 0xcc, 0xe3,
+	0x3d, 0x68, 0x86, 0x6a, 0x31, 0x2b, 0xf6, 0x9a, 0xba, 0xc5, 0xdf, 0x7f, 0xaf, 0xc1, 0xe6, 0xc4,
+	0x81, 0xff, 0x5b, 0xee, 0x36, 0x7f, 0x5d, 0x83, 0x1e, 0x96, 0x96, 0x17, 0xd8, 0x7f, 0x94, 0xd3,
+	0x14, 0x8b, 0x51, 0x96, 0xa6, 0x92, 0x1c, 0x57, 0x8b, 0xfa, 0xcc, 0x6a, 0x31, 0x57, 0x71, 0x66,
+	0x31, 0xe9, 0xe7, 0x4b, 0x49, 0x6f, 0xfe, 0xb5, 0x0e, 0x4d, 0x9b, 0x39, 0x71, 0xea, 0x92, 0x2f,
+	0x43, 0x83, 0x5d, 0xb1, 0x28, 0xab, 0x7e, 0xdd, 0x5c, 0xf7, 0xe7, 0x91, 0xf0, 0xc5, 0xc8, 0xd6,
+	0x5c, 0xf2, 0x15, 0x68, 0x61, 0x53, 0x64, 0xd4, 0xa7, 0x03, 0x33, 0x3e, 0xb1, 0x00, 0x5c, 0x96,
+	0x04, 0xf1, 0x28, 0x94, 0xc7, 0xce, 0x4d, 0x47, 0x17, 0x20, 0xe4, 0x7d, 0x98, 0x3b, 0x7d, 0x79,
+	0x66, 0xcc, 0x4f, 0x47, 0x4a, 0x1e, 0xf9, 0x08, 0x9a, 0xe7, 0x43, 0xe7, 0x0d, 0x13, 0x46, 0x63,
+	0x3a, 0x0a, 0xd9, 0xe4, 0x11, 0x2c, 0x24, 0x7e, 0xc2, 0x54, 0x34, 0x9a, 0xd3, 0xa1, 0x39, 0x40,
+	0x1a, 0xe5, 0x52, 0x41, 0x39, 0x13, 0x46, 0x6b, 0x86, 0x51, 0xc8, 0x97, 0xd0, 0x2c, 0x32, 0x0b,
+	0x33, 0xa0, 0xc8, 0x37, 0xff, 0xd6, 0x84, 0xa6, 0x5e, 0x93, 0x0f, 0x90, 0x18, 0x25, 0xf9, 0x23,
+	0x2f, 0x7f, 0x63, 0x1a, 0xd7, 0xf3, 0x34, 0xde, 0x9b, 0x4c, 0xd7, 0x5a, 0x39, 0x29, 0x1f, 0xc1,
+	0x02, 0x93, 0xe7, 0xf9, 0x8c, 0x63, 0x5a, 0x8e, 0x85, 0xeb, 0x28, 0xda, 0x39, 0xa0, 0x90, 0xc1,
+	0x8d, 0x77, 0xcc, 0xe0, 0x1d, 0x68, 0x73, 0x41, 0x53, 0x21, 0x2f, 0x86, 0xba, 0xfb, 0x6d, 0x7b,
+	0xbc, 0x20, 0x93, 0x88, 0x45, 0xae, 0xe2, 0xb5, 0x74, 0x12, 0x21, 0x59, 0x4c, 0xaf, 0x85, 0x72,
+	0x4d, 0xdd, 0x83, 0x4e, 0xc2, 0x52, 0x3f, 0x76, 0x4f, 0xe5, 0x31, 0x46, 0x5b, 0x71, 0x8b, 0x4b,
+	0x52, 0xa6, 0x26, 0x9f, 0x47, 0xae, 0x01, 0x5a, 0x66, 0xbe, 0x20, 0xf7, 0x07, 0xf4, 0x9c, 0x05,
+	0xba, 0xca, 0x18, 0x1d, 0xd5, 0xcb, 0x14, 0x97, 0xc8, 0x07, 0xb0, 0x34, 0x8c, 0x8a, 0x98, 0x45,
+	0x85, 0x29, 0x2f, 0xaa, 0x64, 0xc8, 0x9a, 0xb4, 0xa5, 0x59, 0xc9, 0x80, 0x00, 0x04, 0xcb, 0x08,
+	0x72, 0x63, 0x79, 0x36, 0xd8, 0x1d, 0x22, 0x18, 0xd3, 0x9d, 0x1b, 0xdd, 0x19, 0xe0, 0x0c, 0x40,
+	0xfa, 0x12, 0x7c, 0xc5, 0x52, 0x5f, 0x8c, 0x8c, 0x15, 0x65, 0x6b, 0x4e, 0x4b, 0x43, 0x5c, 0x3a,
+	0xe2, 0x36, 0x0b, 0xa9, 0x1f, 0xf9, 0x91, 0x67, 0xac, 0xee, 0xd5, 0x1e, 0x36, 0xec, 0xf2, 0xa2,
+	0x74, 0x57, 0x2c, 0x7b, 0xf5, 0x84, 0x45, 0xae, 0x41, 0x94, 0xa9, 0xe3, 0x05, 0x19, 0x08, 0x1a,
+	0xb0, 0x54, 0xbc, 0x70, 0x8d, 0x35, 0x1d, 0x08, 0x24, 0x65, 0x89, 0xe4, 0x82, 0x8a, 0x21, 0x37,
+	0xd6, 0x75, 0x89, 0xd4, 0x94, 0x74, 0xb0, 0xfe, 0x75, 0x16, 0x09, 0x3f, 0x30, 0x36, 0x74, 0x80,
+	0x0a, 0x4b, 0x2a, 0x73, 0x59, 0x1a, 0x1a, 0x3d, 0xcc, 0x5c, 0x96, 0x86, 0xe4, 0x43, 0x58, 0x0e,
+	0xe3, 0x48, 0x5c, 0x06, 0xa3, 0x53, 0x7a, 0xe5, 0x47, 0x1e, 0x37, 0x36, 0x95, 0x2a, 0x95, 0x55,
+	0x62, 0xc2, 0xa2, 0x1f, 0x71, 0x41, 0x23, 0x87, 0x7d, 0x2e, 0xb3, 0xdf, 0x50, 0x67, 0x94, 0xd6,
+	0xc8, 0x13, 0xd8, 0x4c, 0x99, 0x13, 0x87, 0x21, 0x8b, 0x5c, 0xe6, 0xbe, 0x28, 0xc2, 0xb7, 0x14,
+	0x7c, 0x16, 0xdb, 0xfc, 0x00, 0x16, 0x8f, 0xa4, 0x79, 0x37, 0x37, 0xee, 0x4f, 0x60, 0x09, 0x51,
+	0xf8, 0x7a, 0x7c, 0x04, 0x4d, 0xe5, 0x15, 0x3e, 0xab, 0xd0, 0x21, 0xdb, 0xfc, 0xa2, 0x06, 0x6b,
+	0x32, 0x7b, 0xbe, 0x1f, 0xa7, 0xcc, 0xa1, 0xb7, 0xf5, 0x6b, 0x46, 0xb1, 0x1d, 0x2c, 0x5d, 0x02,
+	0x03, 0x5a, 0x97, 0x71, 0xea, 0xbf, 0x55, 0x77, 0x5a, 0x71, 0x90, 0xcc, 0x5b, 0xff, 0xf9, 0x42,
+	0xeb, 0xff, 0x1e, 0x80, 0x13, 0x47, 0x17, 0xbe, 0xcb, 0x22, 0x47, 0x5f, 0xdd, 0x9a, 0x5d, 0x58,
+	0x31, 0x43, 0x58, 0xcb, 0x14, 0xba, 0xe7, 0x57, 0x84, 0x34, 0x20, 0x88, 0xaf, 0x59, 0xaa, 0xd4,
+	0xa9, 0xd9, 0x9a, 0x90, 0xab, 0xc3, 0x24, 0x61, 0xa9, 0xd2, 0xa6, 0x66, 0x6b, 0xc2, 0xfc, 0xf3,
+	0x1c, 0xac, 0x97, 0x9d, 0x70, 0xc7, 0x47, 0x78, 0xb6, 0xf5, 0xbb, 0x00, 0xaa, 0xba, 0x0c, 0x0a,
+	0x3e, 0x28, 0xd4, 0x9b, 0x2d, 0x59, 0xec, 0x5c, 0xcd, 0x6c, 0x94, 0x0b, 0x4e, 0xe5, 0x61, 0x6f,
+	0xde, 0xe5, 0x61, 0x7f, 0x02, 0x0b, 0x17, 0x68, 0x0b, 0xd6, 0xfa, 0x9d, 0x7c, 0xe3, 0x14, 0xc7,
+	0xda, 0x39, 0x5a, 0xb9, 0xd3, 0x11, 0x43, 0x1a, 0x18, 0x0b, 0xe8, 0x4e, 0x45, 0x91, 0x43, 0x68,
+	0x88, 0x58, 0xd0, 0x40, 0x95, 0xb7, 0xdb, 0x8e, 0xd3, 0x50, 0xe9, 0xec, 0x30, 0x76, 0x59, 0x80,
+	0x25, 0x4f, 0x13, 0x95, 0xd8, 0x77, 0xaa, 0xb1, 0x27, 0x4f, 0xa1, 0xe3, 0xa4, 0x31, 0xe7, 0x03,
+	0xe7, 0x92, 0x39, 0x6f, 0x8c, 0xc5, 0x77, 0x90, 0x07, 0x6a, 0xc3, 0xb1, 0xc4, 0x9b, 0x7f, 0xaa,
+	0x01, 0x51, 0x77, 0xe1, 0x54, 0xdd, 0xef, 0x5b, 0xf3, 0x39, 0xab, 0x25, 0xf5, 0x59, 0xb5, 0x64,
+	0xae, 0x54, 0x4b, 0x7a, 0xd0, 0x4c, 0x19, 0xe5, 0x71, 0x84, 0xb1, 0x44, 0x4a, 0x56, 0xbd, 0x0b,
+	0xc6, 0x5c, 0x39, 0x59, 0xc1, 0x40, 0xe6, 0xb4, 0xe4, 0xb9, 0xc3, 0x34, 0x0b, 0xa3, 0xe2, 0x65,
+	0xb4, 0xf9, 0xc7, 0x1a, 0x74, 0x0a, 0xea, 0xfe, 0x57, 0xf5, 0x94, 0x97, 0x43, 0x55, 0x48, 0xad,
+	0xa4, 0x26, 0xcc, 0x63, 0x58, 0x2b, 0xf9, 0x33, 0x1f, 0x09, 0x64, 0x82, 0x6b, 0x7b, 0xb5, 0xd2,
+	0x87, 0x61, 0x11, 0x8d, 0x18, 0xf3, 0x57, 0xb0, 0x7a, 0x1c, 0x87, 0xa1, 0x2f, 0x64, 0x0b, 0xf4,
+	0x6f, 0xd4, 0x98, 0xac, 0x2b, 0xc3, 0x5b, 0x86, 0x64, 0xb9, 0x33, 0x9c, 0xaf, 0xb6, 0xd9, 0xbf,
+	0xab, 0xc3, 0xfa, 0x58, 0xfa, 0xb8, 0xd7, 0xbe, 0xcb, 0xf5, 0xa6, 0x8e, 0xa3, 0x8a, 0x0c, 0x0a,
+	0x46, 0xb2, 0xa8, 0xd2, 0x7c, 0x59, 0xa5, 0xca, 0xf5, 0x6d, 0xdc, 0xaf, 0x2f, 0x6f, 0xde, 0xa7,
+	0x2f, 0x6f, 0xbd, 0x4b, 0x5f, 0xfe, 0x19, 0x90, 0x62, 0x44, 0x30, 0xaa, 0xdf, 0x84, 0x96, 0xfe,
+	0x72, 0xc9, 0x1e, 0x8e, 0xdd, 0xb1, 0xe8, 0x29, 0x1e, 0xb4, 0x33, 0xb4, 0x39, 0x80, 0x2e, 0x76,
+	0xf9, 0xfc, 0xbe, 0xe1, 0xbd, 0xb1, 0xbd, 0xc7, 0xc1, 0x97, 0x14, 0xf0, 0x4e, 0x83, 0xaf, 0xef,
+	0xc1, 0xca, 0x58, 0x9f, 0xd2, 0x14, 0x4b, 0xad, 0x4d, 0x9b, 0x62, 0x49, 0xc6, 0xb8, 0xe9, 0x39,
+	0xfc, 0x19, 0xb4, 0x8e, 0xae, 0xb9, 0xea, 0xac, 0x5e, 0x01, 0x9c, 0x30, 0x81, 0x03, 0x46, 0xd2,
+	0x3b, 0xd0, 0xb3, 0xcf, 0x83, 0x6c, 0x30, 0x7a, 0xf0, 0x5c, 0x0e, 0x46, 0xfb, 0xe3, 0x28, 0x55,
+	0x46, 0x91, 0xe6, 0xca, 0x6f, 0xfe, 0xf2, 0x8f, 0x2f, 0xea, 0x40, 0x16, 0x2c, 0x1c, 0x41, 0x1e,
+	0xfe, 0x13, 0xa0, 0x2b, 0x8f, 0xce, 0x3e, 0x89, 0x8e, 0x12, 0x9f, 0xfc, 0xb6, 0x06, 0xfd, 0x13,
+	0x26, 0x66, 0x8c, 0x26, 0x67, 0x8a, 0x7d, 0x98, 0x8b, 0xbd, 0x65, 0xa8, 0x69, 0x7e, 0x49, 0xa9,
+	0xb1, 0x4b, 0xb6, 0xad, 0x80, 0x72, 0x31, 0x70, 0x10, 0x3a, 0x38, 0xd7, 0x58, 0xf5, 0x0a, 0x91,
+	0x9f, 0xc3, 0xd2, 0x09, 0x13, 0xe3, 0x09, 0x25, 0xe9, 0xe7, 0xe7, 0x4f, 0x0c, 0x39, 0xfb, 0xdb,
+	0x53, 0x79, 0x28, 0x6e, 0x5d, 0x89, 0x5b, 0x26, 0x8b, 0x96, 0x1a, 0x84, 0x7a, 0xfa, 0xb8, 0xd7,
+	0xb0, 0x72, 0xc2, 0x44, 0x69, 0xcc, 0x48, 0x76, 0xcb, 0x1f, 0xdf, 0x95, 0x61, 0x65, 0xff, 0xbd,
+	0x59, 0x6c, 0x14, 0xb4, 0xa9, 0x04, 0xad, 0x92, 0xae, 0xa5, 0x64, 0x0c, 0xf2, 0x26, 0x97, 0x03,
+	0x39, 0x61, 0xa2, 0xf2, 0x85, 0x4d, 0x1e, 0x14, 0x2e, 0xe1, 0xb4, 0x8f, 0xf9, 0xfe, 0xde, 0x6c,
+	0x00, 0x4a, 0xec, 0x2b, 0x89, 0xeb, 0x84, 0x58, 0xae, 0x44, 0x0c, 0xf4, 0x2d, 0x90, 0x0e, 0xa4,
+	0x24, 0x86, 0xd5, 0xcc, 0xc0, 0x7c, 0x88, 0x42, 0x2a, 0x26, 0x54, 0x27, 0x36, 0xfd, 0x07, 0x33,
+	0xf9, 0x28, 0x71, 0x4b, 0x49, 0x5c, 0x23, 0xab, 0x68, 0xa3, 0x96, 0x2b, 0x77, 0x10, 0xaa, 0xac,
+	0xac, 0x7c, 0x63, 0x17, 0xac, 0x9c, 0xfe, 0xf5, 0xdd, 0xaf, 0xb6, 0x84, 0x05, 0x11, 0x78, 0x07,
+	0x06, 0xd9, 0x1f, 0x06, 0xe4, 0x1a, 0xd6, 0xb4, 0x88, 0xd2, 0x68, 0x88, 0xec, 0x55, 0x87, 0x81,
+	0x13, 0x76, 0xbd, 0x7f, 0x03, 0x02, 0x2d, 0xdb, 0x56, 0x62, 0x37, 0xc8, 0x9a, 0x85, 0x71, 0x2b,
+	0xda, 0xf6, 0x12, 0xda, 0x27, 0x4c, 0xa8, 0x17, 0x85, 0x93, 0x8d, 0xf2, 0x13, 0x93, 0xc9, 0xe8,
+	0x55, 0x97, 0xf1, 0xe0, 0xae, 0x3a, 0xb8, 0x4d, 0x5a, 0x96, 0xee, 0x75, 0xc9, 0x05, 0x74, 0x4f,
+	0x98, 0x28, 0x36, 0x7a, 0x64, 0xa7, 0x50, 0xde, 0x26, 0x9a, 0xe0, 0xfe, 0xee, 0x0c, 0x2e, 0x0a,
+	0xe8, 0x29, 0x01, 0x2b, 0x64, 0x59, 0xfd, 0xbd, 0x32, 0xc8, 0x7b, 0x28, 0x06, 0xcb, 0xa7, 0xa8,
+	0x34, 0xbe, 0xea, 0xdb, 0x53, 0x1f, 0x47, 0x94, 0xb2, 0x33, 0x9d, 0x89, 0x42, 0x0c, 0x25, 0x84,
+	0x98, 0x4b, 0xda, 0x8a, 0x81, 0x7e, 0x50, 0x3f, 0xa9, 0x7d, 0x95, 0x44, 0xb0, 0xa1, 0xcc, 0xc9,
+	0xca, 0xf2, 0xb1, 0xfc, 0x46, 0xa2, 0x1e, 0x2b, 0xdc, 0xd8, 0x89, 0x37, 0xb7, 0xbf, 0x3d, 0x95,
+	0x87, 0xb2, 0x76, 0x94, 0xac, 0x1e, 0x59, 0xb7, 0x9c, 0x9c, 0x39, 0x70, 0xb2, 0x63, 0x05, 0x18,
+	0x25, 0x79, 0x67, 0xc2, 0x0f, 0xfc, 0xb7, 0xfa, 0xc5, 0xba, 0xb7, 0xc8, 0x07, 0x4a, 0xe4, 0x16,
+	0xd9, 0x2c, 0x8a, 0x1c, 0x16, 0x4e, 0x3e, 0x83, 0xce, 0x38, 0xbb, 0x39, 0x31, 0xaa, 0x69, 0x9d,
+	0xbb, 0x71, 0x6b, 0x0a, 0x07, 0x85, 0xac, 0x2a, 0x21, 0x1d, 0xd2, 0xce, 0x32, 0x9b, 0x7f, 0xfa,
+	0xf5, 0x9f, 0x1e, 0xde, 0xf1, 0x6f, 0xb2, 0x6f, 0x27, 0xe7, 0xe7, 0x4d, 0x55, 0x7b, 0xbf, 0xf6,
+	0xaf, 0x01, 0x00, 0x06, 0x0f, 0x93, 0xbb, 0x63, 0x1b, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// AwsCostClient is the client API for AwsCost service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type AwsCostClient interface {
+	GetVersion(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*VersionResponse, error)
+}
+
+type awsCostClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewAwsCostClient(cc *grpc.ClientConn) AwsCostClient {
+	return &awsCostClient{cc}
+}
+
+func (c *awsCostClient) GetVersion(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*VersionResponse, error) {
+	out := new(VersionResponse)
+	err := c.cc.Invoke(ctx, "/awscost.AwsCost/GetVersion", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AwsCostServer is the server API for AwsCost service.
+type AwsCostServer interface {
+	GetVersion(context.Context, *empty.Empty) (*VersionResponse, error)
+}
+
+func RegisterAwsCostServer(s *grpc.Server, srv AwsCostServer) {
+	s.RegisterService(&_AwsCost_serviceDesc, srv)
+}
+
+func _AwsCost_GetVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AwsCostServer).GetVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/awscost.AwsCost/GetVersion",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AwsCostServer).GetVersion(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _AwsCost_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "awscost.AwsCost",
+	HandlerType: (*AwsCostServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetVersion",
+			Handler:    _AwsCost_GetVersion_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "github.com/seizadi/cost-insights-backend/pkg/pb/service.proto",
+}
+
+// CostInsightsApiClient is the client API for CostInsightsApi service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type CostInsightsApiClient interface {
+	GetLastCompleteBillingDate(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*LastCompleteBillingDateResponse, error)
+	GetUserGroups(ctx context.Context, in *UserGroupsRequest, opts ...grpc.CallOption) (*UserGroupsResponse, error)
+	GetGroupProjects(ctx context.Context, in *GroupProjectsRequest, opts ...grpc.CallOption) (*GroupProjectsResponse, error)
+	GetDailyMetricData(ctx context.Context, in *DailyMetricDataRequest, opts ...grpc.CallOption) (*DailyMetricDataResponse, error)
+	GetGroupDailyCost(ctx context.Context, in *GroupDailyCostRequest, opts ...grpc.CallOption) (*GroupDailyCostResponse, error)
+	GetProductInsights(ctx context.Context, in *ProductInsightsRequest, opts ...grpc.CallOption) (*Entity, error)
+	GetProjectDailyCost(ctx context.Context, in *ProjectDailyCostRequest, opts ...grpc.CallOption) (*ProjectDailyCostResponse, error)
+	GetAlerts(ctx context.Context, in *AlertRequest, opts ...grpc.CallOption) (*AlertResponse, error)
+	GetCostForecast(ctx context.Context, in *CostForecastRequest, opts ...grpc.CallOption) (*CostForecastResponse, error)
+	SetAlertStatus(ctx context.Context, in *AlertStatusRequest, opts ...grpc.CallOption) (*AlertStatusResponse, error)
+	GetCommitmentCoverage(ctx context.Context, in *CommitmentRequest, opts ...grpc.CallOption) (*CommitmentResponse, error)
+	GetCommitmentUtilization(ctx context.Context, in *CommitmentRequest, opts ...grpc.CallOption) (*CommitmentResponse, error)
+	GetProducts(ctx context.Context, in *ProductsRequest, opts ...grpc.CallOption) (*ProductsResponse, error)
+}
+
+type costInsightsApiClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewCostInsightsApiClient(cc *grpc.ClientConn) CostInsightsApiClient {
+	return &costInsightsApiClient{cc}
+}
+
+func (c *costInsightsApiClient) GetLastCompleteBillingDate(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*LastCompleteBillingDateResponse, error) {
+	out := new(LastCompleteBillingDateResponse)
+	err := c.cc.Invoke(ctx, "/awscost.CostInsightsApi/GetLastCompleteBillingDate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *costInsightsApiClient) GetUserGroups(ctx context.Context, in *UserGroupsRequest, opts ...grpc.CallOption) (*UserGroupsResponse, error) {
+	out := new(UserGroupsResponse)
+	err := c.cc.Invoke(ctx, "/awscost.CostInsightsApi/GetUserGroups", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *costInsightsApiClient) GetGroupProjects(ctx context.Context, in *GroupProjectsRequest, opts ...grpc.CallOption) (*GroupProjectsResponse, error) {
+	out := new(GroupProjectsResponse)
+	err := c.cc.Invoke(ctx, "/awscost.CostInsightsApi/GetGroupProjects", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *costInsightsApiClient) GetDailyMetricData(ctx context.Context, in *DailyMetricDataRequest, opts ...grpc.CallOption) (*DailyMetricDataResponse, error) {
+	out := new(DailyMetricDataResponse)
+	err := c.cc.Invoke(ctx, "/awscost.CostInsightsApi/GetDailyMetricData", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *costInsightsApiClient) GetGroupDailyCost(ctx context.Context, in *GroupDailyCostRequest, opts ...grpc.CallOption) (*GroupDailyCostResponse, error) {
+	out := new(GroupDailyCostResponse)
+	err := c.cc.Invoke(ctx, "/awscost.CostInsightsApi/GetGroupDailyCost", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
 func (c *costInsightsApiClient) GetProductInsights(ctx context.Context, in *ProductInsightsRequest, opts ...grpc.CallOption) (*Entity, error) {
@@ -1686,6 +2684,51 @@ func (c *costInsightsApiClient) GetAlerts(ctx context.Context, in *AlertRequest,
 	return out, nil
 }
 
+func (c *costInsightsApiClient) GetCostForecast(ctx context.Context, in *CostForecastRequest, opts ...grpc.CallOption) (*CostForecastResponse, error) {
+	out := new(CostForecastResponse)
+	err := c.cc.Invoke(ctx, "/awscost.CostInsightsApi/GetCostForecast", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *costInsightsApiClient) SetAlertStatus(ctx context.Context, in *AlertStatusRequest, opts ...grpc.CallOption) (*AlertStatusResponse, error) {
+	out := new(AlertStatusResponse)
+	err := c.cc.Invoke(ctx, "/awscost.CostInsightsApi/SetAlertStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *costInsightsApiClient) GetCommitmentCoverage(ctx context.Context, in *CommitmentRequest, opts ...grpc.CallOption) (*CommitmentResponse, error) {
+	out := new(CommitmentResponse)
+	err := c.cc.Invoke(ctx, "/awscost.CostInsightsApi/GetCommitmentCoverage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *costInsightsApiClient) GetCommitmentUtilization(ctx context.Context, in *CommitmentRequest, opts ...grpc.CallOption) (*CommitmentResponse, error) {
+	out := new(CommitmentResponse)
+	err := c.cc.Invoke(ctx, "/awscost.CostInsightsApi/GetCommitmentUtilization", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *costInsightsApiClient) GetProducts(ctx context.Context, in *ProductsRequest, opts ...grpc.CallOption) (*ProductsResponse, error) {
+	out := new(ProductsResponse)
+	err := c.cc.Invoke(ctx, "/awscost.CostInsightsApi/GetProducts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CostInsightsApiServer is the server API for CostInsightsApi service.
 type CostInsightsApiServer interface {
 	GetLastCompleteBillingDate(context.Context, *empty.Empty) (*LastCompleteBillingDateResponse, error)
@@ -1696,6 +2739,11 @@ type CostInsightsApiServer interface {
 	GetProductInsights(context.Context, *ProductInsightsRequest) (*Entity, error)
 	GetProjectDailyCost(context.Context, *ProjectDailyCostRequest) (*ProjectDailyCostResponse, error)
 	GetAlerts(context.Context, *AlertRequest) (*AlertResponse, error)
+	GetCostForecast(context.Context, *CostForecastRequest) (*CostForecastResponse, error)
+	SetAlertStatus(context.Context, *AlertStatusRequest) (*AlertStatusResponse, error)
+	GetCommitmentCoverage(context.Context, *CommitmentRequest) (*CommitmentResponse, error)
+	GetCommitmentUtilization(context.Context, *CommitmentRequest) (*CommitmentResponse, error)
+	GetProducts(context.Context, *ProductsRequest) (*ProductsResponse, error)
 }
 
 func RegisterCostInsightsApiServer(s *grpc.Server, srv CostInsightsApiServer) {
@@ -1846,6 +2894,96 @@ func _CostInsightsApi_GetAlerts_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CostInsightsApi_GetCostForecast_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CostForecastRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CostInsightsApiServer).GetCostForecast(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/awscost.CostInsightsApi/GetCostForecast",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CostInsightsApiServer).GetCostForecast(ctx, req.(*CostForecastRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CostInsightsApi_SetAlertStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AlertStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CostInsightsApiServer).SetAlertStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/awscost.CostInsightsApi/SetAlertStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CostInsightsApiServer).SetAlertStatus(ctx, req.(*AlertStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CostInsightsApi_GetCommitmentCoverage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CommitmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CostInsightsApiServer).GetCommitmentCoverage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/awscost.CostInsightsApi/GetCommitmentCoverage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CostInsightsApiServer).GetCommitmentCoverage(ctx, req.(*CommitmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CostInsightsApi_GetCommitmentUtilization_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CommitmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CostInsightsApiServer).GetCommitmentUtilization(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/awscost.CostInsightsApi/GetCommitmentUtilization",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CostInsightsApiServer).GetCommitmentUtilization(ctx, req.(*CommitmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CostInsightsApi_GetProducts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProductsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CostInsightsApiServer).GetProducts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/awscost.CostInsightsApi/GetProducts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CostInsightsApiServer).GetProducts(ctx, req.(*ProductsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _CostInsightsApi_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "awscost.CostInsightsApi",
 	HandlerType: (*CostInsightsApiServer)(nil),
@@ -1882,6 +3020,26 @@ var _CostInsightsApi_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetAlerts",
 			Handler:    _CostInsightsApi_GetAlerts_Handler,
 		},
+		{
+			MethodName: "GetCostForecast",
+			Handler:    _CostInsightsApi_GetCostForecast_Handler,
+		},
+		{
+			MethodName: "SetAlertStatus",
+			Handler:    _CostInsightsApi_SetAlertStatus_Handler,
+		},
+		{
+			MethodName: "GetCommitmentCoverage",
+			Handler:    _CostInsightsApi_GetCommitmentCoverage_Handler,
+		},
+		{
+			MethodName: "GetCommitmentUtilization",
+			Handler:    _CostInsightsApi_GetCommitmentUtilization_Handler,
+		},
+		{
+			MethodName: "GetProducts",
+			Handler:    _CostInsightsApi_GetProducts_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "github.com/seizadi/cost-insights-backend/pkg/pb/service.proto",
@@ -1971,6 +1971,26 @@ func (m *Entity) Validate() error {
 
 	}
 
+	// no validation rules for Severity
+
+	// no validation rules for DaysRemaining
+
+	// no validation rules for Overspend
+
+	// no validation rules for AlertId
+
+	// no validation rules for Status
+
+	// no validation rules for StatusUntil
+
+	// no validation rules for Term
+
+	// no validation rules for MonthlySavings
+
+	// no validation rules for InstanceType
+
+	// no validation rules for RecommendedInstanceType
+
 	return nil
 }
 
@@ -2174,3 +2194,1005 @@ var _ interface {
 	Cause() error
 	ErrorName() string
 } = AlertResponseValidationError{}
+
+// Validate checks the field values on CostForecastRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, an error is returned.
+func (m *CostForecastRequest) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	// no validation rules for Group
+
+	// no validation rules for Project
+
+	// no validation rules for Horizon
+
+	// no validation rules for Date
+
+	// no validation rules for Confidence
+
+	return nil
+}
+
+// CostForecastRequestValidationError is the validation error returned by
+// CostForecastRequest.Validate if the designated constraints aren't met.
+type CostForecastRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e CostForecastRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e CostForecastRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e CostForecastRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e CostForecastRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e CostForecastRequestValidationError) ErrorName() string {
+	return "CostForecastRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e CostForecastRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sCostForecastRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = CostForecastRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = CostForecastRequestValidationError{}
+
+// Validate checks the field values on ForecastAggregation with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, an error is returned.
+func (m *ForecastAggregation) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	// no validation rules for Date
+
+	// no validation rules for Amount
+
+	// no validation rules for Lower
+
+	// no validation rules for Upper
+
+	return nil
+}
+
+// ForecastAggregationValidationError is the validation error returned by
+// ForecastAggregation.Validate if the designated constraints aren't met.
+type ForecastAggregationValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ForecastAggregationValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ForecastAggregationValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ForecastAggregationValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ForecastAggregationValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ForecastAggregationValidationError) ErrorName() string {
+	return "ForecastAggregationValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ForecastAggregationValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sForecastAggregation.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ForecastAggregationValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ForecastAggregationValidationError{}
+
+// Validate checks the field values on CostForecastResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, an error is returned.
+func (m *CostForecastResponse) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	// no validation rules for Id
+
+	// no validation rules for Format
+
+	// no validation rules for Horizon
+
+	// no validation rules for StartDate
+
+	// no validation rules for EndDate
+
+	for idx, item := range m.GetAggregation() {
+		_, _ = idx, item
+
+		if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return CostForecastResponseValidationError{
+					field:  fmt.Sprintf("Aggregation[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	for idx, item := range m.GetForecast() {
+		_, _ = idx, item
+
+		if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return CostForecastResponseValidationError{
+					field:  fmt.Sprintf("Forecast[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	// no validation rules for Actual
+
+	if v, ok := interface{}(m.GetTotal()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return CostForecastResponseValidationError{
+				field:  "Total",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	// no validation rules for Model
+
+	// no validation rules for Confidence
+
+	if v, ok := interface{}(m.GetCrossCheck()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return CostForecastResponseValidationError{
+				field:  "CrossCheck",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	return nil
+}
+
+// CostForecastResponseValidationError is the validation error returned by
+// CostForecastResponse.Validate if the designated constraints aren't met.
+type CostForecastResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e CostForecastResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e CostForecastResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e CostForecastResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e CostForecastResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e CostForecastResponseValidationError) ErrorName() string {
+	return "CostForecastResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e CostForecastResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sCostForecastResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = CostForecastResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = CostForecastResponseValidationError{}
+
+// Validate checks the field values on AlertStatusRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, an error is returned.
+func (m *AlertStatusRequest) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	// no validation rules for Group
+
+	// no validation rules for AlertId
+
+	// no validation rules for Status
+
+	// no validation rules for Reason
+
+	// no validation rules for Feedback
+
+	// no validation rules for Duration
+
+	return nil
+}
+
+// AlertStatusRequestValidationError is the validation error returned by
+// AlertStatusRequest.Validate if the designated constraints aren't met.
+type AlertStatusRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e AlertStatusRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e AlertStatusRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e AlertStatusRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e AlertStatusRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e AlertStatusRequestValidationError) ErrorName() string {
+	return "AlertStatusRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e AlertStatusRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sAlertStatusRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = AlertStatusRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = AlertStatusRequestValidationError{}
+
+// Validate checks the field values on AlertStatus with the rules defined in
+// the proto definition for this message. If any rules are violated, an error
+// is returned.
+func (m *AlertStatus) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	// no validation rules for Group
+
+	// no validation rules for AlertId
+
+	// no validation rules for Status
+
+	// no validation rules for Reason
+
+	// no validation rules for Feedback
+
+	// no validation rules for Until
+
+	return nil
+}
+
+// AlertStatusValidationError is the validation error returned by
+// AlertStatus.Validate if the designated constraints aren't met.
+type AlertStatusValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e AlertStatusValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e AlertStatusValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e AlertStatusValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e AlertStatusValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e AlertStatusValidationError) ErrorName() string { return "AlertStatusValidationError" }
+
+// Error satisfies the builtin error interface
+func (e AlertStatusValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sAlertStatus.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = AlertStatusValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = AlertStatusValidationError{}
+
+// Validate checks the field values on AlertStatusResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, an error is returned.
+func (m *AlertStatusResponse) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	if v, ok := interface{}(m.GetStatus()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return AlertStatusResponseValidationError{
+				field:  "Status",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	return nil
+}
+
+// AlertStatusResponseValidationError is the validation error returned by
+// AlertStatusResponse.Validate if the designated constraints aren't met.
+type AlertStatusResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e AlertStatusResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e AlertStatusResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e AlertStatusResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e AlertStatusResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e AlertStatusResponseValidationError) ErrorName() string {
+	return "AlertStatusResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e AlertStatusResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sAlertStatusResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = AlertStatusResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = AlertStatusResponseValidationError{}
+
+// Validate checks the field values on CommitmentRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, an
+// error is returned.
+func (m *CommitmentRequest) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	// no validation rules for Group
+
+	// no validation rules for Project
+
+	// no validation rules for Service
+
+	// no validation rules for Intervals
+
+	return nil
+}
+
+// CommitmentRequestValidationError is the validation error returned by
+// CommitmentRequest.Validate if the designated constraints aren't met.
+type CommitmentRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e CommitmentRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e CommitmentRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e CommitmentRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e CommitmentRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e CommitmentRequestValidationError) ErrorName() string { return "CommitmentRequestValidationError" }
+
+// Error satisfies the builtin error interface
+func (e CommitmentRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sCommitmentRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = CommitmentRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = CommitmentRequestValidationError{}
+
+// Validate checks the field values on CommitmentMetricData with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, an error is returned.
+func (m *CommitmentMetricData) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	// no validation rules for Id
+
+	// no validation rules for Format
+
+	// no validation rules for Account
+
+	// no validation rules for Service
+
+	for idx, item := range m.GetAggregation() {
+		_, _ = idx, item
+
+		if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return CommitmentMetricDataValidationError{
+					field:  fmt.Sprintf("Aggregation[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if v, ok := interface{}(m.GetChange()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return CommitmentMetricDataValidationError{
+				field:  "Change",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if v, ok := interface{}(m.GetTrendline()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return CommitmentMetricDataValidationError{
+				field:  "Trendline",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	return nil
+}
+
+// CommitmentMetricDataValidationError is the validation error returned by
+// CommitmentMetricData.Validate if the designated constraints aren't met.
+type CommitmentMetricDataValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e CommitmentMetricDataValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e CommitmentMetricDataValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e CommitmentMetricDataValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e CommitmentMetricDataValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e CommitmentMetricDataValidationError) ErrorName() string {
+	return "CommitmentMetricDataValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e CommitmentMetricDataValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sCommitmentMetricData.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = CommitmentMetricDataValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = CommitmentMetricDataValidationError{}
+
+// Validate checks the field values on CommitmentResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, an error is returned.
+func (m *CommitmentResponse) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	for idx, item := range m.GetMetrics() {
+		_, _ = idx, item
+
+		if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return CommitmentResponseValidationError{
+					field:  fmt.Sprintf("Metrics[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	return nil
+}
+
+// CommitmentResponseValidationError is the validation error returned by
+// CommitmentResponse.Validate if the designated constraints aren't met.
+type CommitmentResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e CommitmentResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e CommitmentResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e CommitmentResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e CommitmentResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e CommitmentResponseValidationError) ErrorName() string {
+	return "CommitmentResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e CommitmentResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sCommitmentResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = CommitmentResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = CommitmentResponseValidationError{}
+
+// Validate checks the field values on ProductsRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, an
+// error is returned.
+func (m *ProductsRequest) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	// no validation rules for Group
+
+	// no validation rules for Project
+
+	// no validation rules for Intervals
+
+	return nil
+}
+
+// ProductsRequestValidationError is the validation error returned by
+// ProductsRequest.Validate if the designated constraints aren't met.
+type ProductsRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ProductsRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ProductsRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ProductsRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ProductsRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ProductsRequestValidationError) ErrorName() string { return "ProductsRequestValidationError" }
+
+// Error satisfies the builtin error interface
+func (e ProductsRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sProductsRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ProductsRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ProductsRequestValidationError{}
+
+// Validate checks the field values on Product with the rules defined in the
+// proto definition for this message. If any rules are violated, an error is returned.
+func (m *Product) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	// no validation rules for Id
+
+	// no validation rules for Name
+
+	return nil
+}
+
+// ProductValidationError is the validation error returned by Product.Validate
+// if the designated constraints aren't met.
+type ProductValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ProductValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ProductValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ProductValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ProductValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ProductValidationError) ErrorName() string { return "ProductValidationError" }
+
+// Error satisfies the builtin error interface
+func (e ProductValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sProduct.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ProductValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ProductValidationError{}
+
+// Validate checks the field values on ProductsResponse with the rules defined
+// in the proto definition for this message. If any rules are violated, an
+// error is returned.
+func (m *ProductsResponse) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	for idx, item := range m.GetProducts() {
+		_, _ = idx, item
+
+		if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return ProductsResponseValidationError{
+					field:  fmt.Sprintf("Products[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	return nil
+}
+
+// ProductsResponseValidationError is the validation error returned by
+// ProductsResponse.Validate if the designated constraints aren't met.
+type ProductsResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ProductsResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ProductsResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ProductsResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ProductsResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ProductsResponseValidationError) ErrorName() string { return "ProductsResponseValidationError" }
+
+// Error satisfies the builtin error interface
+func (e ProductsResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sProductsResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ProductsResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ProductsResponseValidationError{}
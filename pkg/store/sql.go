@@ -0,0 +1,233 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+
+	_ "github.com/lib/pq"
+)
+
+// The dates are ISO 8601 text in both databases so they compare in date order
+const createDailyCost = `CREATE TABLE IF NOT EXISTS daily_cost (
+	date    VARCHAR(10)  NOT NULL,
+	account VARCHAR(64)  NOT NULL,
+	service VARCHAR(256) NOT NULL,
+	tag     VARCHAR(512) NOT NULL,
+	metric  VARCHAR(64)  NOT NULL,
+	amount  DOUBLE PRECISION NOT NULL,
+	PRIMARY KEY (date, account, service, tag, metric)
+)`
+
+const createCheckpoint = `CREATE TABLE IF NOT EXISTS checkpoint (
+	name VARCHAR(64) NOT NULL PRIMARY KEY,
+	date VARCHAR(10) NOT NULL
+)`
+
+// The group column is group_id as GROUP is a reserved word
+const createAlertStatus = `CREATE TABLE IF NOT EXISTS alert_status (
+	group_id VARCHAR(256)  NOT NULL,
+	alert_id VARCHAR(64)   NOT NULL,
+	status   VARCHAR(16)   NOT NULL,
+	reason   VARCHAR(256)  NOT NULL,
+	feedback VARCHAR(4096) NOT NULL,
+	until    VARCHAR(10)   NOT NULL,
+	PRIMARY KEY (group_id, alert_id)
+)`
+
+// Both Postgres (9.5+) and SQLite (3.24+) support the upsert
+const saveDailyCost = `INSERT INTO daily_cost (date, account, service, tag, metric, amount)
+VALUES (?, ?, ?, ?, ?, ?)
+ON CONFLICT (date, account, service, tag, metric) DO UPDATE SET amount = excluded.amount`
+
+const saveCheckpoint = `INSERT INTO checkpoint (name, date) VALUES (?, ?)
+ON CONFLICT (name) DO UPDATE SET date = excluded.date`
+
+const saveAlertStatus = `INSERT INTO alert_status (group_id, alert_id, status, reason, feedback, until)
+VALUES (?, ?, ?, ?, ?, ?)
+ON CONFLICT (group_id, alert_id) DO UPDATE SET status = excluded.status, reason = excluded.reason,
+feedback = excluded.feedback, until = excluded.until`
+
+// sqlStore
+// stores the daily costs in the daily_cost table of a Postgres or SQLite database
+type sqlStore struct {
+	db     *sql.DB
+	driver string
+}
+
+// Open
+// opens the database with the driver (postgres or sqlite3) and creates the tables
+func Open(driver string, dsn string) (Store, error) {
+	switch driver {
+	case PostgresStore, SqliteStore:
+	case "sqlite":
+		driver = SqliteStore
+	default:
+		return nil, errors.New("unsupported database type: " + driver)
+	}
+	if driver == SqliteStore && !SqliteSupported {
+		return nil, errors.New("database type sqlite3 is not supported by a build without cgo (CGO_ENABLED=0), use postgres")
+	}
+
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+	if driver == SqliteStore {
+		// SQLite allows a single writer and every connection to :memory: is a new database
+		db.SetMaxOpenConns(1)
+	}
+	for _, create := range []string{createDailyCost, createCheckpoint, createAlertStatus} {
+		if _, err := db.Exec(create); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+	return &sqlStore{db: db, driver: driver}, nil
+}
+
+// rebind
+// replaces the ? placeholders with the $1, $2, ... placeholders of Postgres
+func (s sqlStore) rebind(query string) string {
+	if s.driver != PostgresStore {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func (s sqlStore) Save(ctx context.Context, costs []DailyCost) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := s.save(ctx, tx, costs); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s sqlStore) Replace(ctx context.Context, start string, end string, metric string, costs []DailyCost) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, s.rebind("DELETE FROM daily_cost WHERE date >= ? AND date < ? AND metric = ?"), start, end, metric)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := s.save(ctx, tx, costs); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s sqlStore) save(ctx context.Context, tx *sql.Tx, costs []DailyCost) error {
+	stmt, err := tx.PrepareContext(ctx, s.rebind(saveDailyCost))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, cost := range costs {
+		_, err := stmt.ExecContext(ctx, cost.Date, cost.Account, cost.Service, cost.Tag, cost.Metric, cost.Amount)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s sqlStore) DailyCosts(ctx context.Context, query Query) ([]DailyCost, error) {
+	where := []string{"date >= ?", "date < ?", "metric = ?"}
+	args := []interface{}{query.Start, query.End, query.Metric}
+	in := func(column string, values []string) {
+		if len(values) == 0 {
+			return
+		}
+		where = append(where, column+" IN (?"+strings.Repeat(", ?", len(values)-1)+")")
+		for _, value := range values {
+			args = append(args, value)
+		}
+	}
+	in("account", query.Accounts)
+	in("service", query.Services)
+
+	rows, err := s.db.QueryContext(ctx, s.rebind(
+		"SELECT date, account, service, tag, metric, amount FROM daily_cost WHERE "+
+			strings.Join(where, " AND ")+" ORDER BY date, account, service, tag"), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	costs := []DailyCost{}
+	for rows.Next() {
+		var cost DailyCost
+		if err := rows.Scan(&cost.Date, &cost.Account, &cost.Service, &cost.Tag, &cost.Metric, &cost.Amount); err != nil {
+			return nil, err
+		}
+		costs = append(costs, cost)
+	}
+	return costs, rows.Err()
+}
+
+func (s sqlStore) Checkpoint(ctx context.Context, name string) (string, error) {
+	var date string
+	err := s.db.QueryRowContext(ctx, s.rebind("SELECT date FROM checkpoint WHERE name = ?"), name).Scan(&date)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return date, err
+}
+
+func (s sqlStore) SaveCheckpoint(ctx context.Context, name string, date string) error {
+	_, err := s.db.ExecContext(ctx, s.rebind(saveCheckpoint), name, date)
+	return err
+}
+
+func (s sqlStore) AlertStatuses(ctx context.Context, group string) ([]AlertStatus, error) {
+	rows, err := s.db.QueryContext(ctx, s.rebind(
+		"SELECT group_id, alert_id, status, reason, feedback, until FROM alert_status WHERE group_id = ? ORDER BY alert_id"), group)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	statuses := []AlertStatus{}
+	for rows.Next() {
+		var status AlertStatus
+		if err := rows.Scan(&status.Group, &status.AlertId, &status.Status, &status.Reason, &status.Feedback, &status.Until); err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, rows.Err()
+}
+
+func (s sqlStore) SaveAlertStatus(ctx context.Context, status AlertStatus) error {
+	if status.Status == "" {
+		_, err := s.db.ExecContext(ctx, s.rebind("DELETE FROM alert_status WHERE group_id = ? AND alert_id = ?"), status.Group, status.AlertId)
+		return err
+	}
+	_, err := s.db.ExecContext(ctx, s.rebind(saveAlertStatus), status.Group, status.AlertId, status.Status, status.Reason, status.Feedback, status.Until)
+	return err
+}
+
+func (s sqlStore) Close() error {
+	return s.db.Close()
+}
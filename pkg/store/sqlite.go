@@ -0,0 +1,12 @@
+//go:build cgo
+// +build cgo
+
+package store
+
+import (
+	// The SQLite driver is a cgo package, builds without cgo only support Postgres
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// SqliteSupported is whether the build includes the SQLite driver
+const SqliteSupported = true
@@ -0,0 +1,7 @@
+//go:build !cgo
+// +build !cgo
+
+package store
+
+// SqliteSupported is whether the build includes the SQLite driver
+const SqliteSupported = false
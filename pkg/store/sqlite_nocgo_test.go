@@ -0,0 +1,12 @@
+//go:build !cgo
+// +build !cgo
+
+package store
+
+import "testing"
+
+func TestSqliteStoreWithoutCgo(t *testing.T) {
+	if _, err := Open(SqliteStore, ":memory:"); err == nil {
+		t.Errorf("Expected an error for sqlite3 in a build without cgo")
+	}
+}
@@ -0,0 +1,99 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/viper"
+)
+
+// Database types of the database.type configuration
+const (
+	PostgresStore = "postgres"
+	SqliteStore   = "sqlite3"
+)
+
+// DailyCost
+// is the cost of one day, AWS Account, service and tag value in one Cost Explorer metric
+// (e.g. NetAmortizedCost). Tag is the Cost Explorer tag key and value, e.g. Product$service-a,
+// or empty when the cost is not broken down by tag.
+type DailyCost struct {
+	Date    string
+	Account string
+	Service string
+	Tag     string
+	Metric  string
+	Amount  float64
+}
+
+// Query
+// selects the daily costs of a metric from Start (inclusive) to End (exclusive), optionally
+// restricted to some accounts and services
+type Query struct {
+	Start    string
+	End      string
+	Metric   string
+	Accounts []string
+	Services []string
+}
+
+// AlertStatus
+// is the status of an alert of a group, e.g. snoozed, with the reason and feedback it was set
+// with. Until is the date the status expires (exclusive), or empty when it does not expire.
+type AlertStatus struct {
+	Group    string
+	AlertId  string
+	Status   string
+	Reason   string
+	Feedback string
+	Until    string
+}
+
+// Store
+// persists daily cost snapshots, a cost saved again for the same date, account, service, tag
+// and metric replaces the previous amount
+type Store interface {
+	Save(ctx context.Context, costs []DailyCost) error
+	// Replace deletes the costs of the metric from start (inclusive) to end (exclusive) and saves
+	// costs in their place in one transaction
+	Replace(ctx context.Context, start string, end string, metric string, costs []DailyCost) error
+	DailyCosts(ctx context.Context, query Query) ([]DailyCost, error)
+	// Checkpoint returns the date saved under name, or an empty string
+	Checkpoint(ctx context.Context, name string) (string, error)
+	SaveCheckpoint(ctx context.Context, name string, date string) error
+	// AlertStatuses returns the statuses of the alerts of the group
+	AlertStatuses(ctx context.Context, group string) ([]AlertStatus, error)
+	// SaveAlertStatus replaces the status of the alert, an empty Status deletes it
+	SaveAlertStatus(ctx context.Context, status AlertStatus) error
+	Close() error
+}
+
+// NewStore
+// opens the store of the database.* configuration, it returns nil when database.enable is false
+func NewStore() (Store, error) {
+	if !viper.GetBool("database.enable") {
+		return nil, nil
+	}
+	return Open(viper.GetString("database.type"), DataSourceName())
+}
+
+// DataSourceName
+// returns database.dsn or builds it from the database.* configuration. The name of a SQLite
+// database is its file.
+func DataSourceName() string {
+	if dsn := viper.GetString("database.dsn"); dsn != "" {
+		return dsn
+	}
+	if viper.GetString("database.type") != PostgresStore {
+		return viper.GetString("database.name")
+	}
+	return fmt.Sprintf("host=%s port=%s dbname=%s user=%s password=%s sslmode=%s %s",
+		viper.GetString("database.address"),
+		viper.GetString("database.port"),
+		viper.GetString("database.name"),
+		viper.GetString("database.user"),
+		viper.GetString("database.password"),
+		viper.GetString("database.ssl"),
+		viper.GetString("database.option"),
+	)
+}
@@ -0,0 +1,146 @@
+//go:build cgo
+// +build cgo
+
+package store
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSqliteStore(t *testing.T) {
+	s, err := Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer s.Close()
+	ctx := context.Background()
+
+	costs := []DailyCost{
+		{Date: "2021-08-31", Account: "111111111111", Service: "AWS Lambda", Metric: "NetAmortizedCost", Amount: 1},
+		{Date: "2021-09-01", Account: "111111111111", Service: "AWS Lambda", Metric: "NetAmortizedCost", Amount: 2},
+		{Date: "2021-09-01", Account: "222222222222", Service: "AWS Lambda", Metric: "NetAmortizedCost", Amount: 3},
+		{Date: "2021-09-01", Account: "111111111111", Service: "AWS Lambda", Metric: "UnblendedCost", Amount: 4},
+	}
+	if err := s.Save(ctx, costs); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// Saving a cost again replaces its amount
+	costs[1].Amount = 5
+	if err := s.Save(ctx, costs[1:2]); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var tests = []struct {
+		name     string
+		query    Query
+		expected []float64
+	}{
+		{name: "date range", query: Query{Start: "2021-09-01", End: "2021-09-02", Metric: "NetAmortizedCost"}, expected: []float64{5, 3}},
+		{name: "accounts", query: Query{Start: "2021-08-01", End: "2021-09-02", Metric: "NetAmortizedCost", Accounts: []string{"111111111111"}}, expected: []float64{1, 5}},
+		{name: "services", query: Query{Start: "2021-08-01", End: "2021-09-02", Metric: "UnblendedCost", Services: []string{"AWS Lambda", "AWS Glue"}}, expected: []float64{4}},
+		{name: "no costs", query: Query{Start: "2021-10-01", End: "2021-10-02", Metric: "NetAmortizedCost"}, expected: []float64{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			costs, err := s.DailyCosts(ctx, test.query)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(costs) != len(test.expected) {
+				t.Fatalf("Output %v not equal to expected %v", costs, test.expected)
+			}
+			for i, cost := range costs {
+				if cost.Amount != test.expected[i] {
+					t.Errorf("Output %v not equal to expected %v", cost.Amount, test.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSqliteStoreReplace(t *testing.T) {
+	s, err := Open(SqliteStore, ":memory:")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer s.Close()
+	ctx := context.Background()
+
+	err = s.Save(ctx, []DailyCost{
+		{Date: "2021-09-01", Account: "111111111111", Service: "AWS Lambda", Metric: "NetAmortizedCost", Amount: 1},
+		{Date: "2021-09-02", Account: "111111111111", Service: "AWS Lambda", Metric: "NetAmortizedCost", Amount: 2},
+		{Date: "2021-09-02", Account: "111111111111", Service: "AWS Glue", Metric: "NetAmortizedCost", Amount: 3},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// The restated day no longer has a Glue cost
+	err = s.Replace(ctx, "2021-09-02", "2021-09-03", "NetAmortizedCost", []DailyCost{
+		{Date: "2021-09-02", Account: "111111111111", Service: "AWS Lambda", Metric: "NetAmortizedCost", Amount: 4},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	costs, err := s.DailyCosts(ctx, Query{Start: "2021-09-01", End: "2021-09-03", Metric: "NetAmortizedCost"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(costs) != 2 || costs[0].Amount != 1 || costs[1].Amount != 4 {
+		t.Errorf("Output %v not equal to the replaced costs", costs)
+	}
+
+	if date, err := s.Checkpoint(ctx, "daily_cost"); err != nil || date != "" {
+		t.Errorf("Output %q %v not equal to an empty checkpoint", date, err)
+	}
+	s.SaveCheckpoint(ctx, "daily_cost", "2021-09-02")
+	s.SaveCheckpoint(ctx, "daily_cost", "2021-09-03")
+	if date, err := s.Checkpoint(ctx, "daily_cost"); err != nil || date != "2021-09-03" {
+		t.Errorf("Output %q %v not equal to expected checkpoint 2021-09-03", date, err)
+	}
+}
+
+func TestSqliteStoreAlertStatus(t *testing.T) {
+	s, err := Open(SqliteStore, ":memory:")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer s.Close()
+	ctx := context.Background()
+
+	for _, status := range []AlertStatus{
+		{Group: "platform", AlertId: "a", Status: "snoozed", Until: "2021-10-22"},
+		{Group: "platform", AlertId: "b", Status: "dismissed", Reason: "expected", Feedback: "a planned migration"},
+		{Group: "data", AlertId: "a", Status: "accepted"},
+		// Saving a status again replaces it
+		{Group: "platform", AlertId: "a", Status: "accepted", Reason: "resolved"},
+	} {
+		if err := s.SaveAlertStatus(ctx, status); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	statuses, err := s.AlertStatuses(ctx, "platform")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := AlertStatus{Group: "platform", AlertId: "a", Status: "accepted", Reason: "resolved"}
+	if len(statuses) != 2 || statuses[0] != expected || statuses[1].Feedback != "a planned migration" {
+		t.Errorf("Output %v not equal to expected statuses", statuses)
+	}
+
+	// An empty status deletes it
+	if err := s.SaveAlertStatus(ctx, AlertStatus{Group: "platform", AlertId: "a"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if statuses, err := s.AlertStatuses(ctx, "platform"); err != nil || len(statuses) != 1 || statuses[0].AlertId != "b" {
+		t.Errorf("Output %v %v not equal to the remaining status", statuses, err)
+	}
+}
+
+func TestPostgresRebind(t *testing.T) {
+	s := sqlStore{driver: PostgresStore}
+	actual := s.rebind("date >= ? AND account IN (?, ?)")
+	if actual != "date >= $1 AND account IN ($2, $3)" {
+		t.Errorf("Output %q not equal to expected", actual)
+	}
+}
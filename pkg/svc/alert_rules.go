@@ -0,0 +1,236 @@
+package svc
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/seizadi/cost-insights-backend/pkg/types"
+	"github.com/seizadi/cost-insights-backend/pkg/utils"
+)
+
+// Types of the alert rules
+const (
+	AnomalyRule     = "anomaly"
+	GrowthRule      = "growth"
+	SpendRule       = "spend"
+	UnlabeledRule   = "unlabeled"
+	BudgetRule      = "budget"
+	SavingsRule     = "savings"
+	RightsizingRule = "rightsizing"
+)
+
+// Severities of the alerts raised by a rule
+const (
+	InfoSeverity     = "info"
+	WarningSeverity  = "warning"
+	CriticalSeverity = "critical"
+)
+
+var alertSeverities = map[string]bool{InfoSeverity: true, WarningSeverity: true, CriticalSeverity: true}
+
+// AlertRule
+// is a cost alert evaluated by GetAlerts for the requested group, configured in
+// cost.alerts.rules, e.g.
+//
+//	cost:
+//	  alerts:
+//	    rules:
+//	      - name: s3-quarterly-growth
+//	        type: growth
+//	        service: S3
+//	        window: P3M
+//	        threshold: 0.2
+//	      - name: lambda-weekly-spend
+//	        type: spend
+//	        service: Lambda
+//	        window: P1W
+//	        threshold: 500
+//	        severity: critical
+//	      - name: team-tag-coverage
+//	        type: unlabeled
+//	        tag_key: team
+//	        window: P1M
+//	        threshold: 0.1
+//	      - name: platform-budget
+//	        type: budget
+//	        group: platform
+//	        budget: 20000
+//	      - name: commitment-savings
+//	        type: savings
+//	        window: P30D
+//	        threshold: 100
+//	      - name: ec2-rightsizing
+//	        type: rightsizing
+//	        threshold: 50
+//
+// An anomaly rule alerts on the cost anomalies in the last Window with a robust z-score of
+// Threshold, a growth rule when the cost of the last Window grew by the Threshold ratio from
+// the Window before, a spend rule when the cost of the last Window is over the Threshold
+// amount and an unlabeled rule when more than the Threshold share of the cost of the last
+// Window has no value of the TagKey cost allocation tag. A budget rule alerts when the cost of
+// the fiscal month so far reaches the Threshold share of the Budget, when the run rate projects
+// it over the Budget and when the Budget is spent, without a Budget the month is budgeted by
+// the budgets of the group, or of the Project, in the cost.alerts.budget.path file. A savings
+// rule alerts on the Savings Plans and Reserved Instance purchases Cost Explorer recommends from
+// the usage of the last Window, P7D, P30D or P60D, that save at least the Threshold amount a
+// month. A rightsizing rule alerts on the EC2 instances of every account Cost Explorer
+// recommends to downsize or terminate from their usage of the last 14 days when they save the
+// account at least the Threshold amount a month. The Window is an ISO 8601 duration, quarters
+// and years are fiscal periods. The scope of a rule is the cost of the group narrowed to the
+// Project, the Service, an AWS_SERVICE name or a Cost Explorer service, and the Tag, a key=value
+// pair, that are set. A rule with a Group is only evaluated for that group.
+type AlertRule struct {
+	Name      string  `mapstructure:"name"`
+	Type      string  `mapstructure:"type"`
+	Group     string  `mapstructure:"group"`
+	Project   string  `mapstructure:"project"`
+	Service   string  `mapstructure:"service"`
+	Tag       string  `mapstructure:"tag"`
+	TagKey    string  `mapstructure:"tag_key"`
+	Window    string  `mapstructure:"window"`
+	Threshold float64 `mapstructure:"threshold"`
+	Budget    float64 `mapstructure:"budget"`
+	Severity  string  `mapstructure:"severity"`
+}
+
+// NewAlertRules
+// returns the rules of the cost.alerts.rules configuration. Without rules the cost anomalies of
+// every group are alerted on with the cost.alerts.anomaly configuration, and the monthly cost
+// without the cost.alerts.unlabeled.tag_key tag when it is set.
+func NewAlertRules() ([]AlertRule, error) {
+	var rules []AlertRule
+	if err := viper.UnmarshalKey("cost.alerts.rules", &rules); err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		rules = []AlertRule{{Name: "cost-anomaly", Type: AnomalyRule}}
+		if viper.GetString("cost.alerts.unlabeled.tag_key") != "" {
+			rules = append(rules, AlertRule{Name: "tag-coverage", Type: UnlabeledRule})
+		}
+	}
+	for i := range rules {
+		if err := rules[i].normalize(); err != nil {
+			return nil, err
+		}
+	}
+	return rules, nil
+}
+
+// normalize
+// checks the rule and fills in the defaults, the severity is warning, an anomaly rule without
+// a window or threshold uses the cost.alerts.anomaly configuration, an unlabeled rule checks
+// the last month of the cost.alerts.unlabeled.tag_key tag by default and a budget rule warns at
+// the cost.alerts.budget.warning share of the budget by default. A savings rule looks back 30
+// days by default and a rightsizing rule documents the 14 days of the recommendations.
+func (r *AlertRule) normalize() error {
+	if r.Name == "" {
+		r.Name = r.Type
+	}
+	switch r.Type {
+	case AnomalyRule:
+		if r.Window == "" {
+			r.Window = fmt.Sprintf("P%dD", viper.GetInt("cost.alerts.anomaly.recent_days"))
+		}
+		if r.Threshold == 0 {
+			r.Threshold = viper.GetFloat64("cost.alerts.anomaly.threshold")
+		}
+	case GrowthRule, SpendRule:
+		if r.Window == "" {
+			return errors.New("alert rule without a window: " + r.Name)
+		}
+	case UnlabeledRule:
+		if r.Window == "" {
+			r.Window = "P1M"
+		}
+		if r.TagKey == "" {
+			r.TagKey = viper.GetString("cost.alerts.unlabeled.tag_key")
+		}
+		if r.TagKey == "" {
+			return errors.New("unlabeled alert rule without a tag_key: " + r.Name)
+		}
+		if r.Threshold < 0 || r.Threshold >= 1 {
+			return fmt.Errorf("threshold of unlabeled alert rule %s is not a share of the cost: %v", r.Name, r.Threshold)
+		}
+	case BudgetRule:
+		// The budget is of the fiscal month, the window only documents it
+		if r.Window == "" {
+			r.Window = "P1M"
+		}
+		if r.Threshold == 0 {
+			r.Threshold = viper.GetFloat64("cost.alerts.budget.warning")
+		}
+		if r.Budget < 0 {
+			return fmt.Errorf("negative budget of alert rule %s: %v", r.Name, r.Budget)
+		}
+		if r.Budget == 0 && viper.GetString("cost.alerts.budget.path") == "" {
+			return errors.New("budget alert rule without a budget or cost.alerts.budget.path: " + r.Name)
+		}
+	case SavingsRule:
+		if r.Window == "" {
+			r.Window = "P30D"
+		}
+		if _, ok := savingsLookbacks[r.Window]; !ok {
+			return fmt.Errorf("window of savings alert rule %s is not P7D, P30D or P60D: %s", r.Name, r.Window)
+		}
+		if r.Tag != "" {
+			return errors.New("savings alert rule with a tag, recommendations are not by tag: " + r.Name)
+		}
+		if r.Threshold < 0 {
+			return fmt.Errorf("negative threshold of alert rule %s: %v", r.Name, r.Threshold)
+		}
+	case RightsizingRule:
+		// Cost Explorer recommends from the last 14 days, the window only documents it
+		if r.Window == "" {
+			r.Window = "P14D"
+		}
+		if r.Service != "" && r.Service != "EC2" && r.Service != AWS_SERVICE["EC2"] {
+			return errors.New("rightsizing alert rule with a service other than EC2: " + r.Name)
+		}
+		if r.Tag != "" {
+			return errors.New("rightsizing alert rule with a tag, recommendations are not by tag: " + r.Name)
+		}
+		if r.Threshold < 0 {
+			return fmt.Errorf("negative threshold of alert rule %s: %v", r.Name, r.Threshold)
+		}
+	default:
+		return fmt.Errorf("unknown type of alert rule %s: %s", r.Name, r.Type)
+	}
+
+	if r.Severity == "" {
+		r.Severity = WarningSeverity
+	}
+	r.Severity = strings.ToLower(r.Severity)
+	if !alertSeverities[r.Severity] {
+		return fmt.Errorf("unknown severity of alert rule %s: %s", r.Name, r.Severity)
+	}
+	if _, err := utils.ParseDuration(types.Duration(r.Window)); err != nil {
+		return fmt.Errorf("invalid window of alert rule %s: %s", r.Name, r.Window)
+	}
+	if r.Tag != "" && !strings.Contains(r.Tag, "=") {
+		return fmt.Errorf("tag of alert rule %s is not a key=value pair: %s", r.Name, r.Tag)
+	}
+	return nil
+}
+
+// appliesTo returns true when the rule is evaluated for the group
+func (r AlertRule) appliesTo(group string) bool {
+	return r.Group == "" || r.Group == group
+}
+
+// intervals
+// returns repeats windows of the rule ending with the last complete billing date
+func (r AlertRule) intervals(repeats int, date time.Time) string {
+	return fmt.Sprintf("R%d/%s/%s", repeats, r.Window, date.AddDate(0, 0, 1).Format(types.DEFAULT_DATE_FORMAT))
+}
+
+// scope returns the project of the rule, or the group when the rule is not for a project
+func (r AlertRule) scope(group string) string {
+	if r.Project != "" {
+		return r.Project
+	}
+	return group
+}
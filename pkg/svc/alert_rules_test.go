@@ -0,0 +1,75 @@
+package svc
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestNewAlertRules(t *testing.T) {
+	setTestCostConfig()
+	defer viper.Set("cost.alerts.rules", nil)
+
+	viper.Set("cost.alerts.rules", nil)
+	rules, err := NewAlertRules()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(rules) != 1 || rules[0] != testAlertRules[0] {
+		t.Errorf("Output %+v not equal to expected default %+v", rules, testAlertRules[0])
+	}
+
+	viper.Set("cost.alerts.unlabeled.tag_key", "team")
+	defer viper.Set("cost.alerts.unlabeled.tag_key", "")
+	rules, err = NewAlertRules()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	coverage := AlertRule{Name: "tag-coverage", Type: UnlabeledRule, TagKey: "team", Window: "P1M", Severity: WarningSeverity}
+	if len(rules) != 2 || rules[1] != coverage {
+		t.Errorf("Output %+v not equal to expected tag coverage %+v", rules, coverage)
+	}
+
+	viper.Set("cost.alerts.rules", []map[string]interface{}{
+		{"type": "growth", "service": "S3", "window": "P3M", "threshold": 0.2, "severity": "Critical"},
+	})
+	rules, err = NewAlertRules()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := AlertRule{Name: GrowthRule, Type: GrowthRule, Service: "S3", Window: "P3M", Threshold: 0.2, Severity: CriticalSeverity}
+	if len(rules) != 1 || rules[0] != expected {
+		t.Errorf("Output %+v not equal to expected %+v", rules, expected)
+	}
+}
+
+func TestNewAlertRulesErrors(t *testing.T) {
+	setTestCostConfig()
+	defer viper.Set("cost.alerts.rules", nil)
+
+	var tests = []struct {
+		name string
+		rule map[string]interface{}
+	}{
+		{name: "unknown type", rule: map[string]interface{}{"type": "budget", "window": "P1M"}},
+		{name: "growth without window", rule: map[string]interface{}{"type": "growth", "threshold": 0.2}},
+		{name: "window with a time part", rule: map[string]interface{}{"type": "spend", "window": "PT1H"}},
+		{name: "unknown severity", rule: map[string]interface{}{"type": "anomaly", "severity": "page"}},
+		{name: "tag without value", rule: map[string]interface{}{"type": "anomaly", "tag": "team"}},
+		{name: "unlabeled without tag key", rule: map[string]interface{}{"type": "unlabeled"}},
+		{name: "budget without budget", rule: map[string]interface{}{"type": "budget"}},
+		{name: "negative budget", rule: map[string]interface{}{"type": "budget", "budget": -1}},
+		{name: "unlabeled share over one", rule: map[string]interface{}{"type": "unlabeled", "tag_key": "team", "threshold": 1}},
+		{name: "savings lookback", rule: map[string]interface{}{"type": "savings", "window": "P1M"}},
+		{name: "savings with a tag", rule: map[string]interface{}{"type": "savings", "tag": "team=platform"}},
+		{name: "rightsizing of another service", rule: map[string]interface{}{"type": "rightsizing", "service": "RDS"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			viper.Set("cost.alerts.rules", []map[string]interface{}{test.rule})
+			if _, err := NewAlertRules(); err == nil {
+				t.Errorf("Expected error for rule %v", test.rule)
+			}
+		})
+	}
+}
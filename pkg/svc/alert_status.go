@@ -0,0 +1,190 @@
+package svc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/spf13/viper"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/seizadi/cost-insights-backend/pkg/pb"
+	"github.com/seizadi/cost-insights-backend/pkg/store"
+	"github.com/seizadi/cost-insights-backend/pkg/types"
+	"github.com/seizadi/cost-insights-backend/pkg/utils"
+)
+
+// Statuses of an alert set with SetAlertStatus, ActiveStatus clears the status of an alert
+const (
+	SnoozedStatus   = "snoozed"
+	AcceptedStatus  = "accepted"
+	DismissedStatus = "dismissed"
+	ActiveStatus    = "active"
+)
+
+// Longest alert id, reason and feedback of an alert status, the sizes of their alert_status columns
+const (
+	maxAlertIdLength  = 64
+	maxReasonLength   = 256
+	maxFeedbackLength = 4096
+)
+
+// AlertStatusStore
+// persists the statuses of alerts, the daily cost store implements it
+type AlertStatusStore interface {
+	AlertStatuses(ctx context.Context, group string) ([]store.AlertStatus, error)
+	SaveAlertStatus(ctx context.Context, status store.AlertStatus) error
+}
+
+// NewAlertStatusStore
+// returns the database store s, or an in-memory store that loses the alert statuses on restart
+// when there is no store because database.enable is false
+func NewAlertStatusStore(s store.Store) AlertStatusStore {
+	if s == nil {
+		return newMemoryAlertStatusStore()
+	}
+	return s
+}
+
+// memoryAlertStatusStore
+// keeps the alert statuses of every group by alert id
+type memoryAlertStatusStore struct {
+	mu       sync.Mutex
+	statuses map[string]map[string]store.AlertStatus
+}
+
+func newMemoryAlertStatusStore() *memoryAlertStatusStore {
+	return &memoryAlertStatusStore{statuses: map[string]map[string]store.AlertStatus{}}
+}
+
+func (s *memoryAlertStatusStore) AlertStatuses(ctx context.Context, group string) ([]store.AlertStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	statuses := []store.AlertStatus{}
+	for _, alertStatus := range s.statuses[group] {
+		statuses = append(statuses, alertStatus)
+	}
+	return statuses, nil
+}
+
+func (s *memoryAlertStatusStore) SaveAlertStatus(ctx context.Context, alertStatus store.AlertStatus) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if alertStatus.Status == "" {
+		delete(s.statuses[alertStatus.Group], alertStatus.AlertId)
+		return nil
+	}
+	if s.statuses[alertStatus.Group] == nil {
+		s.statuses[alertStatus.Group] = map[string]store.AlertStatus{}
+	}
+	s.statuses[alertStatus.Group][alertStatus.AlertId] = alertStatus
+	return nil
+}
+
+// alertIdOf
+// returns the stable id of an alert of the group. Alerts of rolling windows keep their id while
+// the rule keeps raising them, so their dismissals expire after cost.alerts.dismiss, an anomaly
+// is identified by its first day and a budget alert by its month so that a new anomaly or month
+// is a new alert.
+func alertIdOf(group string, alert *pb.Entity) string {
+	parts := []string{group, alert.Type, alert.Id, alert.Project}
+	switch alert.Type {
+	case CostAnomalyAlert, BudgetBurnRateAlert, BudgetBreachAlert:
+		parts = append(parts, alert.StartDate)
+	}
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// flagAlerts
+// sets the id of the alerts of the group and the status of the alerts whose status has not
+// expired on today. With cost.alerts.hide_inactive the snoozed and dismissed alerts are removed.
+func flagAlerts(ctx context.Context, statuses AlertStatusStore, group string, today time.Time, alerts []*pb.Entity) ([]*pb.Entity, error) {
+	saved, err := statuses.AlertStatuses(ctx, group)
+	if err != nil {
+		return nil, err
+	}
+	date := today.Format(types.DEFAULT_DATE_FORMAT)
+	byId := map[string]store.AlertStatus{}
+	for _, alertStatus := range saved {
+		if alertStatus.Until == "" || date < alertStatus.Until {
+			byId[alertStatus.AlertId] = alertStatus
+		}
+	}
+
+	hide := viper.GetBool("cost.alerts.hide_inactive")
+	flagged := []*pb.Entity{}
+	for _, alert := range alerts {
+		alert.AlertId = alertIdOf(group, alert)
+		if alertStatus, ok := byId[alert.AlertId]; ok {
+			alert.Status = alertStatus.Status
+			alert.StatusUntil = alertStatus.Until
+		}
+		if hide && (alert.Status == SnoozedStatus || alert.Status == DismissedStatus) {
+			continue
+		}
+		flagged = append(flagged, alert)
+	}
+	return flagged, nil
+}
+
+// alertStatusOf
+// returns the status of the request set today. Snoozes last cost.alerts.snooze and dismissals
+// cost.alerts.dismiss without a duration, accepted alerts do not expire. An alert id, reason or feedback longer than its
+// column is an invalid argument.
+func alertStatusOf(req *pb.AlertStatusRequest, today time.Time) (store.AlertStatus, error) {
+	alertStatus := store.AlertStatus{
+		Group:    req.Group,
+		AlertId:  req.AlertId,
+		Status:   strings.ToLower(req.Status),
+		Reason:   req.Reason,
+		Feedback: req.Feedback,
+	}
+	if req.Group == "" || req.AlertId == "" {
+		return alertStatus, status.Error(codes.InvalidArgument, "alert status without a group or alertId")
+	}
+	for _, field := range []struct {
+		name  string
+		value string
+		max   int
+	}{
+		{name: "alertId", value: req.AlertId, max: maxAlertIdLength},
+		{name: "reason", value: req.Reason, max: maxReasonLength},
+		{name: "feedback", value: req.Feedback, max: maxFeedbackLength},
+	} {
+		if utf8.RuneCountInString(field.value) > field.max {
+			return alertStatus, status.Errorf(codes.InvalidArgument, "alert status %s longer than %d characters", field.name, field.max)
+		}
+	}
+	duration := req.Duration
+	switch alertStatus.Status {
+	case SnoozedStatus:
+		if duration == "" {
+			duration = viper.GetString("cost.alerts.snooze")
+		}
+	case DismissedStatus:
+		if duration == "" {
+			duration = viper.GetString("cost.alerts.dismiss")
+		}
+	case AcceptedStatus:
+	case ActiveStatus:
+		// Saving an empty status deletes it
+		alertStatus.Status = ""
+		return alertStatus, nil
+	default:
+		return alertStatus, status.Errorf(codes.InvalidArgument, "unknown alert status %q", req.Status)
+	}
+	if duration != "" {
+		d, err := utils.ParseDuration(types.Duration(duration))
+		if err != nil {
+			return alertStatus, status.Errorf(codes.InvalidArgument, "invalid alert status duration %q", duration)
+		}
+		alertStatus.Until = utils.AddDuration(today, d, 1).Format(types.DEFAULT_DATE_FORMAT)
+	}
+	return alertStatus, nil
+}
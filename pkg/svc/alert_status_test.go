@@ -0,0 +1,121 @@
+package svc
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/seizadi/cost-insights-backend/pkg/pb"
+)
+
+func TestSetAlertStatus(t *testing.T) {
+	setTestCostConfig()
+	server := newTestAwsServer(t)
+	ctx := context.Background()
+
+	alerts, err := server.GetAlerts(ctx, &pb.AlertRequest{Group: "platform"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	snoozed, dismissed := alerts.Alerts[0], alerts.Alerts[1]
+	if snoozed.AlertId == "" || snoozed.AlertId == dismissed.AlertId {
+		t.Fatalf("Output alert ids %q and %q not unique", snoozed.AlertId, dismissed.AlertId)
+	}
+
+	resp, err := server.SetAlertStatus(ctx, &pb.AlertStatusRequest{Group: "platform", AlertId: snoozed.AlertId, Status: "Snoozed"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if resp.Status.Status != SnoozedStatus || resp.Status.Until != "2021-10-22" {
+		t.Errorf("Output %v not equal to expected snooze until 2021-10-22", resp.Status)
+	}
+	_, err = server.SetAlertStatus(ctx, &pb.AlertStatusRequest{
+		Group: "platform", AlertId: dismissed.AlertId, Status: DismissedStatus, Reason: "expected", Feedback: "planned migration",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The statuses flag the alerts recomputed by later calls
+	flagged, err := server.GetAlerts(ctx, &pb.AlertRequest{Group: "platform"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	statuses := map[string]string{}
+	for _, alert := range flagged.Alerts {
+		statuses[alert.AlertId] = alert.Status
+	}
+	if statuses[snoozed.AlertId] != SnoozedStatus || statuses[dismissed.AlertId] != DismissedStatus || len(statuses) != len(alerts.Alerts) {
+		t.Errorf("Output statuses %v not equal to the snoozed and dismissed alerts", statuses)
+	}
+
+	viper.Set("cost.alerts.hide_inactive", true)
+	defer viper.Set("cost.alerts.hide_inactive", false)
+	hidden, err := server.GetAlerts(ctx, &pb.AlertRequest{Group: "platform"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(hidden.Alerts) != len(alerts.Alerts)-2 {
+		t.Errorf("Output %d alerts not equal to expected %d without the inactive alerts", len(hidden.Alerts), len(alerts.Alerts)-2)
+	}
+
+	// The snooze expires after a week, the dismissal lasts longer
+	server.now = func() time.Time { return testNow().AddDate(0, 0, 7) }
+	server.rules = nil
+	expired, err := flagAlerts(ctx, server.statuses, "platform", server.now(), []*pb.Entity{snoozed, dismissed})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(expired) != 1 || expired[0].AlertId != snoozed.AlertId {
+		t.Errorf("Output %v not equal to the expired snooze", expired)
+	}
+
+	// The dismissal expires after cost.alerts.dismiss
+	later := testNow().AddDate(0, 0, 90)
+	dismissed.Status = ""
+	if expired, err := flagAlerts(ctx, server.statuses, "platform", later, []*pb.Entity{dismissed}); err != nil || len(expired) != 1 || expired[0].Status != "" {
+		t.Errorf("Output %v %v not equal to the expired dismissal", expired, err)
+	}
+
+	// An active status clears the dismissal
+	if _, err := server.SetAlertStatus(ctx, &pb.AlertStatusRequest{Group: "platform", AlertId: dismissed.AlertId, Status: ActiveStatus}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	dismissed.Status = ""
+	if active, err := flagAlerts(ctx, server.statuses, "platform", server.now(), []*pb.Entity{dismissed}); err != nil || len(active) != 1 || active[0].Status != "" {
+		t.Errorf("Output %v %v not equal to the active alert", active, err)
+	}
+}
+
+func TestSetAlertStatusErrors(t *testing.T) {
+	setTestCostConfig()
+	server := newTestAwsServer(t)
+
+	var tests = []struct {
+		name string
+		req  *pb.AlertStatusRequest
+		code codes.Code
+	}{
+		{name: "unknown group", req: &pb.AlertStatusRequest{Group: "ops", AlertId: "a", Status: SnoozedStatus}, code: codes.NotFound},
+		{name: "without alert id", req: &pb.AlertStatusRequest{Group: "platform", Status: SnoozedStatus}, code: codes.InvalidArgument},
+		{name: "unknown status", req: &pb.AlertStatusRequest{Group: "platform", AlertId: "a", Status: "resolved"}, code: codes.InvalidArgument},
+		{name: "invalid duration", req: &pb.AlertStatusRequest{Group: "platform", AlertId: "a", Status: AcceptedStatus, Duration: "7 days"}, code: codes.InvalidArgument},
+		{name: "long alert id", req: &pb.AlertStatusRequest{Group: "platform", AlertId: strings.Repeat("a", 65), Status: ActiveStatus}, code: codes.InvalidArgument},
+		{name: "long reason", req: &pb.AlertStatusRequest{Group: "platform", AlertId: "a", Status: DismissedStatus, Reason: strings.Repeat("r", 257)}, code: codes.InvalidArgument},
+		{name: "long feedback", req: &pb.AlertStatusRequest{Group: "platform", AlertId: "a", Status: DismissedStatus, Feedback: strings.Repeat("f", 4097)}, code: codes.InvalidArgument},
+		{name: "longest feedback", req: &pb.AlertStatusRequest{Group: "platform", AlertId: "a", Status: DismissedStatus, Feedback: strings.Repeat("é", 4096)}, code: codes.OK},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := server.SetAlertStatus(context.Background(), test.req)
+			if status.Code(err) != test.code {
+				t.Errorf("Output error %v not equal to expected code %v", err, test.code)
+			}
+		})
+	}
+}
@@ -2,143 +2,532 @@ package svc
 
 import (
 	"context"
-	"github.com/spf13/viper"
+	"math"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
 	ceTypes "github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/spf13/viper"
 
+	"github.com/seizadi/cost-insights-backend/metrics"
+	"github.com/seizadi/cost-insights-backend/pkg/accounts"
 	"github.com/seizadi/cost-insights-backend/pkg/pb"
 	"github.com/seizadi/cost-insights-backend/pkg/types"
 	"github.com/seizadi/cost-insights-backend/pkg/utils"
 )
 
-func (m costInsightsAwsServer) ProjectGrowthAlert() (*pb.Entity, error) {
-	entity := pb.Entity{}
-	entity.Type = "ProjectGrowthAlert"
-	// TODO - Alert on each project for now do it for the aggregate
-	entity.Project = "All Projects (AWS Accounts)"
+// Types of the alerts raised by the alert rules, growth and tag coverage alerts are the
+// ProjectGrowthAlert and UnlabeledDataflowAlert of the Backstage Cost Insights plugin
+const (
+	CostAnomalyAlert      = "CostAnomalyAlert"
+	GrowthAlert           = "ProjectGrowthAlert"
+	CostThresholdAlert    = "CostThresholdAlert"
+	TagCoverageAlert      = "UnlabeledDataflowAlert"
+	BudgetBurnRateAlert   = "BudgetBurnRateAlert"
+	BudgetBreachAlert     = "BudgetBreachAlert"
+	SavingsPlansAlert     = "SavingsPlansAlert"
+	ReservedInstanceAlert = "ReservedInstanceAlert"
+	RightsizingAlert      = "RightsizingAlert"
+)
 
-	// TODO - Configure the Alert Interval - default to 90 days
-	// FIXME - Change from hard coded value to Time.Now()
-	intervals := "R2/P90D/2021-09-01"
-	interval, err := utils.ParseIntervals(intervals)
+// anomalyKey is the AWS Account and the AWS Service of a daily cost series
+type anomalyKey struct {
+	account string
+	service string
+}
+
+// RuleAlerts
+// evaluates the alert rules for the group on the costs up to the last complete billing date,
+// the alerts carry the severity of their rule unless the rule sets one by alert
+func (m costInsightsAwsServer) RuleAlerts(ctx context.Context, group string) ([]*pb.Entity, error) {
+	billing, err := m.GetLastCompleteBillingDate(ctx, &empty.Empty{})
+	if err != nil {
+		return nil, err
+	}
+	date, err := time.Parse(types.DEFAULT_DATE_FORMAT, billing.Date)
 	if err != nil {
-		return &pb.Entity{}, err
+		return nil, err
 	}
 
-	startDate, err := utils.InclusiveStartDateOf(interval.Duration, interval.EndDate)
+	alerts := []*pb.Entity{}
+	for _, rule := range m.rules {
+		if !rule.appliesTo(group) {
+			continue
+		}
+		filter, err := m.ruleFilter(group, rule)
+		if err != nil {
+			return nil, err
+		}
+		var ruleAlerts []*pb.Entity
+		switch rule.Type {
+		case AnomalyRule:
+			ruleAlerts, err = m.anomalyAlerts(ctx, filter, date, rule)
+		case GrowthRule:
+			ruleAlerts, err = m.growthAlerts(ctx, filter, date, group, rule)
+		case SpendRule:
+			ruleAlerts, err = m.spendAlerts(ctx, filter, date, group, rule)
+		case UnlabeledRule:
+			ruleAlerts, err = m.unlabeledAlerts(ctx, filter, date, group, rule)
+		case BudgetRule:
+			ruleAlerts, err = m.budgetAlerts(ctx, filter, date, group, rule)
+		case SavingsRule:
+			ruleAlerts, err = m.savingsAlerts(ctx, date, group, rule)
+		case RightsizingRule:
+			ruleAlerts, err = m.rightsizingAlerts(ctx, date, group, rule)
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, alert := range ruleAlerts {
+			if alert.Severity == "" {
+				alert.Severity = rule.Severity
+			}
+		}
+		alerts = append(alerts, ruleAlerts...)
+	}
+	return alerts, nil
+}
+
+// ruleFilter
+// returns the CostExplorer filter on the cost of the group in the scope of the rule, or nil
+// for the cost of all accounts
+func (m costInsightsAwsServer) ruleFilter(group string, rule AlertRule) (*ceTypes.Expression, error) {
+	groupAccounts, err := m.groups.LinkedAccountsOfGroup(group)
 	if err != nil {
-		return &pb.Entity{}, err
+		return nil, err
+	}
+	expressions := []ceTypes.Expression{}
+	if filter := linkedAccountFilter(groupAccounts); filter != nil {
+		expressions = append(expressions, *filter)
+	}
+	if rule.Project != "" {
+		linkedAccounts, err := m.groups.LinkedAccountsOf(rule.Project)
+		if err != nil {
+			return nil, err
+		}
+		expressions = append(expressions, *linkedAccountFilter(linkedAccounts))
+	}
+	if rule.Service != "" {
+		service := rule.Service
+		if name, ok := AWS_SERVICE[service]; ok {
+			service = name
+		}
+		expressions = append(expressions, ceTypes.Expression{
+			Dimensions: &ceTypes.DimensionValues{Key: ceTypes.DimensionService, Values: []string{service}},
+		})
 	}
+	if rule.Tag != "" {
+		tag := strings.SplitN(rule.Tag, "=", 2)
+		expressions = append(expressions, ceTypes.Expression{
+			Tags: &ceTypes.TagValues{Key: &tag[0], Values: []string{tag[1]}},
+		})
+	}
+
+	switch len(expressions) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &expressions[0], nil
+	}
+	return &ceTypes.Expression{And: expressions}, nil
+}
+
+// anomalyAlerts
+// returns an alert for every cost anomaly of a service in an AWS Account in the scope of the
+// rule. The daily costs of the cost.alerts.anomaly.history_days days up to the last complete
+// billing date are the baseline of the anomaly detection in the last window of the rule. The
+// alerts are sorted by their excess cost, the largest first.
+func (m costInsightsAwsServer) anomalyAlerts(ctx context.Context, filter *ceTypes.Expression, date time.Time, rule AlertRule) ([]*pb.Entity, error) {
+	window, err := utils.ParseIntervals(rule.intervals(1, date))
+	if err != nil {
+		return nil, err
+	}
+	days := viper.GetInt("cost.alerts.anomaly.history_days")
+	start := date.AddDate(0, 0, 1-days)
+	startDate := start.Format(types.DEFAULT_DATE_FORMAT)
+	endDate := date.AddDate(0, 0, 1).Format(types.DEFAULT_DATE_FORMAT)
+	windowStart, err := time.Parse(types.DEFAULT_DATE_FORMAT, window.StartDate)
+	if err != nil {
+		return nil, err
+	}
+	recent := int(date.AddDate(0, 0, 1).Sub(windowStart).Hours() / 24)
 
-	// TODO - Alert on each project for now do it for the aggregate
-	// Daily cost grouped by cloud product (AWS Service)
-	groupKey := "SERVICE"
-	resp, err := m.client.GetCostAndUsage(context.TODO(), &costexplorer.GetCostAndUsageInput{
-		TimePeriod: &ceTypes.DateInterval{Start: &startDate, End: &interval.EndDate},
-		Metrics:    []string{viper.GetString("cost.aws.datasets")},
-		// TODO - Need Account(i.e. Project) to filter
-		//Filter: &ceTypes.Expression{
-		//	Dimensions: &ceTypes.DimensionValues{
-		//		Key: ceTypes.DimensionLinkedAccount,
-		//		Values: []string{"ACCOUNT_ID"},
-		//	},
-		//},
+	accountKey := "LINKED_ACCOUNT"
+	serviceKey := "SERVICE"
+	results, err := m.getCostAndUsage(ctx, &costexplorer.GetCostAndUsageInput{
+		TimePeriod:  &ceTypes.DateInterval{Start: &startDate, End: &endDate},
+		Metrics:     []string{viper.GetString("cost.aws.datasets")},
+		Filter:      filter,
 		Granularity: ceTypes.GranularityDaily,
 		GroupBy: []ceTypes.GroupDefinition{
-			{Key: &groupKey, Type: ceTypes.GroupDefinitionTypeDimension},
+			{Key: &accountKey, Type: ceTypes.GroupDefinitionTypeDimension},
+			{Key: &serviceKey, Type: ceTypes.GroupDefinitionTypeDimension},
 		},
 	})
 	if err != nil {
-		return &pb.Entity{}, err
+		return nil, err
 	}
 
-	entities, err := getEntityAwsProducts(resp.ResultsByTime)
-	if err != nil {
-		return &pb.Entity{}, err
+	// Days without cost of a service are missing from the results and stay zero in its series
+	series := map[anomalyKey][]float64{}
+	for _, result := range results {
+		day, err := time.Parse(types.DEFAULT_DATE_FORMAT, *result.TimePeriod.Start)
+		if err != nil {
+			return nil, err
+		}
+		t := int(day.Sub(start).Hours() / 24)
+		if t < 0 || t >= days {
+			continue
+		}
+		for _, g := range result.Groups {
+			if len(g.Keys) < 2 {
+				continue
+			}
+			key := anomalyKey{account: g.Keys[0], service: g.Keys[1]}
+			if _, ok := series[key]; !ok {
+				series[key] = make([]float64, days)
+			}
+			for _, metric := range g.Metrics {
+				series[key][t] = getAwsMetricAmount(metric)
+			}
+		}
 	}
 
-	entity.Products = entities
+	minExcess := viper.GetFloat64("cost.alerts.anomaly.min_excess")
+	alerts := []*pb.Entity{}
+	for key, amounts := range series {
+		for _, anomaly := range utils.AnomaliesOf(amounts, recent, rule.Threshold, minExcess) {
+			alerts = append(alerts, m.anomalyAlert(ctx, key, start, amounts, anomaly))
+		}
+	}
+	sort.Slice(alerts, func(i, j int) bool {
+		if alerts[i].Change.Amount != alerts[j].Change.Amount {
+			return alerts[i].Change.Amount > alerts[j].Change.Amount
+		}
+		if alerts[i].Project != alerts[j].Project {
+			return alerts[i].Project < alerts[j].Project
+		}
+		if alerts[i].Id != alerts[j].Id {
+			return alerts[i].Id < alerts[j].Id
+		}
+		return alerts[i].StartDate < alerts[j].StartDate
+	})
+	return alerts, nil
+}
+
+// anomalyAlert
+// returns the alert of an anomaly of the daily amounts from start. The aggregation compares the
+// expected with the actual cost of the anomaly and the service the daily costs of its days.
+// With cost.round the costs and their change are all rounded.
+func (m costInsightsAwsServer) anomalyAlert(ctx context.Context, key anomalyKey, start time.Time, amounts []float64, anomaly utils.Anomaly) *pb.Entity {
+	round := func(amount float64) float64 { return amount }
+	if viper.GetBool("cost.round") {
+		round = math.Round
+	}
+	expected, actual := round(anomaly.Expected), round(anomaly.Actual)
+	days := []float64{}
+	for _, amount := range amounts[anomaly.Start:anomaly.End] {
+		days = append(days, round(amount))
+	}
+	change := &pb.ChangeStatistic{Amount: actual - expected}
+	if expected > 0 {
+		change.Ratio = float32((actual - expected) / expected)
+	}
+	return &pb.Entity{
+		Type:        CostAnomalyAlert,
+		Id:          key.service,
+		Project:     accounts.NameOf(ctx, m.accounts, key.account),
+		StartDate:   start.AddDate(0, 0, anomaly.Start).Format(types.DEFAULT_DATE_FORMAT),
+		EndDate:     start.AddDate(0, 0, anomaly.End-1).Format(types.DEFAULT_DATE_FORMAT),
+		Aggregation: []float64{expected, actual},
+		Change:      change,
+		Services: []*pb.Entity{
+			{
+				Id:          key.service,
+				Aggregation: days,
+				Change:      change,
+			},
+		},
+	}
+}
 
-	// TODO - CostInsights Plugin should accept Start and End date from duration without truncating it to month
-	start, err := time.Parse(types.DEFAULT_DATE_FORMAT, startDate)
+// growthAlerts
+// returns a ProjectGrowthAlert when the cost of the last window of the rule grew by at least
+// the threshold ratio from the window before, with the products driving the growth
+func (m costInsightsAwsServer) growthAlerts(ctx context.Context, filter *ceTypes.Expression, date time.Time, group string, rule AlertRule) ([]*pb.Entity, error) {
+	interval, err := utils.ParseIntervals(rule.intervals(2, date))
 	if err != nil {
-		return &pb.Entity{}, err
+		return nil, err
+	}
+	periods, err := utils.Buckets(interval)
+	if err != nil {
+		return nil, err
 	}
 
-	end, err := time.Parse(types.DEFAULT_DATE_FORMAT, interval.EndDate)
+	serviceKey := "SERVICE"
+	results, err := m.getCostAndUsage(ctx, &costexplorer.GetCostAndUsageInput{
+		TimePeriod:  &ceTypes.DateInterval{Start: &interval.StartDate, End: &interval.EndDate},
+		Metrics:     []string{viper.GetString("cost.aws.datasets")},
+		Filter:      filter,
+		Granularity: ceTypes.GranularityDaily,
+		GroupBy:     []ceTypes.GroupDefinition{{Key: &serviceKey, Type: ceTypes.GroupDefinitionTypeDimension}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	products, err := getEntityAwsProducts(results, periods, nil)
 	if err != nil {
-		return &pb.Entity{}, err
+		return nil, err
+	}
+	aggregation := utils.AggregationOfEntities(products, len(periods))
+	change := utils.ChangeOfEntity(aggregation)
+	if aggregation[0] <= 0 || float64(change.Ratio) < rule.Threshold {
+		return nil, nil
 	}
-	entity.PeriodStart = start.Format(types.ALERT_DATE_FORMAT)
-	entity.PeriodEnd = end.Format(types.ALERT_DATE_FORMAT)
 
-	entity.PeriodStart = "2020-Q2"
-	entity.PeriodEnd = "2020-Q3"
+	sort.Slice(products, func(i, j int) bool {
+		if products[i].Change.Amount != products[j].Change.Amount {
+			return products[i].Change.Amount > products[j].Change.Amount
+		}
+		return products[i].Id < products[j].Id
+	})
+	periodStart, err := utils.PeriodLabel(periods[0], interval.Duration)
+	if err != nil {
+		return nil, err
+	}
+	periodEnd, err := utils.PeriodLabel(periods[1], interval.Duration)
+	if err != nil {
+		return nil, err
+	}
+	return []*pb.Entity{
+		{
+			Type:        GrowthAlert,
+			Id:          rule.Name,
+			Project:     rule.scope(group),
+			PeriodStart: periodStart,
+			PeriodEnd:   periodEnd,
+			Aggregation: aggregation,
+			Change:      change,
+			Products:    products,
+		},
+	}, nil
+}
 
-	var startAggregate float64
-	var endAggregate float64
-	for _, e := range entity.Products {
-		startAggregate = startAggregate + e.Aggregation[0]
-		endAggregate = endAggregate + e.Aggregation[1]
+// spendAlerts
+// returns a CostThresholdAlert when the cost of the last window of the rule is over the
+// threshold amount, the aggregation compares the threshold with the actual cost
+func (m costInsightsAwsServer) spendAlerts(ctx context.Context, filter *ceTypes.Expression, date time.Time, group string, rule AlertRule) ([]*pb.Entity, error) {
+	interval, err := utils.ParseIntervals(rule.intervals(1, date))
+	if err != nil {
+		return nil, err
+	}
+	results, err := m.getCostAndUsage(ctx, &costexplorer.GetCostAndUsageInput{
+		TimePeriod:  &ceTypes.DateInterval{Start: &interval.StartDate, End: &interval.EndDate},
+		Metrics:     []string{viper.GetString("cost.aws.datasets")},
+		Filter:      filter,
+		Granularity: ceTypes.GranularityDaily,
+	})
+	if err != nil {
+		return nil, err
+	}
+	aggregation, err := aggregationForAWS(results)
+	if err != nil {
+		return nil, err
+	}
+	actual := 0.0
+	for _, day := range aggregation {
+		actual += day.Amount
+	}
+	if actual <= rule.Threshold {
+		return nil, nil
 	}
-	entity.Aggregation = []float64{startAggregate, endAggregate}
-	entity.Change = utils.ChangeOfEntity(entity.Aggregation)
 
-	return &entity, nil
+	end, err := time.Parse(types.DEFAULT_DATE_FORMAT, interval.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	change := &pb.ChangeStatistic{Amount: actual - rule.Threshold}
+	if rule.Threshold > 0 {
+		change.Ratio = float32((actual - rule.Threshold) / rule.Threshold)
+	}
+	return []*pb.Entity{
+		{
+			Type:        CostThresholdAlert,
+			Id:          rule.Name,
+			Project:     rule.scope(group),
+			StartDate:   interval.StartDate,
+			EndDate:     end.AddDate(0, 0, -1).Format(types.DEFAULT_DATE_FORMAT),
+			Aggregation: []float64{rule.Threshold, actual},
+			Change:      change,
+		},
+	}, nil
 }
 
-func ProjectGrowthAlert() (*pb.Entity, error) {
-	entity := pb.Entity{
-		Type:        "ProjectGrowthAlert",
-		Project:     "example-project",
-		PeriodStart: "2020-02",
-		PeriodEnd:   "2020-03",
-		Aggregation: []float64{60000, 120000},
-		Change: &pb.ChangeStatistic{
-			Ratio:  1,
-			Amount: 60000,
-		},
-		Products: []*pb.Entity{
-			&pb.Entity{
-				Id:          "Compute Engine",
-				Aggregation: []float64{50000, 118000},
-			},
-			&pb.Entity{
-				Id:          "Cloud Dataflow",
-				Aggregation: []float64{1200, 1500},
-			},
-			&pb.Entity{
-				Id:          "Cloud Storage",
-				Aggregation: []float64{800, 500},
-			},
+// unlabeledAlerts
+// returns an UnlabeledDataflowAlert when more than the threshold share of the cost of the last
+// window of the rule has no value of the tag_key cost allocation tag. The cost is grouped by
+// AWS Account and the tag, Cost Explorer groups the cost without the tag under an empty value,
+// and the projects break the labeled and unlabeled cost down by account, the largest unlabeled
+// cost first.
+func (m costInsightsAwsServer) unlabeledAlerts(ctx context.Context, filter *ceTypes.Expression, date time.Time, group string, rule AlertRule) ([]*pb.Entity, error) {
+	interval, err := utils.ParseIntervals(rule.intervals(1, date))
+	if err != nil {
+		return nil, err
+	}
+	accountKey := "LINKED_ACCOUNT"
+	results, err := m.getCostAndUsage(ctx, &costexplorer.GetCostAndUsageInput{
+		TimePeriod:  &ceTypes.DateInterval{Start: &interval.StartDate, End: &interval.EndDate},
+		Metrics:     []string{viper.GetString("cost.aws.datasets")},
+		Filter:      filter,
+		Granularity: ceTypes.GranularityDaily,
+		GroupBy: []ceTypes.GroupDefinition{
+			{Key: &accountKey, Type: ceTypes.GroupDefinitionTypeDimension},
+			{Key: &rule.TagKey, Type: ceTypes.GroupDefinitionTypeTag},
 		},
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return &entity, nil
+	projects := map[string]*pb.Entity{}
+	alert := &pb.Entity{}
+	for _, result := range results {
+		for _, g := range result.Groups {
+			if len(g.Keys) < 2 {
+				continue
+			}
+			var amount float64
+			// We expect only one metric 'UnblendedCost' in the map but we could query more
+			for _, metric := range g.Metrics {
+				amount = getAwsMetricAmount(metric)
+			}
+			project, ok := projects[g.Keys[0]]
+			if !ok {
+				project = &pb.Entity{Id: accounts.NameOf(ctx, m.accounts, g.Keys[0])}
+				projects[g.Keys[0]] = project
+			}
+			if strings.TrimPrefix(g.Keys[1], rule.TagKey+"$") == "" {
+				project.UnlabeledCost += amount
+				alert.UnlabeledCost += amount
+			} else {
+				project.LabeledCost += amount
+				alert.LabeledCost += amount
+			}
+		}
+	}
+	total := alert.LabeledCost + alert.UnlabeledCost
+	if alert.UnlabeledCost <= 0 || alert.UnlabeledCost/total <= rule.Threshold {
+		return nil, nil
+	}
+
+	for _, project := range projects {
+		if project.LabeledCost+project.UnlabeledCost > 0 {
+			alert.Projects = append(alert.Projects, project)
+		}
+	}
+	sort.Slice(alert.Projects, func(i, j int) bool {
+		if alert.Projects[i].UnlabeledCost != alert.Projects[j].UnlabeledCost {
+			return alert.Projects[i].UnlabeledCost > alert.Projects[j].UnlabeledCost
+		}
+		return alert.Projects[i].Id < alert.Projects[j].Id
+	})
+	end, err := time.Parse(types.DEFAULT_DATE_FORMAT, interval.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	alert.Type = TagCoverageAlert
+	alert.Id = rule.Name
+	alert.Project = rule.scope(group)
+	alert.PeriodStart = interval.StartDate
+	alert.PeriodEnd = end.AddDate(0, 0, -1).Format(types.DEFAULT_DATE_FORMAT)
+	return []*pb.Entity{alert}, nil
 }
 
-func UnlabeledAlert() (*pb.Entity, error) {
-	entity := pb.Entity{
-		Type:          "UnlabeledDataflowAlert",
-		PeriodStart:   "2020-09-1",
-		PeriodEnd:     "2020-09-30",
-		LabeledCost:   6200,
-		UnlabeledCost: 7000,
-		Projects: []*pb.Entity{
-			&pb.Entity{
-				Id:            "example-project-1",
-				UnlabeledCost: 5000,
-				LabeledCost:   3000,
-			},
-			&pb.Entity{
-				Id:            "example-project-2",
-				UnlabeledCost: 2000,
-				LabeledCost:   3200,
-			},
-		},
+// budgetAlerts
+// returns a budget alert when the cost of the fiscal month up to the last complete billing
+// date breaches the budget of the rule, or without one the budget of the group, or of the
+// project of the rule, in the cost.alerts.budget.path file. A group without budgets is not
+// alerted on. A BudgetBreachAlert, always critical, is raised when the cost spent reaches the
+// cost.alerts.budget.breach share of the budget, otherwise a BudgetBurnRateAlert when the run
+// rate of the month so far projects the cost of the month to the cost.alerts.budget.projected
+// share of the budget, or an info BudgetBurnRateAlert when the cost spent reaches the threshold
+// share of the budget. The aggregation is the budget, the cost spent and the projected cost of
+// the month, the overspend is never negative when a share below 1 alerts before the budget is
+// spent.
+func (m costInsightsAwsServer) budgetAlerts(ctx context.Context, filter *ceTypes.Expression, date time.Time, group string, rule AlertRule) ([]*pb.Entity, error) {
+	calendar, err := utils.NewFiscalCalendar()
+	if err != nil {
+		return nil, err
+	}
+	start, end := calendar.PeriodOf(date, 1)
+	startDate := start.Format(types.DEFAULT_DATE_FORMAT)
+	budget := rule.Budget
+	if budget == 0 {
+		budgets, err := metrics.LoadGroupBudgets(viper.GetString("cost.alerts.budget.path"))
+		if err != nil {
+			return nil, err
+		}
+		b, ok := metrics.BudgetOn(budgets[rule.scope(group)], startDate)
+		if !ok || b.BudgetTotal <= 0 {
+			return nil, nil
+		}
+		budget = b.BudgetTotal
 	}
 
-	return &entity, nil
+	endDate := date.AddDate(0, 0, 1).Format(types.DEFAULT_DATE_FORMAT)
+	results, err := m.getCostAndUsage(ctx, &costexplorer.GetCostAndUsageInput{
+		TimePeriod:  &ceTypes.DateInterval{Start: &startDate, End: &endDate},
+		Metrics:     []string{viper.GetString("cost.aws.datasets")},
+		Filter:      filter,
+		Granularity: ceTypes.GranularityDaily,
+	})
+	if err != nil {
+		return nil, err
+	}
+	aggregation, err := aggregationForAWS(results)
+	if err != nil {
+		return nil, err
+	}
+	actual := 0.0
+	for _, day := range aggregation {
+		actual += day.Amount
+	}
+	elapsed := date.Sub(start).Hours()/24 + 1
+	days := end.Sub(start).Hours() / 24
+	projected := actual / elapsed * days
+	if viper.GetBool("cost.round") {
+		projected = math.Round(projected)
+	}
+
+	alert := &pb.Entity{
+		Id:            rule.Name,
+		Project:       rule.scope(group),
+		StartDate:     startDate,
+		EndDate:       end.AddDate(0, 0, -1).Format(types.DEFAULT_DATE_FORMAT),
+		Aggregation:   []float64{budget, actual, projected},
+		DaysRemaining: int32(days - elapsed),
+	}
+	switch {
+	case actual >= viper.GetFloat64("cost.alerts.budget.breach")*budget:
+		alert.Type = BudgetBreachAlert
+		alert.Severity = CriticalSeverity
+		alert.Overspend = math.Max(0, actual-budget)
+	case projected >= viper.GetFloat64("cost.alerts.budget.projected")*budget:
+		alert.Type = BudgetBurnRateAlert
+		alert.Overspend = math.Max(0, projected-budget)
+	case actual >= rule.Threshold*budget:
+		alert.Type = BudgetBurnRateAlert
+		alert.Severity = InfoSeverity
+		alert.Overspend = math.Max(0, projected-budget)
+	default:
+		return nil, nil
+	}
+	alert.Change = &pb.ChangeStatistic{
+		Ratio:  float32(alert.Overspend / budget),
+		Amount: alert.Overspend,
+	}
+	return []*pb.Entity{alert}, nil
 }
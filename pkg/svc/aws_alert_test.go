@@ -0,0 +1,181 @@
+package svc
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/seizadi/cost-insights-backend/pkg/utils"
+)
+
+func TestBudgetAlertsFromFile(t *testing.T) {
+	setTestCostConfig()
+	server := newTestAwsServer(t)
+	path := filepath.Join(t.TempDir(), "budget.json")
+	budgets := `{
+		"platform": [{"Date": "2021-11-01", "BudgetTotal": 1000}, {"Date": "2021-09-01", "BudgetTotal": 12000}],
+		"data": [{"Date": "2021-09-01", "BudgetTotal": 100}]
+	}`
+	if err := ioutil.WriteFile(path, []byte(budgets), 0644); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	viper.Set("cost.alerts.budget.path", path)
+	viper.Set("cost.alerts.budget.projected", 2.0)
+	defer viper.Set("cost.alerts.budget.path", "")
+	defer viper.Set("cost.alerts.budget.projected", 1.0)
+
+	// 9974 spent of the budget of 12000 in effect since September is over the warning share,
+	// the projection of 22085 is under the projected share of two budgets
+	rule := AlertRule{Name: "platform-budget", Type: BudgetRule, Window: "P1M", Threshold: 0.8, Severity: WarningSeverity}
+	date := time.Date(2021, 10, 14, 0, 0, 0, 0, time.UTC)
+	filter, err := server.ruleFilter("platform", rule)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	alerts, err := server.budgetAlerts(context.Background(), filter, date, "platform", rule)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(alerts) != 1 {
+		t.Fatalf("Output %d alerts not equal to expected 1", len(alerts))
+	}
+	alert := alerts[0]
+	if alert.Type != BudgetBurnRateAlert || alert.Severity != InfoSeverity {
+		t.Errorf("Output %s %s alert not equal to expected info %s", alert.Severity, alert.Type, BudgetBurnRateAlert)
+	}
+	if alert.Aggregation[0] != 12000 || alert.Overspend != 10085 || alert.DaysRemaining != 17 {
+		t.Errorf("Unexpected budget alert %v", alert)
+	}
+
+	// A group without budgets in the file is not alerted on
+	if alerts, err := server.budgetAlerts(context.Background(), filter, date, "finance", rule); err != nil || len(alerts) != 0 {
+		t.Errorf("Output %v alerts without a budget, error %v", alerts, err)
+	}
+
+	// The month is under the threshold share of a larger budget
+	rule.Budget = 20000
+	if alerts, err := server.budgetAlerts(context.Background(), filter, date, "platform", rule); err != nil || len(alerts) != 0 {
+		t.Errorf("Output %v alerts under budget, error %v", alerts, err)
+	}
+
+	// A breach share below 1 alerts before the budget is spent, without a negative overspend
+	viper.Set("cost.alerts.budget.breach", 0.5)
+	defer viper.Set("cost.alerts.budget.breach", 1.0)
+	rule.Budget = 12000
+	alerts, err = server.budgetAlerts(context.Background(), filter, date, "platform", rule)
+	if err != nil || len(alerts) != 1 {
+		t.Fatalf("Output %v alerts not equal to expected 1, error %v", alerts, err)
+	}
+	if alert := alerts[0]; alert.Type != BudgetBreachAlert || alert.Overspend != 0 || alert.Change.Amount != 0 {
+		t.Errorf("Unexpected budget breach alert %v", alert)
+	}
+}
+
+func TestSavingsAlerts(t *testing.T) {
+	setTestCostConfig()
+	server := &costInsightsAwsServer{client: fakeCeClient{}, groups: testGroupDirectory, rules: testAlertRules, now: testNow}
+	viper.Set("cost.alerts.savings.term", "THREE_YEARS")
+	defer viper.Set("cost.alerts.savings.term", "ONE_YEAR")
+
+	// Only the Compute Savings Plans apply to Lambda, the EC2 reservations are left out
+	rule := AlertRule{Name: "lambda-savings", Type: SavingsRule, Project: "data-lake", Service: "Lambda", Window: "P60D", Severity: InfoSeverity}
+	date := time.Date(2021, 10, 14, 0, 0, 0, 0, time.UTC)
+	alerts, err := server.savingsAlerts(context.Background(), date, "data", rule)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(alerts) != 1 {
+		t.Fatalf("Output %d alerts not equal to expected 1", len(alerts))
+	}
+	alert := alerts[0]
+	if alert.Type != SavingsPlansAlert || alert.Id != "COMPUTE_SP" || alert.Term != "P3Y" {
+		t.Errorf("Output %s %s %s alert not equal to expected %s COMPUTE_SP P3Y", alert.Type, alert.Id, alert.Term, SavingsPlansAlert)
+	}
+	if alert.MonthlySavings != 2190 || alert.Aggregation[0] != 5475 || alert.StartDate != "2021-08-16" {
+		t.Errorf("Unexpected savings alert %v", alert)
+	}
+	// The linked accounts of the project, the largest savings first
+	if len(alert.Projects) != 2 || alert.Projects[0].Id != "333333333333" || alert.Projects[1].Id != "222222222222" {
+		t.Errorf("Unexpected projects %v", alert.Projects)
+	}
+
+	// The savings are under the threshold
+	rule.Threshold = 5000
+	if alerts, err := server.savingsAlerts(context.Background(), date, "data", rule); err != nil || len(alerts) != 0 {
+		t.Errorf("Output %v alerts under the threshold, error %v", alerts, err)
+	}
+}
+
+func TestRightsizingAlerts(t *testing.T) {
+	setTestCostConfig()
+	server := &costInsightsAwsServer{client: fakeCeClient{}, groups: testGroupDirectory, rules: testAlertRules, now: testNow}
+
+	rule := AlertRule{Name: "ec2-rightsizing", Type: RightsizingRule, Project: "data-lake", Window: "P14D", Threshold: 100, Severity: InfoSeverity}
+	date := time.Date(2021, 10, 14, 0, 0, 0, 0, time.UTC)
+	alerts, err := server.rightsizingAlerts(context.Background(), date, "data", rule)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// An alert for every linked account of the project, the largest savings first
+	if len(alerts) != 2 || alerts[0].Id != "333333333333" || alerts[1].Id != "222222222222" {
+		t.Fatalf("Unexpected alerts %v", alerts)
+	}
+	alert := alerts[0]
+	if alert.Type != RightsizingAlert || alert.Project != "data-lake" || alert.StartDate != "2021-10-01" {
+		t.Errorf("Unexpected rightsizing alert %v", alert)
+	}
+	if alert.MonthlySavings != 201 || alert.Aggregation[0] != 341 || alert.Aggregation[1] != 140 {
+		t.Errorf("Output %v savings of %v not equal to expected 201 of [341 140]", alert.MonthlySavings, alert.Aggregation)
+	}
+	if len(alert.Services) != 2 {
+		t.Fatalf("Output %d instances not equal to expected 2", len(alert.Services))
+	}
+	if instance := alert.Services[0]; instance.InstanceType != "m5.2xlarge" || instance.RecommendedInstanceType != "m5.xlarge" || instance.Change.Amount != -140 {
+		t.Errorf("Unexpected modified instance %v", instance)
+	}
+	if instance := alert.Services[1]; instance.InstanceType != "t3.large" || instance.RecommendedInstanceType != "" || instance.Aggregation[1] != 0 {
+		t.Errorf("Unexpected terminated instance %v", instance)
+	}
+
+	// Across instance families the instances are resized to another type
+	viper.Set("cost.alerts.rightsizing.target", "CROSS_INSTANCE_FAMILY")
+	defer viper.Set("cost.alerts.rightsizing.target", "SAME_INSTANCE_FAMILY")
+	rule.Threshold = 200
+	alerts, err = server.rightsizingAlerts(context.Background(), date, "data", rule)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(alerts) != 1 || alerts[0].Services[0].RecommendedInstanceType != "c5.xlarge" || alerts[0].MonthlySavings != 217 {
+		t.Errorf("Unexpected alerts across instance families %v", alerts)
+	}
+}
+
+func TestAnomalyAlertRounding(t *testing.T) {
+	setTestCostConfig()
+	server := costInsightsAwsServer{groups: testGroupDirectory}
+	key := anomalyKey{account: "111111111111", service: "AWS Lambda"}
+	start := time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC)
+	amounts := []float64{10.4, 10.6, 40.4, 30.3}
+	anomaly := utils.Anomaly{Start: 2, End: 4, Expected: 20.6, Actual: 70.7}
+
+	// The expected and actual costs, their change and the days of the service are all rounded
+	alert := server.anomalyAlert(context.Background(), key, start, amounts, anomaly)
+	if alert.Aggregation[0] != 21 || alert.Aggregation[1] != 71 || alert.Change.Amount != 50 {
+		t.Errorf("Output %v %v not equal to expected rounded costs", alert.Aggregation, alert.Change)
+	}
+	if days := alert.Services[0].Aggregation; len(days) != 2 || days[0] != 40 || days[1] != 30 {
+		t.Errorf("Output %v not equal to expected rounded days", days)
+	}
+
+	// Without rounding none of them are
+	viper.Set("cost.round", false)
+	defer viper.Set("cost.round", true)
+	alert = server.anomalyAlert(context.Background(), key, start, amounts, anomaly)
+	if alert.Aggregation[0] != 20.6 || alert.Aggregation[1] != 70.7 || alert.Services[0].Aggregation[0] != 40.4 {
+		t.Errorf("Output %v %v not equal to expected costs", alert.Aggregation, alert.Services[0].Aggregation)
+	}
+}
@@ -2,38 +2,53 @@ package svc
 
 import (
 	"context"
+	"fmt"
 	"math"
+	"sort"
 	"strconv"
 	"time"
 
 	"github.com/spf13/viper"
 
-	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
 	ceTypes "github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
 	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/logrus/ctxlogrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/seizadi/cost-insights-backend/metrics"
+	"github.com/seizadi/cost-insights-backend/pkg/accounts"
 	"github.com/seizadi/cost-insights-backend/pkg/pb"
+	"github.com/seizadi/cost-insights-backend/pkg/store"
 	"github.com/seizadi/cost-insights-backend/pkg/types"
 	"github.com/seizadi/cost-insights-backend/pkg/utils"
 )
 
 type costInsightsAwsServer struct {
-	client *costexplorer.Client
+	client   CostExplorerClient
+	groups   *GroupDirectory
+	accounts accounts.Directory
+	rules    []AlertRule
+	statuses AlertStatusStore
+	catalog  *productCatalog
+	now      func() time.Time
 }
 
+// AWS_SERVICE
+// are the short ids of well-known Cost Explorer services, the product ids of GetProducts and
+// the service names of alert rules. The ids of the other services are derived from their name.
 var AWS_SERVICE = map[string]string{
 	"EC2":           "Amazon Elastic Compute Cloud - Compute",
 	"EC2Other":      "EC2 - Other",
 	"S3":            "Amazon Simple Storage Service",
 	"DynamoDB":      "Amazon DynamoDB",
-	"ElasticSearch": "Amazon Elasticsearch Service",
+	"ElasticSearch": "Amazon OpenSearch Service",
 	"CloudWatch":    "Amazon CloudWatch",
-	"CloudTrail":    "Amazon CloudTrail",
+	"CloudTrail":    "AWS CloudTrail",
 	"RDS":           "Amazon Relational Database Service",
 	"ELB":           "Amazon Elastic Load Balancing",
-	"EMR":           "Amazon Elastic MadReduce",
+	"EMR":           "Amazon Elastic MapReduce",
 	"MSK":           "Amazon Managed Streaming for Apache Kafka",
 	"Lambda":        "AWS Lambda",
 	"SNS":           "Amazon Simple Notification Service",
@@ -90,25 +105,100 @@ var AwsAccounts = map[AwsAccountType]AwsAccount{
 	},
 }
 
-func NewCeClient() (*costexplorer.Client, error) {
-	cfg, err := config.LoadDefaultConfig(context.TODO())
+// NewCostInsightsApiAwsServer
+// returns an instance of the default server interface, s is the database store of the daily
+// costs and the alert statuses, nil when database.enable is false
+func NewCostInsightsApiAwsServer(s store.Store) (CostProvider, error) {
+	client, err := NewCeClient(s)
 	if err != nil {
 		return nil, err
 	}
 
-	client := costexplorer.NewFromConfig(cfg)
-	return client, nil
-}
+	groups, err := NewGroupDirectory()
+	if err != nil {
+		return nil, err
+	}
 
-// NewCostInsightsApiAwsServer
-// returns an instance of the default server interface
-func NewCostInsightsApiAwsServer() (pb.CostInsightsApiServer, error) {
-	client, err := NewCeClient()
+	directory, err := accounts.NewDirectory()
 	if err != nil {
 		return nil, err
 	}
 
-	return &costInsightsAwsServer{client: client}, nil
+	rules, err := NewAlertRules()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ValidateProductBreakdowns(); err != nil {
+		return nil, err
+	}
+
+	return &costInsightsAwsServer{client: client, groups: groups, accounts: directory, rules: rules, statuses: NewAlertStatusStore(s), catalog: newProductCatalog(), now: time.Now}, nil
+}
+
+func (costInsightsAwsServer) Name() string {
+	return AwsCostProvider
+}
+
+// getCostAndUsage
+// Runs a CostExplorer query following NextPageToken until the last page. Grouped queries over
+// long windows are paginated by AWS, a page can end in the middle of a day so the groups of a
+// day may be split across pages. The pages are merged into one ResultByTime per time period.
+// Queries grouped by RESOURCE_ID are run with GetCostAndUsageWithResources.
+//
+func (m costInsightsAwsServer) getCostAndUsage(ctx context.Context, input *costexplorer.GetCostAndUsageInput) ([]ceTypes.ResultByTime, error) {
+	results := []ceTypes.ResultByTime{}
+	index := map[string]int{}
+	page := *input
+	for {
+		resp, err := m.costAndUsagePage(ctx, &page)
+		if err != nil {
+			return nil, err
+		}
+		for _, result := range resp.ResultsByTime {
+			key := *result.TimePeriod.Start + "/" + *result.TimePeriod.End
+			i, ok := index[key]
+			if !ok {
+				index[key] = len(results)
+				results = append(results, result)
+				continue
+			}
+			results[i].Groups = append(results[i].Groups, result.Groups...)
+			if len(results[i].Total) == 0 {
+				results[i].Total = result.Total
+			}
+			results[i].Estimated = results[i].Estimated || result.Estimated
+		}
+		if resp.NextPageToken == nil || *resp.NextPageToken == "" {
+			return results, nil
+		}
+		page.NextPageToken = resp.NextPageToken
+	}
+}
+
+// costAndUsagePage
+// returns a page of the query, from GetCostAndUsageWithResources when it is grouped by resource
+func (m costInsightsAwsServer) costAndUsagePage(ctx context.Context, input *costexplorer.GetCostAndUsageInput) (*costexplorer.GetCostAndUsageOutput, error) {
+	if !groupsByResource(input.GroupBy) {
+		return m.client.GetCostAndUsage(ctx, input)
+	}
+	resp, err := m.client.GetCostAndUsageWithResources(ctx, &costexplorer.GetCostAndUsageWithResourcesInput{
+		TimePeriod:    input.TimePeriod,
+		Granularity:   input.Granularity,
+		Filter:        input.Filter,
+		Metrics:       input.Metrics,
+		GroupBy:       input.GroupBy,
+		NextPageToken: input.NextPageToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &costexplorer.GetCostAndUsageOutput{
+		DimensionValueAttributes: resp.DimensionValueAttributes,
+		GroupDefinitions:         resp.GroupDefinitions,
+		NextPageToken:            resp.NextPageToken,
+		ResultsByTime:            resp.ResultsByTime,
+	}, nil
 }
 
 // getAwsMetricAmount
@@ -247,7 +337,7 @@ func getGroupedAwsProducts(results []ceTypes.ResultByTime) ([]*pb.ProductCost, e
 	}
 
 	filteredCosts := []*pb.ProductCost{}
-	for _, index := range keys {
+	for index := range costs {
 		if len(costs[index].Aggregation) > 0 {
 			filteredCosts = append(filteredCosts, costs[index])
 		}
@@ -289,7 +379,7 @@ func getGroupedAwsProjects(results []ceTypes.ResultByTime) ([]*pb.ProjectCost, e
 	}
 
 	filteredCosts := []*pb.ProjectCost{}
-	for _, index := range keys {
+	for index := range costs {
 		if len(costs[index].Aggregation) > 0 {
 			filteredCosts = append(filteredCosts, costs[index])
 		}
@@ -299,66 +389,92 @@ func getGroupedAwsProjects(results []ceTypes.ResultByTime) ([]*pb.ProjectCost, e
 }
 
 // getEntityAwsProducts
-// Retrieves Entities for AWS Products (i.e. AWS Services) Costs from CostExplorer API
+// Retrieves Entities for AWS Products (i.e. AWS Services) Costs from CostExplorer API. The
+// entities are the first group key, with subEntities the costs of the second group key of an
+// entity are its nested subEntities of the record.
 //
-func getEntityAwsProducts(results []ceTypes.ResultByTime) ([]*pb.Entity, error) {
+func getEntityAwsProducts(results []ceTypes.ResultByTime, periods []types.Period, subEntities func(*pb.Record) *[]*pb.Entity) ([]*pb.Entity, error) {
 	keys := getGroupedAwsKeyIndex(results)
 	costs := make([]*pb.Entity, len(keys))
+	subCosts := make([][]*pb.Entity, len(keys))
+	subKeys := make([]map[string]int, len(keys))
 
-	for key, index := range keys {
-		entity := &pb.Entity{
-			Id: key,
-			// TODO - Fix the hard coded values for Aggregation and Change
-			Aggregation: []float64{0, 0},
+	newEntity := func(key string) *pb.Entity {
+		return &pb.Entity{
+			Id:          key,
+			Aggregation: make([]float64, len(periods)),
 			Change:      &pb.ChangeStatistic{},
 			Entities:    &pb.Record{},
 		}
-		costs[index] = entity
+	}
+	for key, index := range keys {
+		costs[index] = newEntity(key)
+		subKeys[index] = map[string]int{}
 	}
 
-	// TODO - Compute Aggregation and Change
-	// The ResultsByTime objects provide a Groups array with an entry for each resource and its costs for the
-	// given day. You'll need to aggregate cost data into two bucketed time periods (e.g. month vs month,
-	// or quarter vs quarter) for each resource since this is the expected data type for the Aggregation
-	// field on Entity.
-
-	midPoint := len(results) / 2
-
-	for i, result := range results {
+	// The ResultsByTime objects provide a Groups array with an entry for each resource and its
+	// costs for the given day, the costs are summed into the bucketed time periods of the
+	// interval (e.g. month vs month, or quarter vs quarter).
+	for _, result := range results {
+		bucket := utils.BucketOf(periods, *result.TimePeriod.Start)
+		if bucket < 0 {
+			continue
+		}
 		for _, group := range result.Groups {
 			var amount float64
 			// We expect only one metric 'UnblendedCost' in the map but we could query more
 			for _, metric := range group.Metrics {
 				amount = getAwsMetricAmount(metric)
 			}
-
-			if i >= midPoint {
-				costs[keys[group.Keys[0]]].Aggregation[1] = costs[keys[group.Keys[0]]].Aggregation[1] + amount
-			} else {
-				costs[keys[group.Keys[0]]].Aggregation[0] = costs[keys[group.Keys[0]]].Aggregation[0] + amount
+			index := keys[group.Keys[0]]
+			costs[index].Aggregation[bucket] += amount
+			if subEntities == nil || len(group.Keys) < 2 {
+				continue
+			}
+			sub, ok := subKeys[index][group.Keys[1]]
+			if !ok {
+				sub = len(subCosts[index])
+				subKeys[index][group.Keys[1]] = sub
+				subCosts[index] = append(subCosts[index], newEntity(group.Keys[1]))
 			}
+			subCosts[index][sub].Aggregation[bucket] += amount
 		}
 	}
 
-	filteredCosts := []*pb.Entity{}
-	for _, index := range keys {
-		if !(costs[index].Aggregation[0] == 0 && costs[index].Aggregation[1] == 0) {
-			costs[index].Change = utils.ChangeOfEntity(costs[index].Aggregation)
-			filteredCosts = append(filteredCosts, costs[index])
+	filteredCosts := nonZeroAwsEntities(costs)
+	if subEntities != nil {
+		for index := range costs {
+			*subEntities(costs[index].Entities) = nonZeroAwsEntities(subCosts[index])
 		}
 	}
-
 	return filteredCosts, nil
 }
 
+// nonZeroAwsEntities
+// returns the entities with cost in a period, with the change of their aggregation
+func nonZeroAwsEntities(entities []*pb.Entity) []*pb.Entity {
+	filtered := []*pb.Entity{}
+	for _, entity := range entities {
+		zero := true
+		for _, amount := range entity.Aggregation {
+			zero = zero && amount == 0
+		}
+		if !zero {
+			entity.Change = utils.ChangeOfEntity(entity.Aggregation)
+			filtered = append(filtered, entity)
+		}
+	}
+	return filtered
+}
+
 // GetLastCompleteBillingDate
 // returns the most current date for which billing data is complete, in YYYY-MM-DD format. This helps
 // define the intervals used in other API methods to avoid showing incomplete cost. The costs for
 // today, for example, will not be complete. This ideally comes from the cloud provider.
 //
 // Implements CostInsightsApiClient getLastCompleteBillingDate(): Promise<string>;
-func (costInsightsAwsServer) GetLastCompleteBillingDate(context.Context, *empty.Empty) (*pb.LastCompleteBillingDateResponse, error) {
-	date := time.Now().AddDate(0, 0, -1).Format(types.DEFAULT_DATE_FORMAT)
+func (m costInsightsAwsServer) GetLastCompleteBillingDate(context.Context, *empty.Empty) (*pb.LastCompleteBillingDateResponse, error) {
+	date := m.now().AddDate(0, 0, -1).Format(types.DEFAULT_DATE_FORMAT)
 	return &pb.LastCompleteBillingDateResponse{Date: date}, nil
 }
 
@@ -374,30 +490,96 @@ func (costInsightsAwsServer) GetLastCompleteBillingDate(context.Context, *empty.
 // Implements CostInsightsApiClient getUserGroups(userId: string): Promise<Group[]>;
 //
 
-func (costInsightsAwsServer) GetUserGroups(context.Context, *pb.UserGroupsRequest) (*pb.UserGroupsResponse, error) {
-	groups := []*pb.Group{
-		{Id: "default-group"},
-	}
-	return &pb.UserGroupsResponse{Groups: groups}, nil
+func (m costInsightsAwsServer) GetUserGroups(ctx context.Context, req *pb.UserGroupsRequest) (*pb.UserGroupsResponse, error) {
+	return &pb.UserGroupsResponse{Groups: m.groups.GroupsOf(req.UserId)}, nil
 }
 
 // GetUserGroups
 // Get a list of cloud billing entities that belong to this group (projects in GCP, AWS has a
 // similar concept in billing accounts). These act as filters for the displayed costs, users can
-// choose whether they see all costs for a group, or those from a particular owned project.
+// choose whether they see all costs for a group, or those from a particular owned project. A
+// group with the "*" wildcard also lists the AWS Accounts with cost in Cost Explorer.
 //
 // @param group The group id from getUserGroups or query parameters
 // Implements CostInsightsApiClient getGroupProjects(group: string): Promise<Project[]>;
-//TODO: Make a call to AWS and find all the accounts that a particular user has access to
-func (costInsightsAwsServer) GetGroupProjects(context.Context, *pb.GroupProjectsRequest) (*pb.GroupProjectsResponse, error) {
-	projects := []*pb.Project{
-		{Id: "project-a"},
-		{Id: "project-b"},
-		{Id: "project-c"},
+func (m costInsightsAwsServer) GetGroupProjects(ctx context.Context, req *pb.GroupProjectsRequest) (*pb.GroupProjectsResponse, error) {
+	projects := []*pb.Project{}
+
+	// Without a group mapping the default group owns every account in the account directory
+	if !m.groups.Configured() && m.accounts != nil {
+		all, err := m.accounts.Accounts(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, account := range all {
+			projects = append(projects, &pb.Project{Id: account.Id, Name: account.Name})
+		}
+		return &pb.GroupProjectsResponse{Projects: projects}, nil
+	}
+
+	groupAccounts, err := m.groups.AccountsOf(req.Group)
+	if err != nil {
+		return nil, err
+	}
+
+	listed := map[string]bool{}
+	for _, account := range groupAccounts {
+		name := account.Name
+		if name == "" {
+			name = accounts.NameOf(ctx, m.accounts, account.Id)
+		}
+		projects = append(projects, &pb.Project{Id: account.Id, Name: name})
+		listed[account.Id] = true
+	}
+
+	// A group of all accounts also sees the accounts with cost that are not one of its projects
+	if m.groups.SeesAllAccounts(req.Group) {
+		linkedAccounts, err := m.discoverLinkedAccounts(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range linkedAccounts {
+			if !listed[id] {
+				projects = append(projects, &pb.Project{Id: id, Name: accounts.NameOf(ctx, m.accounts, id)})
+			}
+		}
 	}
 	return &pb.GroupProjectsResponse{Projects: projects}, nil
 }
 
+// discoverLinkedAccounts
+// returns the AWS Accounts with cost in the last cost.products.lookback, from the LINKED_ACCOUNT
+// dimension of Cost Explorer, sorted by id
+func (m costInsightsAwsServer) discoverLinkedAccounts(ctx context.Context) ([]string, error) {
+	interval, err := utils.ParseIntervals(fmt.Sprintf("R1/%s/%s", viper.GetString("cost.products.lookback"), m.now().Format(types.DEFAULT_DATE_FORMAT)))
+	if err != nil {
+		return nil, err
+	}
+	params := &costexplorer.GetDimensionValuesInput{
+		TimePeriod: &ceTypes.DateInterval{Start: &interval.StartDate, End: &interval.EndDate},
+		Dimension:  ceTypes.DimensionLinkedAccount,
+		Context:    ceTypes.ContextCostAndUsage,
+	}
+	linkedAccounts := []string{}
+	for {
+		resp, err := m.client.GetDimensionValues(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		for _, value := range resp.DimensionValues {
+			if value.Value != nil && *value.Value != "" {
+				linkedAccounts = append(linkedAccounts, *value.Value)
+			}
+		}
+		if resp.NextPageToken == nil || *resp.NextPageToken == "" {
+			break
+		}
+		params.NextPageToken = resp.NextPageToken
+	}
+	sort.Strings(linkedAccounts)
+	return linkedAccounts, nil
+}
+
 // GetGroupDailyCost
 // Get daily cost aggregations for a given group and interval time frame.
 //
@@ -423,33 +605,34 @@ func (m costInsightsAwsServer) GetGroupDailyCost(ctx context.Context, req *pb.Gr
 		return nil, err
 	}
 
-	startDate, err := utils.InclusiveStartDateOf(interval.Duration, interval.EndDate)
+	startDate := interval.StartDate
+
+	// Each group only sees the cost of its own accounts
+	groupAccounts, err := m.groups.LinkedAccountsOfGroup(req.Group)
 	if err != nil {
 		return nil, err
 	}
+	filter := linkedAccountFilter(groupAccounts)
 
-	resp, err := m.client.GetCostAndUsage(ctx, &costexplorer.GetCostAndUsageInput{
-		TimePeriod: &ceTypes.DateInterval{Start: &startDate, End: &interval.EndDate},
-		Metrics:    []string{viper.GetString("cost.aws.datasets")},
-		// TODO - Need a way to map Group to Account(i.e. Project) to filter
-		//Filter: &ceTypes.Expression{
-		//	Dimensions: &ceTypes.DimensionValues{
-		//		Key: ceTypes.DimensionLinkedAccount,
-		//		Values: []string{"ACCOUNT_ID"},
-		//	},
-		//},
+	results, err := m.getCostAndUsage(ctx, &costexplorer.GetCostAndUsageInput{
+		TimePeriod:  &ceTypes.DateInterval{Start: &startDate, End: &interval.EndDate},
+		Metrics:     []string{viper.GetString("cost.aws.datasets")},
+		Filter:      filter,
 		Granularity: ceTypes.GranularityDaily,
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	aggregation, err := aggregationForAWS(resp.ResultsByTime)
+	aggregation, err := aggregationForAWS(results)
 	if err != nil {
 		return &pb.GroupDailyCostResponse{}, err
 	}
 	cost.Aggregation = aggregation
-	cost.Change = utils.ChangeOf(aggregation)
+	cost.Change, err = utils.ChangeOf(aggregation, req.Intervals)
+	if err != nil {
+		return &pb.GroupDailyCostResponse{}, err
+	}
 	trendline, err := utils.TrendlineOf(aggregation)
 	if err != nil {
 		return &pb.GroupDailyCostResponse{}, err
@@ -461,16 +644,10 @@ func (m costInsightsAwsServer) GetGroupDailyCost(ctx context.Context, req *pb.Gr
 	cost.GroupedCosts = &pb.GroupedCosts{}
 
 	groupKey := "SERVICE"
-	respProductGrouped, err := m.client.GetCostAndUsage(context.TODO(), &costexplorer.GetCostAndUsageInput{
-		TimePeriod: &ceTypes.DateInterval{Start: &startDate, End: &interval.EndDate},
-		Metrics:    []string{viper.GetString("cost.aws.datasets")},
-		// TODO - Need a way to map Group to Account(i.e. Project) to filter
-		//Filter: &ceTypes.Expression{
-		//	Dimensions: &ceTypes.DimensionValues{
-		//		Key: ceTypes.DimensionLinkedAccount,
-		//		Values: []string{"ACCOUNT_ID"},
-		//	},
-		//},
+	productResults, err := m.getCostAndUsage(ctx, &costexplorer.GetCostAndUsageInput{
+		TimePeriod:  &ceTypes.DateInterval{Start: &startDate, End: &interval.EndDate},
+		Metrics:     []string{viper.GetString("cost.aws.datasets")},
+		Filter:      filter,
 		Granularity: ceTypes.GranularityDaily,
 		GroupBy: []ceTypes.GroupDefinition{
 			{Key: &groupKey, Type: ceTypes.GroupDefinitionTypeDimension},
@@ -480,7 +657,7 @@ func (m costInsightsAwsServer) GetGroupDailyCost(ctx context.Context, req *pb.Gr
 		return nil, err
 	}
 
-	cost.GroupedCosts.Product, err = getGroupedAwsProducts(respProductGrouped.ResultsByTime)
+	cost.GroupedCosts.Product, err = getGroupedAwsProducts(productResults)
 	if err != nil {
 		return &cost, err
 	}
@@ -488,16 +665,10 @@ func (m costInsightsAwsServer) GetGroupDailyCost(ctx context.Context, req *pb.Gr
 	// Optional field providing cost groupings / breakdowns keyed by the type. In this example,
 	// daily cost grouped by cloud product OR by project / billing account.
 	groupKey = "LINKED_ACCOUNT"
-	respProjectGrouped, err := m.client.GetCostAndUsage(context.TODO(), &costexplorer.GetCostAndUsageInput{
-		TimePeriod: &ceTypes.DateInterval{Start: &startDate, End: &interval.EndDate},
-		Metrics:    []string{viper.GetString("cost.aws.datasets")},
-		// TODO - Need a way to map Group to Account(i.e. Project) to filter
-		//Filter: &ceTypes.Expression{
-		//	Dimensions: &ceTypes.DimensionValues{
-		//		Key: ceTypes.DimensionLinkedAccount,
-		//		Values: []string{"ACCOUNT_ID"},
-		//	},
-		//},
+	projectResults, err := m.getCostAndUsage(ctx, &costexplorer.GetCostAndUsageInput{
+		TimePeriod:  &ceTypes.DateInterval{Start: &startDate, End: &interval.EndDate},
+		Metrics:     []string{viper.GetString("cost.aws.datasets")},
+		Filter:      filter,
 		Granularity: ceTypes.GranularityDaily,
 		GroupBy: []ceTypes.GroupDefinition{
 			{Key: &groupKey, Type: ceTypes.GroupDefinitionTypeDimension},
@@ -507,10 +678,14 @@ func (m costInsightsAwsServer) GetGroupDailyCost(ctx context.Context, req *pb.Gr
 		return nil, err
 	}
 
-	cost.GroupedCosts.Project, err = getGroupedAwsProjects(respProjectGrouped.ResultsByTime)
+	cost.GroupedCosts.Project, err = getGroupedAwsProjects(projectResults)
 	if err != nil {
 		return &cost, err
 	}
+	// The breakdown keeps the account Ids, which GetProjectDailyCost takes, and shows the names
+	for _, project := range cost.GroupedCosts.Project {
+		project.Name = accounts.NameOf(ctx, m.accounts, project.Id)
+	}
 
 	return &cost, nil
 }
@@ -538,7 +713,10 @@ func (costInsightsAwsServer) GetDailyMetricData(ctx context.Context, req *pb.Dai
 	//	return &pb.DailyMetricDataResponse{}, err
 	//}
 	cost.Aggregation = aggregation
-	cost.Change = utils.ChangeOf(aggregation)
+	cost.Change, err = utils.ChangeOf(aggregation, req.Intervals)
+	if err != nil {
+		return &pb.DailyMetricDataResponse{}, err
+	}
 	trendline, err := utils.TrendlineOf(aggregation)
 	if err != nil {
 		return &pb.DailyMetricDataResponse{}, err
@@ -573,33 +751,33 @@ func (m costInsightsAwsServer) GetProjectDailyCost(ctx context.Context, req *pb.
 		return nil, err
 	}
 
-	startDate, err := utils.InclusiveStartDateOf(interval.Duration, interval.EndDate)
+	startDate := interval.StartDate
+
+	linkedAccounts, err := m.groups.LinkedAccountsOf(req.Project)
 	if err != nil {
 		return nil, err
 	}
+	filter := linkedAccountFilter(linkedAccounts)
 
-	resp, err := m.client.GetCostAndUsage(context.TODO(), &costexplorer.GetCostAndUsageInput{
-		TimePeriod: &ceTypes.DateInterval{Start: &startDate, End: &interval.EndDate},
-		Metrics:    []string{viper.GetString("cost.aws.datasets")},
-		// TODO - Need a way to map Project to Account to filter Project Detail
-		//Filter: &ceTypes.Expression{
-		//	Dimensions: &ceTypes.DimensionValues{
-		//		Key: ceTypes.DimensionLinkedAccount,
-		//		Values: []string{"ACCOUNT_ID"},
-		//	},
-		//},
+	results, err := m.getCostAndUsage(ctx, &costexplorer.GetCostAndUsageInput{
+		TimePeriod:  &ceTypes.DateInterval{Start: &startDate, End: &interval.EndDate},
+		Metrics:     []string{viper.GetString("cost.aws.datasets")},
+		Filter:      filter,
 		Granularity: ceTypes.GranularityDaily,
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	aggregation, err := aggregationForAWS(resp.ResultsByTime)
+	aggregation, err := aggregationForAWS(results)
 	if err != nil {
 		return &pb.ProjectDailyCostResponse{}, err
 	}
 	cost.Aggregation = aggregation
-	cost.Change = utils.ChangeOf(aggregation)
+	cost.Change, err = utils.ChangeOf(aggregation, req.Intervals)
+	if err != nil {
+		return &pb.ProjectDailyCostResponse{}, err
+	}
 	trendline, err := utils.TrendlineOf(aggregation)
 	if err != nil {
 		return &pb.ProjectDailyCostResponse{}, err
@@ -611,16 +789,10 @@ func (m costInsightsAwsServer) GetProjectDailyCost(ctx context.Context, req *pb.
 	cost.GroupedCosts = &pb.GroupedCosts{}
 
 	groupKey := "SERVICE"
-	respGrouped, err := m.client.GetCostAndUsage(context.TODO(), &costexplorer.GetCostAndUsageInput{
-		TimePeriod: &ceTypes.DateInterval{Start: &startDate, End: &interval.EndDate},
-		Metrics:    []string{viper.GetString("cost.aws.datasets")},
-		// TODO - Need Account(i.e. Project) to filter
-		//Filter: &ceTypes.Expression{
-		//	Dimensions: &ceTypes.DimensionValues{
-		//		Key: ceTypes.DimensionLinkedAccount,
-		//		Values: []string{"ACCOUNT_ID"},
-		//	},
-		//},
+	groupedResults, err := m.getCostAndUsage(ctx, &costexplorer.GetCostAndUsageInput{
+		TimePeriod:  &ceTypes.DateInterval{Start: &startDate, End: &interval.EndDate},
+		Metrics:     []string{viper.GetString("cost.aws.datasets")},
+		Filter:      filter,
 		Granularity: ceTypes.GranularityDaily,
 		GroupBy: []ceTypes.GroupDefinition{
 			{Key: &groupKey, Type: ceTypes.GroupDefinitionTypeDimension},
@@ -630,7 +802,7 @@ func (m costInsightsAwsServer) GetProjectDailyCost(ctx context.Context, req *pb.
 		return nil, err
 	}
 
-	cost.GroupedCosts.Product, err = getGroupedAwsProducts(respGrouped.ResultsByTime)
+	cost.GroupedCosts.Product, err = getGroupedAwsProducts(groupedResults)
 	if err != nil {
 		return &cost, err
 	}
@@ -650,16 +822,16 @@ func (m costInsightsAwsServer) GetProjectDailyCost(ctx context.Context, req *pb.
 // The time period is supplied as a Duration rather than intervals, since this is always expected
 // to return data for two bucketed time period (e.g. month vs month, or quarter vs quarter).
 //
-// @param Project to filter for only a specific Project
+// The entities break down the cost by the ProductBreakdown of the product in
+// cost.insights.products, the cost.insights.group_by tag or dimension by default.
+//
+// @param Project to filter for only a specific Project of the Group
 // @param Group to filter for query
 // @param Product to filter only selected cloud product
 // @param Interval to filter for the selected duration of time
 //
 // Implements CostInsightsApiClient getProductInsights(options: ProductInsightsOptions): Promise<Entity>;
 func (m costInsightsAwsServer) GetProductInsights(ctx context.Context, req *pb.ProductInsightsRequest) (*pb.Entity, error) {
-	// TODO - Need to be able to specify the cost Tag(s) that are used for the query
-	// TODO - Need able to filter based on Product, Project or Group
-
 	entity := &pb.Entity{}
 
 	interval, err := utils.ParseIntervals(req.Intervals)
@@ -667,81 +839,347 @@ func (m costInsightsAwsServer) GetProductInsights(ctx context.Context, req *pb.P
 		return nil, err
 	}
 
-	startDate, err := utils.InclusiveStartDateOf(interval.Duration, interval.EndDate)
+	startDate := interval.StartDate
+
+	// The product insights are for the accounts of the project when one is selected, otherwise
+	// for all the accounts of the group
+	var linkedAccounts []string
+	if req.Project != "" {
+		linkedAccounts, err = m.groups.LinkedAccountsOfGroupProject(req.Group, req.Project)
+	} else {
+		linkedAccounts, err = m.groups.LinkedAccountsOfGroup(req.Group)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	// TODO - groupKey is the Cost Tag Name should be configurable (defaults to Product)
-	groupKey := "Product"
-
-	resp, err := m.client.GetCostAndUsage(context.TODO(), &costexplorer.GetCostAndUsageInput{
-		TimePeriod: &ceTypes.DateInterval{Start: &startDate, End: &interval.EndDate},
-		Metrics:    []string{viper.GetString("cost.aws.datasets")},
-		// TODO - Need Account(i.e. Project) to filter
-		// TODO - Use Group to select Account(s) (i.e. Projects) to filter
-		Filter: &ceTypes.Expression{
-			Dimensions: &ceTypes.DimensionValues{
-				Key:    ceTypes.DimensionService,
-				Values: []string{AWS_SERVICE[req.Product]},
-			},
+	period := &ceTypes.DateInterval{Start: &startDate, End: &interval.EndDate}
+	service, err := m.productService(ctx, req.Product, period, linkedAccounts)
+	if err != nil {
+		return nil, err
+	}
+	filter := &ceTypes.Expression{
+		Dimensions: &ceTypes.DimensionValues{
+			Key:    ceTypes.DimensionService,
+			Values: []string{service},
 		},
+	}
+	if accountFilter := linkedAccountFilter(linkedAccounts); accountFilter != nil {
+		filter = &ceTypes.Expression{And: []ceTypes.Expression{*filter, *accountFilter}}
+	}
+
+	breakdown, err := productBreakdownOf(req.Product)
+	if err != nil {
+		return nil, err
+	}
+	groupBy, err := breakdown.groupBy()
+	if err != nil {
+		return nil, err
+	}
+
+	// The costs by resource are only served for the last days, the entities are queried for the
+	// whole intervals and their resources for the part of the intervals in the last days
+	input := &costexplorer.GetCostAndUsageInput{
+		TimePeriod:  period,
+		Metrics:     []string{viper.GetString("cost.aws.datasets")},
+		Filter:      filter,
 		Granularity: ceTypes.GranularityDaily,
-		GroupBy: []ceTypes.GroupDefinition{
-			{Key: &groupKey, Type: ceTypes.GroupDefinitionTypeTag},
-		},
-	})
+		GroupBy:     groupBy,
+	}
+	if groupsByResource(groupBy) {
+		input.GroupBy = groupBy[:1]
+	}
+	results, err := m.getCostAndUsage(ctx, input)
 	if err != nil {
 		return nil, err
 	}
 
 	entity.Id = req.Product
 
-	entities, err := getEntityAwsProducts(resp.ResultsByTime)
+	periods, err := utils.Buckets(interval)
+	if err != nil {
+		return nil, err
+	}
+	var entities []*pb.Entity
+	if !groupsByResource(groupBy) {
+		entities, err = getEntityAwsProducts(results, periods, breakdown.subEntities())
+	} else {
+		entities, err = getEntityAwsProducts(results, periods, nil)
+		if err == nil {
+			err = m.resourceEntities(ctx, entities, input, groupBy, periods, breakdown.subEntities())
+		}
+	}
 	if err != nil {
 		return entity, err
 	}
 
 	entity.Entities = &pb.Record{Service: entities}
 
-	// We aggregate cost data into two bucketed time periods (e.g. month vs month, or quarter vs quarter).
-	// For each half we will walk through the Entities and add their aggregate to form the Aggregation
+	// We aggregate cost data into the bucketed time periods (e.g. month vs month, or quarter vs quarter).
+	// For each bucket we will walk through the Entities and add their aggregate to form the Aggregation
 	//field on Entity.
-
-	var startAggregate float64
-	var endAggregate float64
-	for _, e := range entity.Entities.Service {
-		startAggregate = startAggregate + e.Aggregation[0]
-		endAggregate = endAggregate + e.Aggregation[1]
-	}
-	entity.Aggregation = []float64{startAggregate, endAggregate}
+	entity.Aggregation = utils.AggregationOfEntities(entity.Entities.Service, len(periods))
 	entity.Change = utils.ChangeOfEntity(entity.Aggregation)
 
 	return entity, nil
 }
 
+// resourceEntities
+// fills the nested entities of the entities with the costs of their resources, queried grouped
+// by the resource groups for the part of the period of the input in the last days served by
+// GetCostAndUsageWithResources. The resources are left empty when the period ends before.
+func (m costInsightsAwsServer) resourceEntities(ctx context.Context, entities []*pb.Entity, input *costexplorer.GetCostAndUsageInput, groupBy []ceTypes.GroupDefinition, periods []types.Period, subEntities func(*pb.Record) *[]*pb.Entity) error {
+	window := resourceWindow(input.TimePeriod, m.now())
+	if window == nil {
+		return nil
+	}
+	resourceInput := *input
+	resourceInput.TimePeriod = window
+	resourceInput.GroupBy = groupBy
+	results, err := m.getCostAndUsage(ctx, &resourceInput)
+	if err != nil {
+		return err
+	}
+	resourceEntities, err := getEntityAwsProducts(results, periods, subEntities)
+	if err != nil {
+		return err
+	}
+	resources := map[string]*pb.Entity{}
+	for _, resourceEntity := range resourceEntities {
+		resources[resourceEntity.Id] = resourceEntity
+	}
+	for _, entity := range entities {
+		if resourceEntity, ok := resources[entity.Id]; ok {
+			*subEntities(entity.Entities) = *subEntities(resourceEntity.Entities)
+		}
+	}
+	return nil
+}
+
 // GetAlerts
 //
 // Get current cost alerts for a given group. These show up as Action Items for the group on the
 // Cost Insights page. Alerts may include cost-saving recommendations, such as infrastructure
 // migrations, or cost-related warnings, such as an unexpected billing anomaly.
 //
+// The alerts are raised by the cost.alerts.rules evaluated for the group, each alert has the
+// severity of its rule, a stable alertId and the status set with SetAlertStatus until it expires.
+//
 // Implements CostInsightsApiClient getAlerts(group: string): Promise<Alert[]>;
 func (m costInsightsAwsServer) GetAlerts(ctx context.Context, req *pb.AlertRequest) (*pb.AlertResponse, error) {
-	alerts := []*pb.Entity{}
-	growthAlert, err := m.ProjectGrowthAlert()
+	alerts, err := m.RuleAlerts(ctx, req.Group)
+	if err != nil {
+		return &pb.AlertResponse{}, err
+	}
+	alerts, err = flagAlerts(ctx, m.statuses, req.Group, m.now(), alerts)
 	if err != nil {
 		return &pb.AlertResponse{}, err
 	}
 
-	alerts = append(alerts, growthAlert)
+	return &pb.AlertResponse{Alerts: alerts}, nil
+}
 
-	//unlabeledAlert, err := UnlabeledAlert()
-	//if err != nil {
-	//	return &pb.AlertResponse{}, err
-	//}
-	//
-	//alerts = append(alerts, unlabeledAlert)
+// SetAlertStatus
+// Snooze, accept or dismiss an alert of a group with a reason and feedback, or make it active
+// again. The status is persisted by the alertId of the alert and lasts until the end of the
+// duration, snoozes default to cost.alerts.snooze and dismissals to cost.alerts.dismiss.
+//
+// Implements the CostInsightsApiClient alert snooze, accept and dismiss actions
+func (m costInsightsAwsServer) SetAlertStatus(ctx context.Context, req *pb.AlertStatusRequest) (*pb.AlertStatusResponse, error) {
+	if _, err := m.groups.AccountsOf(req.Group); err != nil {
+		return nil, err
+	}
+	alertStatus, err := alertStatusOf(req, m.now())
+	if err != nil {
+		return nil, err
+	}
+	if err := m.statuses.SaveAlertStatus(ctx, alertStatus); err != nil {
+		return nil, err
+	}
+	return &pb.AlertStatusResponse{Status: &pb.AlertStatus{
+		Group:    alertStatus.Group,
+		AlertId:  alertStatus.AlertId,
+		Status:   alertStatus.Status,
+		Reason:   alertStatus.Reason,
+		Feedback: alertStatus.Feedback,
+		Until:    alertStatus.Until,
+	}}, nil
+}
 
-	return &pb.AlertResponse{Alerts: alerts}, nil
+// GetCommitmentCoverage
+// Get the daily share of the usage of the accounts of a group, or of a project, covered by
+// Savings Plans and reservations, by account and service. Each series carries the change of
+// its percentages over the intervals and a trendline so that the commitment health can be
+// plotted next to the daily costs.
+//
+// @param group The group id from getUserGroups
+// @param project The project id from getGroupProjects of the group, the accounts of the project instead of the group
+// @param service An AWS service name such as EC2 or a Cost Explorer service, defaults to cost.commitment.services
+// @param intervals An ISO 8601 repeating interval string, such as R2/P30D/2020-09-01
+func (m costInsightsAwsServer) GetCommitmentCoverage(ctx context.Context, req *pb.CommitmentRequest) (*pb.CommitmentResponse, error) {
+	return m.commitmentMetrics(ctx, req, []commitmentMetric{
+		{id: SavingsPlansCoverageMetric, byService: true, query: m.savingsPlansCoverage},
+		{id: ReservationCoverageMetric, byService: true, query: m.reservationCoverage},
+	})
+}
+
+// GetCommitmentUtilization
+// Get the daily share of the Savings Plans commitment and of the reserved hours of the accounts
+// of a group, or of a project, that is used. The Savings Plans utilization is by account, the
+// reservation utilization by account and service, with the parameters of GetCommitmentCoverage.
+func (m costInsightsAwsServer) GetCommitmentUtilization(ctx context.Context, req *pb.CommitmentRequest) (*pb.CommitmentResponse, error) {
+	return m.commitmentMetrics(ctx, req, []commitmentMetric{
+		{id: SavingsPlansUtilizationMetric, query: m.savingsPlansUtilization},
+		{id: ReservationUtilizationMetric, byService: true, query: m.reservationUtilization},
+	})
+}
+
+// GetProducts
+// Get the products with cost for the accounts of a group, or of a project, in the intervals or
+// else the last cost.products.lookback. The id of a product is the product of
+// GetProductInsights, a short AWS_SERVICE name for well-known services, so that the product
+// panels of Backstage can be configured from the backend.
+//
+// @param group The group id from getUserGroups, required with a group mapping
+// @param project The project id from getGroupProjects of the group, the accounts of the project instead of the group
+// @param intervals An ISO 8601 repeating interval string, such as R2/P30D/2021-09-01
+func (m costInsightsAwsServer) GetProducts(ctx context.Context, req *pb.ProductsRequest) (*pb.ProductsResponse, error) {
+	intervals := req.Intervals
+	if intervals == "" {
+		intervals = fmt.Sprintf("R1/%s/%s", viper.GetString("cost.products.lookback"), m.now().Format(types.DEFAULT_DATE_FORMAT))
+	}
+	interval, err := utils.ParseIntervals(intervals)
+	if err != nil {
+		return nil, err
+	}
+	// With a group mapping the products of a group or of its projects only
+	if m.groups.Configured() && req.Group == "" {
+		return nil, status.Error(codes.InvalidArgument, "products without a group")
+	}
+	var linkedAccounts []string
+	if req.Project != "" {
+		linkedAccounts, err = m.groups.LinkedAccountsOfGroupProject(req.Group, req.Project)
+	} else {
+		linkedAccounts, err = m.groups.LinkedAccountsOfGroup(req.Group)
+	}
+	if err != nil {
+		return nil, err
+	}
+	products, err := m.discoverProducts(ctx, &ceTypes.DateInterval{Start: &interval.StartDate, End: &interval.EndDate}, linkedAccounts)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ProductsResponse{Products: products}, nil
+}
+
+// GetCostForecast
+// Forecast the daily cost of a project, or of a group when no project is given, to the end of
+// the month, quarter or fiscal year with a confidence band. The forecast is computed locally from
+// the daily cost history, with cost.forecast.cross_check the Cost Explorer forecast of the same
+// period is returned alongside it as CrossCheck.
+//
+// @param group The group id from getUserGroups
+// @param project The project id from getGroupProjects of the group, forecasts the project instead of the group
+// @param horizon One of month, quarter or year of the fiscal calendar, defaults to month
+// @param date The last day of actual costs, defaults to the last complete billing date
+// @param confidence The probability of the band, defaults to cost.forecast.confidence
+func (m costInsightsAwsServer) GetCostForecast(ctx context.Context, req *pb.CostForecastRequest) (*pb.CostForecastResponse, error) {
+	w, err := forecastWindowFor(ctx, m, req)
+	if err != nil {
+		return nil, err
+	}
+
+	id := req.Group
+	var linkedAccounts []string
+	if req.Project != "" {
+		id = req.Project
+		linkedAccounts, err = m.groups.LinkedAccountsOfGroupProject(req.Group, req.Project)
+	} else {
+		linkedAccounts, err = m.groups.LinkedAccountsOfGroup(req.Group)
+	}
+	if err != nil {
+		return nil, err
+	}
+	filter := linkedAccountFilter(linkedAccounts)
+
+	startDate := w.HistoryStart.Format(types.DEFAULT_DATE_FORMAT)
+	endDate := w.historyEnd()
+	results, err := m.getCostAndUsage(ctx, &costexplorer.GetCostAndUsageInput{
+		TimePeriod:  &ceTypes.DateInterval{Start: &startDate, End: &endDate},
+		Metrics:     []string{viper.GetString("cost.aws.datasets")},
+		Filter:      filter,
+		Granularity: ceTypes.GranularityDaily,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	aggregation, err := aggregationForAWS(results)
+	if err != nil {
+		return nil, err
+	}
+	forecast, err := costForecastOf(id, w, aggregation)
+	if err != nil {
+		return nil, err
+	}
+
+	if viper.GetBool("cost.round") {
+		roundForecast(forecast)
+	}
+
+	if viper.GetBool("cost.forecast.cross_check") && len(forecast.Forecast) > 0 {
+		// The cross check only compares, the local forecast is served without it
+		forecast.CrossCheck, err = m.crossCheckForecast(ctx, w, filter, forecast.Actual)
+		if err != nil {
+			ctxlogrus.Extract(ctx).Warnf("Cost Explorer forecast cross check of %s failed: %v", id, err)
+		}
+	}
+	return forecast, nil
+}
+
+// crossCheckForecast
+// returns the Cost Explorer forecast of the rest of the window added to the actual cost so far,
+// comparable with the Total of the local forecast. Cost Explorer takes the prediction interval
+// as a whole percentage between 51 and 99.
+func (m costInsightsAwsServer) crossCheckForecast(ctx context.Context, w forecastWindow, filter *ceTypes.Expression, actual float64) (*pb.ForecastAggregation, error) {
+	level := int32(math.Round(w.Confidence * 100))
+	if level < 51 {
+		level = 51
+	} else if level > 99 {
+		level = 99
+	}
+	startDate := w.historyEnd()
+	endDate := w.End.Format(types.DEFAULT_DATE_FORMAT)
+	resp, err := m.client.GetCostForecast(ctx, &costexplorer.GetCostForecastInput{
+		TimePeriod:              &ceTypes.DateInterval{Start: &startDate, End: &endDate},
+		Metric:                  ceTypes.Metric(viper.GetString("cost.aws.datasets")),
+		Filter:                  filter,
+		Granularity:             ceTypes.GranularityMonthly,
+		PredictionIntervalLevel: &level,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	total := &pb.ForecastAggregation{
+		Date:   w.End.AddDate(0, 0, -1).Format(types.DEFAULT_DATE_FORMAT),
+		Amount: actual,
+		Lower:  actual,
+		Upper:  actual,
+	}
+	for _, result := range resp.ForecastResultsByTime {
+		total.Amount += forecastAmount(result.MeanValue)
+		total.Lower += forecastAmount(result.PredictionIntervalLowerBound)
+		total.Upper += forecastAmount(result.PredictionIntervalUpperBound)
+	}
+	return total, nil
+}
+
+// forecastAmount
+// parses an amount of a Cost Explorer forecast like a cost amount, a missing amount is zero
+func forecastAmount(amount *string) float64 {
+	if amount == nil {
+		return 0
+	}
+	return getAwsMetricAmount(ceTypes.MetricValue{Amount: amount})
 }
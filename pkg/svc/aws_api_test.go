@@ -1,11 +1,19 @@
 package svc
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"strconv"
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
 	ceTypes "github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
 	"github.com/spf13/viper"
+
+	"github.com/seizadi/cost-insights-backend/pkg/pb"
+	"github.com/seizadi/cost-insights-backend/pkg/types"
 )
 
 type TestMetricAmount struct {
@@ -166,3 +174,181 @@ func TestAggregationForAws(t *testing.T) {
 	// Test to see if changing the business type alters the aggregation sum value
 
 }
+
+// pagingCeClient
+// splits the groups returned by the fake client into pages of pageSize groups, so the groups of a
+// day can span pages like they do with Cost Explorer. The other queries are answered by the fake
+// client.
+type pagingCeClient struct {
+	fakeCeClient
+	pageSize int
+	calls    int
+}
+
+func (p *pagingCeClient) GetCostAndUsage(ctx context.Context, params *costexplorer.GetCostAndUsageInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostAndUsageOutput, error) {
+	p.calls++
+	all, err := fakeCeClient{}.GetCostAndUsage(ctx, params, optFns...)
+	if err != nil {
+		return nil, err
+	}
+
+	offset := 0
+	if params.NextPageToken != nil {
+		offset, _ = strconv.Atoi(*params.NextPageToken)
+	}
+
+	resp := &costexplorer.GetCostAndUsageOutput{}
+	position := 0
+	for _, result := range all.ResultsByTime {
+		page := result
+		page.Groups = nil
+		for _, group := range result.Groups {
+			if position >= offset && position < offset+p.pageSize {
+				page.Groups = append(page.Groups, group)
+			}
+			position++
+		}
+		if len(page.Groups) > 0 {
+			resp.ResultsByTime = append(resp.ResultsByTime, page)
+		}
+	}
+	if offset+p.pageSize < position {
+		token := strconv.Itoa(offset + p.pageSize)
+		resp.NextPageToken = &token
+	}
+	return resp, nil
+}
+
+func TestGetCostAndUsagePagination(t *testing.T) {
+	start := "2021-06-01"
+	end := "2021-09-01"
+	groupKey := "SERVICE"
+	input := &costexplorer.GetCostAndUsageInput{
+		TimePeriod:  &ceTypes.DateInterval{Start: &start, End: &end},
+		Metrics:     []string{string(ceTypes.MetricNetAmortizedCost)},
+		Granularity: ceTypes.GranularityDaily,
+		GroupBy: []ceTypes.GroupDefinition{
+			{Key: &groupKey, Type: ceTypes.GroupDefinitionTypeDimension},
+		},
+	}
+
+	expected, err := fakeCeClient{}.GetCostAndUsage(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// A page size of 7 splits the 3 services of a day across pages
+	client := &pagingCeClient{pageSize: 7}
+	server := costInsightsAwsServer{client: client}
+	results, err := server.getCostAndUsage(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if client.calls < 2 {
+		t.Errorf("Output %d calls, expected the query to be paginated", client.calls)
+	}
+	if input.NextPageToken != nil {
+		t.Error("Input of the caller should not be modified")
+	}
+
+	expectedData, _ := json.Marshal(expected.ResultsByTime)
+	actualData, _ := json.Marshal(results)
+	if string(expectedData) != string(actualData) {
+		t.Errorf("Merged pages %s not equal to expected %s", actualData, expectedData)
+	}
+}
+
+// commitmentCeClient counts the commitment queries of the fake client
+type commitmentCeClient struct {
+	fakeCeClient
+	calls int
+}
+
+func (c *commitmentCeClient) GetSavingsPlansCoverage(ctx context.Context, params *costexplorer.GetSavingsPlansCoverageInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetSavingsPlansCoverageOutput, error) {
+	c.calls++
+	return c.fakeCeClient.GetSavingsPlansCoverage(ctx, params, optFns...)
+}
+
+func (c *commitmentCeClient) GetReservationCoverage(ctx context.Context, params *costexplorer.GetReservationCoverageInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetReservationCoverageOutput, error) {
+	c.calls++
+	return c.fakeCeClient.GetReservationCoverage(ctx, params, optFns...)
+}
+
+func (c *commitmentCeClient) GetReservationUtilization(ctx context.Context, params *costexplorer.GetReservationUtilizationInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetReservationUtilizationOutput, error) {
+	c.calls++
+	return c.fakeCeClient.GetReservationUtilization(ctx, params, optFns...)
+}
+
+func TestCommitmentMetricsByAccount(t *testing.T) {
+	setTestCostConfig()
+	directory := &GroupDirectory{groups: []GroupMapping{{
+		Id:       "platform",
+		Users:    []string{"user"},
+		Accounts: []GroupAccount{{Id: "111111111111"}, {Id: "222222222222"}},
+	}}}
+	client := &commitmentCeClient{}
+	server := costInsightsAwsServer{client: client, groups: directory}
+
+	// The coverage of the 2 accounts and 3 services is one Savings Plans query by service for
+	// every account and one reservation query by account for every service
+	resp, err := server.GetCommitmentCoverage(context.Background(), &pb.CommitmentRequest{Group: "platform", Intervals: "R2/P7D/2021-09-01"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if client.calls != 5 {
+		t.Errorf("Output %d queries not equal to expected 5", client.calls)
+	}
+	series := map[string]float64{}
+	for _, metric := range resp.Metrics {
+		series[metric.Id+":"+metric.Account+":"+metric.Service] = metric.Aggregation[0].Amount
+	}
+	if len(series) != 8 {
+		t.Errorf("Output %d series not equal to expected 8: %v", len(series), series)
+	}
+	if series["reservationCoverage:111111111111:"+AWS_SERVICE["RDS"]] == series["reservationCoverage:222222222222:"+AWS_SERVICE["RDS"]] {
+		t.Errorf("Output %v does not hold the coverage of every account", series)
+	}
+
+	// The utilization of the reservations of an account adds up their hours
+	client.calls = 0
+	resp, err = server.GetCommitmentUtilization(context.Background(), &pb.CommitmentRequest{Group: "platform", Service: "RDS", Intervals: "R2/P7D/2021-09-01"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if client.calls != 1 {
+		t.Errorf("Output %d reservation queries not equal to expected 1", client.calls)
+	}
+	for _, metric := range resp.Metrics {
+		if metric.Id != ReservationUtilizationMetric {
+			continue
+		}
+		date, _ := time.Parse(types.DEFAULT_DATE_FORMAT, metric.Aggregation[0].Date)
+		expected, _ := fakeCePercent(commitmentFilter([]string{metric.Account}, []string{metric.Service}), []string{AWS_SERVICE["EC2"], AWS_SERVICE["RDS"]}, 70, date)
+		if strconv.FormatFloat(metric.Aggregation[0].Amount, 'f', 2, 64) != *expected {
+			t.Errorf("Output %v of %s not equal to expected %s", metric.Aggregation[0].Amount, metric.Account, *expected)
+		}
+	}
+}
+
+// failingForecastCeClient fails the Cost Explorer forecast of the fake client
+type failingForecastCeClient struct {
+	fakeCeClient
+}
+
+func (failingForecastCeClient) GetCostForecast(ctx context.Context, params *costexplorer.GetCostForecastInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostForecastOutput, error) {
+	return nil, errors.New("forecast unavailable")
+}
+
+func TestGetCostForecastWithoutCrossCheck(t *testing.T) {
+	setTestCostConfig()
+	server := costInsightsAwsServer{client: failingForecastCeClient{}, groups: testGroupDirectory, now: testNow}
+
+	// The local forecast is served without the cross check Cost Explorer failed
+	forecast, err := server.GetCostForecast(context.Background(), &pb.CostForecastRequest{Group: "platform", Date: "2021-08-15"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(forecast.Forecast) == 0 || forecast.CrossCheck != nil {
+		t.Errorf("Output %v not equal to expected a forecast without a cross check", forecast)
+	}
+}
@@ -0,0 +1,377 @@
+package svc
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	ceTypes "github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+	"github.com/spf13/viper"
+
+	"github.com/seizadi/cost-insights-backend/pkg/accounts"
+	"github.com/seizadi/cost-insights-backend/pkg/pb"
+	"github.com/seizadi/cost-insights-backend/pkg/utils"
+)
+
+// Ids of the daily commitment percentages of GetCommitmentCoverage and GetCommitmentUtilization
+const (
+	SavingsPlansCoverageMetric    = "savingsPlansCoverage"
+	ReservationCoverageMetric     = "reservationCoverage"
+	SavingsPlansUtilizationMetric = "savingsPlansUtilization"
+	ReservationUtilizationMetric  = "reservationUtilization"
+)
+
+// commitmentMetric
+// is a commitment percentage and the query of its days, the Savings Plans utilization is not by
+// service
+type commitmentMetric struct {
+	id        string
+	byService bool
+	query     func(ctx context.Context, period *ceTypes.DateInterval, linkedAccounts []string, services []string) (commitmentSeries, error)
+}
+
+// commitmentKey is the account and the service of a series of commitment percentages, empty for
+// all accounts or all services
+type commitmentKey struct {
+	account string
+	service string
+}
+
+// commitmentSeries are the daily commitment percentages by account and service
+type commitmentSeries map[commitmentKey][]*pb.DateAggregation
+
+// add appends the percentage of a day to the series of the account and the service
+func (s commitmentSeries) add(account string, service string, day *pb.DateAggregation) {
+	key := commitmentKey{account: account, service: service}
+	s[key] = append(s[key], day)
+}
+
+// commitmentMetrics
+// returns the daily percentages of the metrics for every account of the project, or of the
+// group, and for every service of the request or of cost.commitment.services. An account and
+// service without commitments has no days and is left out. A group of all accounts has a
+// single series of all accounts. The change of a series compares the mean percentage of the
+// first and the last interval.
+func (m costInsightsAwsServer) commitmentMetrics(ctx context.Context, req *pb.CommitmentRequest, metrics []commitmentMetric) (*pb.CommitmentResponse, error) {
+	interval, err := utils.ParseIntervals(req.Intervals)
+	if err != nil {
+		return nil, err
+	}
+	var linkedAccounts []string
+	if req.Project != "" {
+		linkedAccounts, err = m.groups.LinkedAccountsOfGroupProject(req.Group, req.Project)
+	} else {
+		linkedAccounts, err = m.groups.LinkedAccountsOfGroup(req.Group)
+	}
+	if err != nil {
+		return nil, err
+	}
+	names := viper.GetStringSlice("cost.commitment.services")
+	if req.Service != "" {
+		names = []string{req.Service}
+	}
+	services := []string{}
+	for _, service := range names {
+		if name, ok := AWS_SERVICE[service]; ok {
+			service = name
+		}
+		services = append(services, service)
+	}
+
+	period := &ceTypes.DateInterval{Start: &interval.StartDate, End: &interval.EndDate}
+	resp := &pb.CommitmentResponse{Metrics: []*pb.CommitmentMetricData{}}
+	for _, metric := range metrics {
+		var metricServices []string
+		if metric.byService {
+			metricServices = services
+		}
+		series, err := metric.query(ctx, period, linkedAccounts, metricServices)
+		if err != nil {
+			return nil, err
+		}
+		for _, account := range orAll(linkedAccounts) {
+			for _, service := range orAll(metricServices) {
+				aggregation := series[commitmentKey{account: account, service: service}]
+				if len(aggregation) == 0 {
+					continue
+				}
+				data := &pb.CommitmentMetricData{
+					Id:          metric.id,
+					Format:      "percent",
+					Account:     accounts.NameOf(ctx, m.accounts, account),
+					Service:     service,
+					Aggregation: aggregation,
+				}
+				if data.Change, err = utils.MeanChangeOf(aggregation, req.Intervals); err != nil {
+					return nil, err
+				}
+				if data.Trendline, err = utils.TrendlineOf(aggregation); err != nil {
+					return nil, err
+				}
+				resp.Metrics = append(resp.Metrics, data)
+			}
+		}
+	}
+	return resp, nil
+}
+
+// orAll returns the values, or a single empty value for all of them
+func orAll(values []string) []string {
+	if len(values) == 0 {
+		return []string{""}
+	}
+	return values
+}
+
+// commitmentFilter
+// returns the CostExplorer filter on the accounts and the services that are set, or nil
+func commitmentFilter(linkedAccounts []string, services []string) *ceTypes.Expression {
+	expressions := []ceTypes.Expression{}
+	if accounts := setValues(linkedAccounts); len(accounts) > 0 {
+		expressions = append(expressions, *linkedAccountFilter(accounts))
+	}
+	if services := setValues(services); len(services) > 0 {
+		expressions = append(expressions, ceTypes.Expression{
+			Dimensions: &ceTypes.DimensionValues{Key: ceTypes.DimensionService, Values: services},
+		})
+	}
+	switch len(expressions) {
+	case 0:
+		return nil
+	case 1:
+		return &expressions[0]
+	}
+	return &ceTypes.Expression{And: expressions}
+}
+
+// setValues returns the values that are not empty
+func setValues(values []string) []string {
+	set := []string{}
+	for _, value := range values {
+		if value != "" {
+			set = append(set, value)
+		}
+	}
+	return set
+}
+
+// commitmentGroupBy returns the Cost Explorer group by the dimension
+func commitmentGroupBy(dimension ceTypes.Dimension) []ceTypes.GroupDefinition {
+	key := string(dimension)
+	return []ceTypes.GroupDefinition{{Type: ceTypes.GroupDefinitionTypeDimension, Key: &key}}
+}
+
+// commitmentAttribute
+// returns the attribute of a Cost Explorer group with one of the keys, compared regardless of
+// case and underscores as Cost Explorer names a LINKED_ACCOUNT group linkedAccount, empty when
+// the group has none of them
+func commitmentAttribute(attributes map[string]string, keys ...string) string {
+	normal := func(key string) string {
+		return strings.ToLower(strings.ReplaceAll(key, "_", ""))
+	}
+	for _, key := range keys {
+		for name, value := range attributes {
+			if normal(name) == normal(key) {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+// commitmentDay
+// returns the percentage of a day, false when Cost Explorer has no percentage for the day
+func commitmentDay(period *ceTypes.DateInterval, percent *string) (*pb.DateAggregation, bool) {
+	if period == nil || period.Start == nil || percent == nil {
+		return nil, false
+	}
+	amount, err := strconv.ParseFloat(*percent, 64)
+	if err != nil {
+		return nil, false
+	}
+	return &pb.DateAggregation{Date: *period.Start, Amount: amount}, true
+}
+
+// savingsPlansCoverage
+// returns the daily share of the on-demand eligible spend covered by Savings Plans, with a query
+// by service for every account as Savings Plans coverage is not grouped by account
+func (m costInsightsAwsServer) savingsPlansCoverage(ctx context.Context, period *ceTypes.DateInterval, linkedAccounts []string, services []string) (commitmentSeries, error) {
+	series := commitmentSeries{}
+	for _, account := range orAll(linkedAccounts) {
+		params := &costexplorer.GetSavingsPlansCoverageInput{
+			TimePeriod:  period,
+			Filter:      commitmentFilter([]string{account}, services),
+			Granularity: ceTypes.GranularityDaily,
+			GroupBy:     commitmentGroupBy(ceTypes.DimensionService),
+		}
+		for {
+			resp, err := m.client.GetSavingsPlansCoverage(ctx, params)
+			if err != nil {
+				return nil, err
+			}
+			for _, coverage := range resp.SavingsPlansCoverages {
+				if coverage.Coverage == nil {
+					continue
+				}
+				if day, ok := commitmentDay(coverage.TimePeriod, coverage.Coverage.CoveragePercentage); ok {
+					series.add(account, commitmentAttribute(coverage.Attributes, string(ceTypes.DimensionService)), day)
+				}
+			}
+			if resp.NextToken == nil || *resp.NextToken == "" {
+				break
+			}
+			params.NextToken = resp.NextToken
+		}
+	}
+	return series, nil
+}
+
+// savingsPlansUtilization
+// returns the daily share of the Savings Plans commitment used, with a query for every account
+// as Savings Plans utilization has no groups
+func (m costInsightsAwsServer) savingsPlansUtilization(ctx context.Context, period *ceTypes.DateInterval, linkedAccounts []string, services []string) (commitmentSeries, error) {
+	series := commitmentSeries{}
+	for _, account := range orAll(linkedAccounts) {
+		resp, err := m.client.GetSavingsPlansUtilization(ctx, &costexplorer.GetSavingsPlansUtilizationInput{
+			TimePeriod:  period,
+			Filter:      commitmentFilter([]string{account}, nil),
+			Granularity: ceTypes.GranularityDaily,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, utilization := range resp.SavingsPlansUtilizationsByTime {
+			if utilization.Utilization == nil {
+				continue
+			}
+			if day, ok := commitmentDay(utilization.TimePeriod, utilization.Utilization.UtilizationPercentage); ok {
+				series.add(account, "", day)
+			}
+		}
+	}
+	return series, nil
+}
+
+// reservationCoverage
+// returns the daily share of the running instance hours covered by reservations, with a query
+// by account for every service as reservation coverage is not grouped by service
+func (m costInsightsAwsServer) reservationCoverage(ctx context.Context, period *ceTypes.DateInterval, linkedAccounts []string, services []string) (commitmentSeries, error) {
+	series := commitmentSeries{}
+	for _, service := range orAll(services) {
+		params := &costexplorer.GetReservationCoverageInput{
+			TimePeriod:  period,
+			Filter:      commitmentFilter(linkedAccounts, []string{service}),
+			Granularity: ceTypes.GranularityDaily,
+		}
+		if len(linkedAccounts) > 0 {
+			params.GroupBy = commitmentGroupBy(ceTypes.DimensionLinkedAccount)
+		}
+		for {
+			resp, err := m.client.GetReservationCoverage(ctx, params)
+			if err != nil {
+				return nil, err
+			}
+			for _, coverage := range resp.CoveragesByTime {
+				if params.GroupBy == nil {
+					if coverage.Total == nil || coverage.Total.CoverageHours == nil {
+						continue
+					}
+					if day, ok := commitmentDay(coverage.TimePeriod, coverage.Total.CoverageHours.CoverageHoursPercentage); ok {
+						series.add("", service, day)
+					}
+					continue
+				}
+				for _, group := range coverage.Groups {
+					if group.Coverage == nil || group.Coverage.CoverageHours == nil {
+						continue
+					}
+					if day, ok := commitmentDay(coverage.TimePeriod, group.Coverage.CoverageHours.CoverageHoursPercentage); ok {
+						series.add(commitmentAttribute(group.Attributes, string(ceTypes.DimensionLinkedAccount)), service, day)
+					}
+				}
+			}
+			if resp.NextPageToken == nil || *resp.NextPageToken == "" {
+				break
+			}
+			params.NextPageToken = resp.NextPageToken
+		}
+	}
+	return series, nil
+}
+
+// reservationUtilization
+// returns the daily share of the reserved hours used, with a query by reservation for every
+// service as reservation utilization is only grouped by SUBSCRIPTION_ID. The used and the
+// reserved hours of the reservations of an account add up to its percentage.
+func (m costInsightsAwsServer) reservationUtilization(ctx context.Context, period *ceTypes.DateInterval, linkedAccounts []string, services []string) (commitmentSeries, error) {
+	series := commitmentSeries{}
+	for _, service := range orAll(services) {
+		params := &costexplorer.GetReservationUtilizationInput{
+			TimePeriod:  period,
+			Filter:      commitmentFilter(linkedAccounts, []string{service}),
+			Granularity: ceTypes.GranularityDaily,
+		}
+		if len(linkedAccounts) > 0 {
+			params.GroupBy = commitmentGroupBy(ceTypes.DimensionSubscriptionId)
+		}
+		for {
+			resp, err := m.client.GetReservationUtilization(ctx, params)
+			if err != nil {
+				return nil, err
+			}
+			for _, utilization := range resp.UtilizationsByTime {
+				if params.GroupBy == nil {
+					if utilization.Total == nil {
+						continue
+					}
+					if day, ok := commitmentDay(utilization.TimePeriod, utilization.Total.UtilizationPercentage); ok {
+						series.add("", service, day)
+					}
+					continue
+				}
+				for _, account := range orAll(linkedAccounts) {
+					if day, ok := reservationUtilizationDay(utilization, account); ok {
+						series.add(account, service, day)
+					}
+				}
+			}
+			if resp.NextPageToken == nil || *resp.NextPageToken == "" {
+				break
+			}
+			params.NextPageToken = resp.NextPageToken
+		}
+	}
+	return series, nil
+}
+
+// reservationUtilizationDay
+// returns the percentage of the reserved hours of the account used in a day, false when the
+// account has no reserved hours
+func reservationUtilizationDay(utilization ceTypes.UtilizationByTime, account string) (*pb.DateAggregation, bool) {
+	var used, reserved float64
+	for _, group := range utilization.Groups {
+		if group.Utilization == nil || commitmentAttribute(group.Attributes, "accountId", string(ceTypes.DimensionLinkedAccount)) != account {
+			continue
+		}
+		used += commitmentHours(group.Utilization.TotalActualHours)
+		reserved += commitmentHours(group.Utilization.PurchasedHours)
+	}
+	if reserved == 0 {
+		return nil, false
+	}
+	percent := strconv.FormatFloat(100*used/reserved, 'f', -1, 64)
+	return commitmentDay(utilization.TimePeriod, &percent)
+}
+
+// commitmentHours returns the hours of a Cost Explorer amount, zero when it is missing
+func commitmentHours(hours *string) float64 {
+	if hours == nil {
+		return 0
+	}
+	amount, err := strconv.ParseFloat(*hours, 64)
+	if err != nil {
+		return 0
+	}
+	return amount
+}
@@ -0,0 +1,766 @@
+package svc
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	ceTypes "github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/spf13/viper"
+
+	"github.com/seizadi/cost-insights-backend/pkg/pb"
+	"github.com/seizadi/cost-insights-backend/pkg/types"
+)
+
+// The fixtures in testdata/fixtures are recorded from fakeCeClient, run the tests with -record
+// to record them again after a query changes and with -update to accept new golden responses.
+var (
+	recordFixtures = flag.Bool("record", false, "record cost explorer fixtures from the fake client")
+	updateGolden   = flag.Bool("update", false, "update the golden responses")
+)
+
+const (
+	testFixturesDir = "testdata/fixtures"
+	testGoldenDir   = "testdata/golden"
+)
+
+var fakeCeGroupKeys = map[string][]string{
+	"SERVICE":        {"Amazon Elastic Compute Cloud - Compute", "Amazon Simple Storage Service", "AWS Lambda"},
+	"LINKED_ACCOUNT": {"111111111111", "222222222222"},
+	"Product":        {"Product$", "Product$service-a", "Product$service-b"},
+	"INSTANCE_TYPE":  {"m5.large", "t3.medium"},
+	"RESOURCE_ID":    {"i-0a1b2c3d", "i-0e5f6a7b"},
+}
+
+// fakeCeClient
+// returns deterministic daily costs for any query, the amount of a day only depends on the
+// date and the group so overlapping queries agree with each other.
+type fakeCeClient struct{}
+
+// fakeCeSpike is a cost anomaly of the first group, after the days of the other fixtures
+var fakeCeSpike = map[string]float64{"2021-10-10": 400, "2021-10-11": 250}
+
+func fakeCeAmount(date time.Time, group int) float64 {
+	amount := float64(100*(group+1)) + float64(date.YearDay()%7)*float64(3+group) + float64(date.Month())
+	if group == 0 {
+		amount += fakeCeSpike[date.Format(types.DEFAULT_DATE_FORMAT)]
+	}
+	return amount
+}
+
+func (fakeCeClient) GetCostAndUsage(ctx context.Context, params *costexplorer.GetCostAndUsageInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostAndUsageOutput, error) {
+	start, err := time.Parse(types.DEFAULT_DATE_FORMAT, *params.TimePeriod.Start)
+	if err != nil {
+		return nil, err
+	}
+	end, err := time.Parse(types.DEFAULT_DATE_FORMAT, *params.TimePeriod.End)
+	if err != nil {
+		return nil, err
+	}
+
+	// The groups of two group keys are every pair of their keys
+	var keys [][]string
+	services := fakeCeFilterValues(params.Filter, ceTypes.DimensionService, fakeCeGroupKeys["SERVICE"])
+	serviceKey := -1
+	for i, groupBy := range params.GroupBy {
+		if *groupBy.Key == "SERVICE" {
+			serviceKey = i
+		}
+		values := fakeCeGroupKeys[*groupBy.Key]
+		if *groupBy.Key == "LINKED_ACCOUNT" {
+			values = fakeCeFilterValues(params.Filter, ceTypes.DimensionLinkedAccount, values)
+		}
+		if len(keys) == 0 {
+			for _, value := range values {
+				keys = append(keys, []string{value})
+			}
+			continue
+		}
+		pairs := [][]string{}
+		for _, key := range keys {
+			for _, value := range values {
+				pairs = append(pairs, append(append([]string{}, key...), value))
+			}
+		}
+		keys = pairs
+	}
+	unit := "USD"
+	metricValue := func(amount float64) map[string]ceTypes.MetricValue {
+		value := map[string]ceTypes.MetricValue{}
+		for _, metric := range params.Metrics {
+			a := fmt.Sprintf("%.4f", amount)
+			value[metric] = ceTypes.MetricValue{Amount: &a, Unit: &unit}
+		}
+		return value
+	}
+
+	resp := &costexplorer.GetCostAndUsageOutput{}
+	for date := start; date.Before(end); date = date.AddDate(0, 0, 1) {
+		dayStart := date.Format(types.DEFAULT_DATE_FORMAT)
+		dayEnd := date.AddDate(0, 0, 1).Format(types.DEFAULT_DATE_FORMAT)
+		result := ceTypes.ResultByTime{
+			TimePeriod: &ceTypes.DateInterval{Start: &dayStart, End: &dayEnd},
+			Estimated:  false,
+		}
+		if len(keys) == 0 {
+			var total float64
+			for group, service := range fakeCeGroupKeys["SERVICE"] {
+				if containsString(services, service) {
+					total += fakeCeAmount(date, group)
+				}
+			}
+			result.Total = metricValue(total)
+		} else {
+			result.Total = map[string]ceTypes.MetricValue{}
+			// Filtered services keep the amounts of their group so filtered queries agree
+			for group, key := range keys {
+				if serviceKey >= 0 && !containsString(services, key[serviceKey]) {
+					continue
+				}
+				result.Groups = append(result.Groups, ceTypes.Group{
+					Keys:    key,
+					Metrics: metricValue(fakeCeAmount(date, group)),
+				})
+			}
+		}
+		resp.ResultsByTime = append(resp.ResultsByTime, result)
+	}
+	return resp, nil
+}
+
+// GetCostForecast
+// forecasts the fake daily costs of every month in the period, with a band of 10% around them
+func (fakeCeClient) GetCostForecast(ctx context.Context, params *costexplorer.GetCostForecastInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostForecastOutput, error) {
+	start, err := time.Parse(types.DEFAULT_DATE_FORMAT, *params.TimePeriod.Start)
+	if err != nil {
+		return nil, err
+	}
+	end, err := time.Parse(types.DEFAULT_DATE_FORMAT, *params.TimePeriod.End)
+	if err != nil {
+		return nil, err
+	}
+	format := func(amount float64) *string {
+		a := fmt.Sprintf("%.4f", amount)
+		return &a
+	}
+
+	resp := &costexplorer.GetCostForecastOutput{}
+	var total float64
+	for month := start; month.Before(end); {
+		next := time.Date(month.Year(), month.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		if next.After(end) {
+			next = end
+		}
+		var amount float64
+		for date := month; date.Before(next); date = date.AddDate(0, 0, 1) {
+			for group := range fakeCeGroupKeys["SERVICE"] {
+				amount += fakeCeAmount(date, group)
+			}
+		}
+		monthStart := month.Format(types.DEFAULT_DATE_FORMAT)
+		monthEnd := next.Format(types.DEFAULT_DATE_FORMAT)
+		resp.ForecastResultsByTime = append(resp.ForecastResultsByTime, ceTypes.ForecastResult{
+			TimePeriod:                   &ceTypes.DateInterval{Start: &monthStart, End: &monthEnd},
+			MeanValue:                    format(amount),
+			PredictionIntervalLowerBound: format(amount * 0.9),
+			PredictionIntervalUpperBound: format(amount * 1.1),
+		})
+		total += amount
+		month = next
+	}
+	unit := "USD"
+	resp.Total = &ceTypes.MetricValue{Amount: format(total), Unit: &unit}
+	return resp, nil
+}
+
+// fakeCeSavingsRatios are the savings of the fake recommendations by the term of the purchase
+var fakeCeSavingsRatios = map[ceTypes.TermInYears]float64{
+	ceTypes.TermInYearsOneYear:    0.2,
+	ceTypes.TermInYearsThreeYears: 0.4,
+}
+
+// GetSavingsPlansPurchaseRecommendation
+// recommends Compute and EC2 Instance Savings Plans for every filtered account, the hourly
+// on-demand spend grows with the account
+func (fakeCeClient) GetSavingsPlansPurchaseRecommendation(ctx context.Context, params *costexplorer.GetSavingsPlansPurchaseRecommendationInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetSavingsPlansPurchaseRecommendationOutput, error) {
+	format := func(amount float64) *string {
+		a := fmt.Sprintf("%.4f", amount)
+		return &a
+	}
+	recommendation := &ceTypes.SavingsPlansPurchaseRecommendation{
+		SavingsPlansType:     params.SavingsPlansType,
+		TermInYears:          params.TermInYears,
+		PaymentOption:        params.PaymentOption,
+		LookbackPeriodInDays: params.LookbackPeriodInDays,
+		AccountScope:         params.AccountScope,
+	}
+	ratio := fakeCeSavingsRatios[params.TermInYears]
+	if params.SavingsPlansType == ceTypes.SupportedSavingsPlansTypeEc2InstanceSp {
+		ratio += 0.1
+	}
+	if params.SavingsPlansType == ceTypes.SupportedSavingsPlansTypeSagemakerSp {
+		return &costexplorer.GetSavingsPlansPurchaseRecommendationOutput{SavingsPlansPurchaseRecommendation: recommendation}, nil
+	}
+	var onDemand, savings float64
+	for i, account := range fakeCeFilterValues(params.Filter, ceTypes.DimensionLinkedAccount, fakeCeGroupKeys["LINKED_ACCOUNT"]) {
+		account := account
+		hourly := 2.5 * float64(i+1)
+		monthlySavings := hourly * hoursPerMonth * ratio
+		recommendation.SavingsPlansPurchaseRecommendationDetails = append(recommendation.SavingsPlansPurchaseRecommendationDetails, ceTypes.SavingsPlansPurchaseRecommendationDetail{
+			AccountId:                         &account,
+			CurrentAverageHourlyOnDemandSpend: format(hourly),
+			HourlyCommitmentToPurchase:        format(hourly * (1 - ratio)),
+			EstimatedMonthlySavingsAmount:     format(monthlySavings),
+			EstimatedSavingsPercentage:        format(ratio * 100),
+		})
+		onDemand += hourly * hoursPerMonth
+		savings += monthlySavings
+	}
+	recommendation.SavingsPlansPurchaseRecommendationSummary = &ceTypes.SavingsPlansPurchaseRecommendationSummary{
+		CurrentOnDemandSpend:          format(onDemand),
+		EstimatedMonthlySavingsAmount: format(savings),
+		EstimatedSavingsPercentage:    format(ratio * 100),
+	}
+	return &costexplorer.GetSavingsPlansPurchaseRecommendationOutput{SavingsPlansPurchaseRecommendation: recommendation}, nil
+}
+
+// GetReservationPurchaseRecommendation
+// recommends m5.large EC2 reservations for every account, and no reservations of other services
+func (fakeCeClient) GetReservationPurchaseRecommendation(ctx context.Context, params *costexplorer.GetReservationPurchaseRecommendationInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetReservationPurchaseRecommendationOutput, error) {
+	resp := &costexplorer.GetReservationPurchaseRecommendationOutput{}
+	if *params.Service != AWS_SERVICE["EC2"] {
+		return resp, nil
+	}
+	format := func(amount float64) *string {
+		a := fmt.Sprintf("%.4f", amount)
+		return &a
+	}
+	recommendation := ceTypes.ReservationPurchaseRecommendation{
+		TermInYears:          params.TermInYears,
+		PaymentOption:        params.PaymentOption,
+		LookbackPeriodInDays: params.LookbackPeriodInDays,
+		AccountScope:         params.AccountScope,
+	}
+	ratio := fakeCeSavingsRatios[params.TermInYears] + 0.2
+	instanceType, family, region := "m5.large", "m5", "us-east-1"
+	var savings float64
+	for i, account := range fakeCeGroupKeys["LINKED_ACCOUNT"] {
+		account := account
+		instances := 4 * (i + 1)
+		onDemand := 70.08 * float64(instances)
+		recommendation.RecommendationDetails = append(recommendation.RecommendationDetails, ceTypes.ReservationPurchaseRecommendationDetail{
+			AccountId: &account,
+			InstanceDetails: &ceTypes.InstanceDetails{EC2InstanceDetails: &ceTypes.EC2InstanceDetails{
+				InstanceType: &instanceType,
+				Family:       &family,
+				Region:       &region,
+			}},
+			RecommendedNumberOfInstancesToPurchase: format(float64(instances)),
+			EstimatedMonthlyOnDemandCost:           format(onDemand),
+			EstimatedMonthlySavingsAmount:          format(onDemand * ratio),
+			EstimatedMonthlySavingsPercentage:      format(ratio * 100),
+		})
+		savings += onDemand * ratio
+	}
+	recommendation.RecommendationSummary = &ceTypes.ReservationPurchaseRecommendationSummary{
+		TotalEstimatedMonthlySavingsAmount: format(savings),
+	}
+	resp.Recommendations = []ceTypes.ReservationPurchaseRecommendation{recommendation}
+	return resp, nil
+}
+
+// GetRightsizingRecommendation
+// recommends to halve an m5.2xlarge instance of every filtered account, to a c5.xlarge across
+// instance families, and to terminate an idle t3.large instance of the second account
+func (fakeCeClient) GetRightsizingRecommendation(ctx context.Context, params *costexplorer.GetRightsizingRecommendationInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetRightsizingRecommendationOutput, error) {
+	format := func(amount float64) *string {
+		a := fmt.Sprintf("%.4f", amount)
+		return &a
+	}
+	instance := func(id string, instanceType string, monthlyCost float64) *ceTypes.CurrentInstance {
+		return &ceTypes.CurrentInstance{
+			ResourceId:      &id,
+			MonthlyCost:     format(monthlyCost),
+			ResourceDetails: &ceTypes.ResourceDetails{EC2ResourceDetails: &ceTypes.EC2ResourceDetails{InstanceType: &instanceType}},
+		}
+	}
+	targetType, targetCost := "m5.xlarge", 140.16
+	if params.Configuration != nil && params.Configuration.RecommendationTarget == ceTypes.RecommendationTargetCrossInstanceFamily {
+		targetType, targetCost = "c5.xlarge", 124.1
+	}
+	resp := &costexplorer.GetRightsizingRecommendationOutput{}
+	for i, account := range fakeCeFilterValues(params.Filter, ceTypes.DimensionLinkedAccount, fakeCeGroupKeys["LINKED_ACCOUNT"]) {
+		account := account
+		resp.RightsizingRecommendations = append(resp.RightsizingRecommendations, ceTypes.RightsizingRecommendation{
+			AccountId:       &account,
+			CurrentInstance: instance(fmt.Sprintf("i-%s01", account[:8]), "m5.2xlarge", 280.32),
+			RightsizingType: ceTypes.RightsizingTypeModify,
+			ModifyRecommendationDetail: &ceTypes.ModifyRecommendationDetail{TargetInstances: []ceTypes.TargetInstance{{
+				DefaultTargetInstance:   true,
+				EstimatedMonthlyCost:    format(targetCost),
+				EstimatedMonthlySavings: format(280.32 - targetCost),
+				ResourceDetails:         &ceTypes.ResourceDetails{EC2ResourceDetails: &ceTypes.EC2ResourceDetails{InstanceType: &targetType}},
+			}}},
+		})
+		if i == 1 {
+			resp.RightsizingRecommendations = append(resp.RightsizingRecommendations, ceTypes.RightsizingRecommendation{
+				AccountId:                     &account,
+				CurrentInstance:               instance(fmt.Sprintf("i-%s02", account[:8]), "t3.large", 60.74),
+				RightsizingType:               ceTypes.RightsizingTypeTerminate,
+				TerminateRecommendationDetail: &ceTypes.TerminateRecommendationDetail{EstimatedMonthlySavings: format(60.74)},
+			})
+		}
+	}
+	return resp, nil
+}
+
+// GetCostAndUsageWithResources
+// returns the fake costs of GetCostAndUsage, grouped by resource like by any other key. Like
+// Cost Explorer it only serves the last 14 days before testNow.
+func (fakeCeClient) GetCostAndUsageWithResources(ctx context.Context, params *costexplorer.GetCostAndUsageWithResourcesInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostAndUsageWithResourcesOutput, error) {
+	if earliest := testNow().AddDate(0, 0, -14).Format(types.DEFAULT_DATE_FORMAT); *params.TimePeriod.Start < earliest {
+		return nil, fmt.Errorf("ValidationException: start date %s is earlier than %s, the costs by resource are only available for the last 14 days", *params.TimePeriod.Start, earliest)
+	}
+	resp, err := fakeCeClient{}.GetCostAndUsage(ctx, &costexplorer.GetCostAndUsageInput{
+		TimePeriod:    params.TimePeriod,
+		Granularity:   params.Granularity,
+		Filter:        params.Filter,
+		Metrics:       params.Metrics,
+		GroupBy:       params.GroupBy,
+		NextPageToken: params.NextPageToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &costexplorer.GetCostAndUsageWithResourcesOutput{ResultsByTime: resp.ResultsByTime, NextPageToken: resp.NextPageToken}, nil
+}
+
+// GetDimensionValues
+// returns the services or the linked accounts of the fake costs, the second account also uses
+// Amazon Simple Email Service which has no AWS_SERVICE name
+func (fakeCeClient) GetDimensionValues(ctx context.Context, params *costexplorer.GetDimensionValuesInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetDimensionValuesOutput, error) {
+	var values []string
+	switch params.Dimension {
+	case ceTypes.DimensionLinkedAccount:
+		values = fakeCeFilterValues(params.Filter, ceTypes.DimensionLinkedAccount, fakeCeGroupKeys["LINKED_ACCOUNT"])
+	case ceTypes.DimensionService:
+		values = append([]string{}, fakeCeGroupKeys["SERVICE"]...)
+		for _, account := range fakeCeFilterValues(params.Filter, ceTypes.DimensionLinkedAccount, fakeCeGroupKeys["LINKED_ACCOUNT"]) {
+			if account == "222222222222" {
+				values = append(values, "Amazon Simple Email Service")
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unexpected dimension %s", params.Dimension)
+	}
+	resp := &costexplorer.GetDimensionValuesOutput{}
+	for _, value := range values {
+		value := value
+		resp.DimensionValues = append(resp.DimensionValues, ceTypes.DimensionValuesWithAttributes{Value: &value})
+	}
+	return resp, nil
+}
+
+// fakeCePercent
+// returns the fake commitment percentage of a day averaged over the filtered accounts and the
+// filtered services of the commitment, false when the commitment covers none of them
+func fakeCePercent(filter *ceTypes.Expression, services []string, base float64, date time.Time) (*string, bool) {
+	var sum float64
+	var count int
+	for a, account := range fakeCeGroupKeys["LINKED_ACCOUNT"] {
+		if !containsString(fakeCeFilterValues(filter, ceTypes.DimensionLinkedAccount, fakeCeGroupKeys["LINKED_ACCOUNT"]), account) {
+			continue
+		}
+		for s, service := range services {
+			if !containsString(fakeCeFilterValues(filter, ceTypes.DimensionService, services), service) {
+				continue
+			}
+			sum += base + float64(10*a+5*s+date.YearDay()%5)
+			count++
+		}
+	}
+	if count == 0 {
+		return nil, false
+	}
+	percent := fmt.Sprintf("%.2f", sum/float64(count))
+	return &percent, true
+}
+
+// fakeCeDays calls day with every day of the period and its date interval
+func fakeCeDays(period *ceTypes.DateInterval, day func(date time.Time, interval *ceTypes.DateInterval)) error {
+	start, err := time.Parse(types.DEFAULT_DATE_FORMAT, *period.Start)
+	if err != nil {
+		return err
+	}
+	end, err := time.Parse(types.DEFAULT_DATE_FORMAT, *period.End)
+	if err != nil {
+		return err
+	}
+	for date := start; date.Before(end); date = date.AddDate(0, 0, 1) {
+		dayStart := date.Format(types.DEFAULT_DATE_FORMAT)
+		dayEnd := date.AddDate(0, 0, 1).Format(types.DEFAULT_DATE_FORMAT)
+		day(date, &ceTypes.DateInterval{Start: &dayStart, End: &dayEnd})
+	}
+	return nil
+}
+
+// GetSavingsPlansCoverage
+// covers the EC2 and Lambda usage of every account by Savings Plans, by service with a group
+func (fakeCeClient) GetSavingsPlansCoverage(ctx context.Context, params *costexplorer.GetSavingsPlansCoverageInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetSavingsPlansCoverageOutput, error) {
+	resp := &costexplorer.GetSavingsPlansCoverageOutput{}
+	services := []string{AWS_SERVICE["EC2"], AWS_SERVICE["Lambda"]}
+	err := fakeCeDays(params.TimePeriod, func(date time.Time, interval *ceTypes.DateInterval) {
+		if len(params.GroupBy) == 0 {
+			if percent, ok := fakeCePercent(params.Filter, services, 50, date); ok {
+				resp.SavingsPlansCoverages = append(resp.SavingsPlansCoverages, ceTypes.SavingsPlansCoverage{
+					TimePeriod: interval,
+					Coverage:   &ceTypes.SavingsPlansCoverageData{CoveragePercentage: percent},
+				})
+			}
+			return
+		}
+		for _, service := range fakeCeFilterValues(params.Filter, ceTypes.DimensionService, services) {
+			if percent, ok := fakeCePercent(fakeCeAnd(params.Filter, ceTypes.DimensionService, service), services, 50, date); ok {
+				resp.SavingsPlansCoverages = append(resp.SavingsPlansCoverages, ceTypes.SavingsPlansCoverage{
+					Attributes: map[string]string{"SERVICE": service},
+					TimePeriod: interval,
+					Coverage:   &ceTypes.SavingsPlansCoverageData{CoveragePercentage: percent},
+				})
+			}
+		}
+	})
+	return resp, err
+}
+
+// GetSavingsPlansUtilization
+// uses most of the Savings Plans of every account
+func (fakeCeClient) GetSavingsPlansUtilization(ctx context.Context, params *costexplorer.GetSavingsPlansUtilizationInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetSavingsPlansUtilizationOutput, error) {
+	resp := &costexplorer.GetSavingsPlansUtilizationOutput{}
+	err := fakeCeDays(params.TimePeriod, func(date time.Time, interval *ceTypes.DateInterval) {
+		if percent, ok := fakeCePercent(params.Filter, []string{""}, 80, date); ok {
+			resp.SavingsPlansUtilizationsByTime = append(resp.SavingsPlansUtilizationsByTime, ceTypes.SavingsPlansUtilizationByTime{
+				TimePeriod:  interval,
+				Utilization: &ceTypes.SavingsPlansUtilization{UtilizationPercentage: percent},
+			})
+		}
+	})
+	return resp, err
+}
+
+// GetReservationCoverage
+// covers the EC2 and RDS usage of every account by reservations, by account with a group
+func (fakeCeClient) GetReservationCoverage(ctx context.Context, params *costexplorer.GetReservationCoverageInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetReservationCoverageOutput, error) {
+	resp := &costexplorer.GetReservationCoverageOutput{}
+	services := []string{AWS_SERVICE["EC2"], AWS_SERVICE["RDS"]}
+	err := fakeCeDays(params.TimePeriod, func(date time.Time, interval *ceTypes.DateInterval) {
+		coverage := ceTypes.CoverageByTime{TimePeriod: interval}
+		if percent, ok := fakeCePercent(params.Filter, services, 20, date); ok {
+			coverage.Total = &ceTypes.Coverage{CoverageHours: &ceTypes.CoverageHours{CoverageHoursPercentage: percent}}
+		}
+		if len(params.GroupBy) > 0 {
+			for _, account := range fakeCeFilterValues(params.Filter, ceTypes.DimensionLinkedAccount, fakeCeGroupKeys["LINKED_ACCOUNT"]) {
+				if percent, ok := fakeCePercent(fakeCeAnd(params.Filter, ceTypes.DimensionLinkedAccount, account), services, 20, date); ok {
+					coverage.Groups = append(coverage.Groups, ceTypes.ReservationCoverageGroup{
+						Attributes: map[string]string{"linkedAccount": account},
+						Coverage:   &ceTypes.Coverage{CoverageHours: &ceTypes.CoverageHours{CoverageHoursPercentage: percent}},
+					})
+				}
+			}
+		}
+		if coverage.Total != nil {
+			resp.CoveragesByTime = append(resp.CoveragesByTime, coverage)
+		}
+	})
+	return resp, err
+}
+
+// GetReservationUtilization
+// uses most of the EC2 and RDS reservations of every account, with a group two reservations of
+// every account reserving 100 and 300 hours
+func (fakeCeClient) GetReservationUtilization(ctx context.Context, params *costexplorer.GetReservationUtilizationInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetReservationUtilizationOutput, error) {
+	resp := &costexplorer.GetReservationUtilizationOutput{}
+	services := []string{AWS_SERVICE["EC2"], AWS_SERVICE["RDS"]}
+	err := fakeCeDays(params.TimePeriod, func(date time.Time, interval *ceTypes.DateInterval) {
+		utilization := ceTypes.UtilizationByTime{TimePeriod: interval}
+		if percent, ok := fakeCePercent(params.Filter, services, 70, date); ok {
+			utilization.Total = &ceTypes.ReservationAggregates{UtilizationPercentage: percent}
+		}
+		if len(params.GroupBy) > 0 {
+			for _, account := range fakeCeFilterValues(params.Filter, ceTypes.DimensionLinkedAccount, fakeCeGroupKeys["LINKED_ACCOUNT"]) {
+				percent, ok := fakeCePercent(fakeCeAnd(params.Filter, ceTypes.DimensionLinkedAccount, account), services, 70, date)
+				if !ok {
+					continue
+				}
+				for _, reserved := range []float64{100, 300} {
+					used, _ := strconv.ParseFloat(*percent, 64)
+					usedHours := fmt.Sprintf("%.2f", used*reserved/100)
+					reservedHours := fmt.Sprintf("%.0f", reserved)
+					utilization.Groups = append(utilization.Groups, ceTypes.ReservationUtilizationGroup{
+						Attributes:  map[string]string{"accountId": account},
+						Utilization: &ceTypes.ReservationAggregates{TotalActualHours: &usedHours, PurchasedHours: &reservedHours},
+					})
+				}
+			}
+		}
+		if utilization.Total != nil {
+			resp.UtilizationsByTime = append(resp.UtilizationsByTime, utilization)
+		}
+	})
+	return resp, err
+}
+
+// fakeCeAnd returns the filter and a filter on the value of the dimension
+func fakeCeAnd(filter *ceTypes.Expression, dimension ceTypes.Dimension, value string) *ceTypes.Expression {
+	expression := ceTypes.Expression{Dimensions: &ceTypes.DimensionValues{Key: dimension, Values: []string{value}}}
+	if filter == nil {
+		return &expression
+	}
+	return &ceTypes.Expression{And: []ceTypes.Expression{*filter, expression}}
+}
+
+// fakeCeFilterValues
+// returns the values of keys selected by the dimension filter, or of an And of filters, or all
+// keys without a filter on the dimension
+func fakeCeFilterValues(filter *ceTypes.Expression, dimension ceTypes.Dimension, keys []string) []string {
+	if filter == nil {
+		return keys
+	}
+	for i := range filter.And {
+		keys = fakeCeFilterValues(&filter.And[i], dimension, keys)
+	}
+	if filter.Dimensions == nil || filter.Dimensions.Key != dimension {
+		return keys
+	}
+	return filter.Dimensions.Values
+}
+
+// testGroupDirectory
+// maps the test user to a group owning one of the fake accounts
+var testGroupDirectory = &GroupDirectory{groups: []GroupMapping{
+	{
+		Id:       "platform",
+		Users:    []string{"user"},
+		Accounts: []GroupAccount{{Id: "111111111111", Name: "platform-prod"}},
+	},
+	{
+		Id:    "data",
+		Users: []string{"analyst"},
+		Accounts: []GroupAccount{
+			{Id: "data-lake", Name: "Data Lake", LinkedAccounts: []string{"222222222222", "333333333333"}},
+		},
+	},
+	{
+		Id:       "finance",
+		Users:    []string{"controller"},
+		Accounts: []GroupAccount{{Id: AllAccounts}},
+	},
+	{
+		Id:    "audit",
+		Users: []string{"auditor"},
+	},
+}}
+
+// capturingCeClient
+// saves the inputs of every cost and usage query and answers them with the fake client
+type capturingCeClient struct {
+	fakeCeClient
+	inputs []*costexplorer.GetCostAndUsageInput
+}
+
+func (c *capturingCeClient) GetCostAndUsage(ctx context.Context, params *costexplorer.GetCostAndUsageInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostAndUsageOutput, error) {
+	c.inputs = append(c.inputs, params)
+	return fakeCeClient{}.GetCostAndUsage(ctx, params, optFns...)
+}
+
+func setTestCostConfig() {
+	viper.Set("cost.round", true)
+	viper.Set("support.cost", false)
+	viper.Set("cost.aws.datasets", string(ceTypes.MetricNetAmortizedCost))
+	viper.Set("cost.forecast.history_days", 56)
+	viper.Set("cost.forecast.confidence", 0.8)
+	viper.Set("cost.forecast.cross_check", true)
+	viper.Set("cost.commitment.services", []string{"EC2", "Lambda", "RDS"})
+	viper.Set("cost.insights.group_by", "tag:Product")
+	viper.Set("cost.insights.products", map[string]interface{}{
+		"s3": map[string]interface{}{"group_by": "INSTANCE_TYPE", "sub_group_by": "RESOURCE_ID", "sub_entities": "deployment"},
+	})
+	viper.Set("cost.alerts.anomaly.history_days", 56)
+	viper.Set("cost.alerts.anomaly.recent_days", 7)
+	viper.Set("cost.alerts.anomaly.threshold", 3)
+	viper.Set("cost.alerts.anomaly.min_excess", 10)
+	viper.Set("cost.alerts.budget.warning", 0.8)
+	viper.Set("cost.alerts.budget.projected", 1.0)
+	viper.Set("cost.alerts.budget.breach", 1.0)
+	viper.Set("cost.alerts.snooze", "P7D")
+	viper.Set("cost.alerts.dismiss", "P90D")
+	viper.Set("cost.products.lookback", "P3M")
+	viper.Set("cost.alerts.hide_inactive", false)
+	viper.Set("cost.alerts.savings.term", string(ceTypes.TermInYearsOneYear))
+	viper.Set("cost.alerts.savings.payment_option", string(ceTypes.PaymentOptionNoUpfront))
+	viper.Set("cost.alerts.savings.plans", []string{"COMPUTE_SP", "EC2_INSTANCE_SP"})
+	viper.Set("cost.alerts.savings.reservations", []string{"EC2", "RDS"})
+	viper.Set("cost.alerts.rightsizing.target", string(ceTypes.RecommendationTargetSameInstanceFamily))
+	viper.Set("cost.alerts.rightsizing.benefits_considered", true)
+}
+
+// testNow is the time of the test server, the last complete billing date is the day before
+func testNow() time.Time {
+	return time.Date(2021, 10, 15, 12, 0, 0, 0, time.UTC)
+}
+
+// testAlertRules
+// are the cost anomalies, the monthly growth of EC2 with the spike, a weekly Lambda spend over
+// its threshold, the cost without a Product tag, a month projected over budget, a month over
+// budget, the recommended Savings Plans and EC2 reservations, the EC2 instances to rightsize and
+// a rule of another group which is not evaluated for the platform group
+var testAlertRules = []AlertRule{
+	{Name: "cost-anomaly", Type: AnomalyRule, Window: "P7D", Threshold: 3, Severity: WarningSeverity},
+	{Name: "ec2-monthly-growth", Type: GrowthRule, Service: "EC2", Window: "P1M", Threshold: 0.1, Severity: InfoSeverity},
+	{Name: "lambda-weekly-spend", Type: SpendRule, Service: "Lambda", Window: "P1W", Threshold: 500, Severity: CriticalSeverity},
+	{Name: "product-tag-coverage", Type: UnlabeledRule, TagKey: "Product", Window: "P1M", Threshold: 0.1, Severity: WarningSeverity},
+	{Name: "platform-budget", Type: BudgetRule, Window: "P1M", Threshold: 0.8, Budget: 20000, Severity: WarningSeverity},
+	{Name: "platform-hard-budget", Type: BudgetRule, Window: "P1M", Threshold: 0.8, Budget: 8000, Severity: WarningSeverity},
+	{Name: "commitment-savings", Type: SavingsRule, Window: "P30D", Threshold: 100, Severity: InfoSeverity},
+	{Name: "ec2-rightsizing", Type: RightsizingRule, Window: "P14D", Threshold: 100, Severity: InfoSeverity},
+	{Name: "data-lake-spend", Type: SpendRule, Group: "data", Project: "data-lake", Window: "P1W", Threshold: 1, Severity: CriticalSeverity},
+}
+
+func newTestAwsServer(t *testing.T) *costInsightsAwsServer {
+	if *recordFixtures {
+		return &costInsightsAwsServer{client: NewRecordingCeClient(fakeCeClient{}, testFixturesDir), groups: testGroupDirectory, rules: testAlertRules, statuses: newMemoryAlertStatusStore(), now: testNow}
+	}
+	return &costInsightsAwsServer{client: NewReplayCeClient(testFixturesDir), groups: testGroupDirectory, rules: testAlertRules, statuses: newMemoryAlertStatusStore(), now: testNow}
+}
+
+// checkGolden
+// compares the JSON payload of a response, as the Backstage plugin receives it, with the golden file
+func checkGolden(t *testing.T, name string, resp proto.Message) {
+	marshaler := jsonpb.Marshaler{Indent: "  "}
+	actual, err := marshaler.MarshalToString(resp)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	path := filepath.Join(testGoldenDir, name+".json")
+	if *updateGolden {
+		if err := os.MkdirAll(testGoldenDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(actual+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	expected, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(expected) != actual+"\n" {
+		t.Errorf("Response of %s does not match %s:\n%s", name, path, actual)
+	}
+}
+
+func TestCeClientRecordReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fixtures")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	start := "2021-08-01"
+	end := "2021-08-08"
+	groupKey := "SERVICE"
+	input := &costexplorer.GetCostAndUsageInput{
+		TimePeriod:  &ceTypes.DateInterval{Start: &start, End: &end},
+		Metrics:     []string{string(ceTypes.MetricNetAmortizedCost)},
+		Granularity: ceTypes.GranularityDaily,
+		GroupBy: []ceTypes.GroupDefinition{
+			{Key: &groupKey, Type: ceTypes.GroupDefinitionTypeDimension},
+		},
+	}
+
+	recorded, err := NewRecordingCeClient(fakeCeClient{}, dir).GetCostAndUsage(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	replayed, err := NewReplayCeClient(dir).GetCostAndUsage(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected, _ := json.Marshal(recorded.ResultsByTime)
+	actual, _ := json.Marshal(replayed.ResultsByTime)
+	if string(expected) != string(actual) {
+		t.Errorf("Replayed results %s not equal to recorded %s", actual, expected)
+	}
+
+	end = "2021-08-09"
+	if _, err := NewReplayCeClient(dir).GetCostAndUsage(context.Background(), input); err == nil {
+		t.Error("Expected error replaying a query that was not recorded")
+	}
+}
+
+func TestAwsServerReplay(t *testing.T) {
+	setTestCostConfig()
+	server := newTestAwsServer(t)
+	ctx := context.Background()
+
+	var tests = []struct {
+		name string
+		call func() (proto.Message, error)
+	}{
+		{"user_groups", func() (proto.Message, error) {
+			return server.GetUserGroups(ctx, &pb.UserGroupsRequest{UserId: "user"})
+		}},
+		{"group_projects", func() (proto.Message, error) {
+			return server.GetGroupProjects(ctx, &pb.GroupProjectsRequest{Group: "platform"})
+		}},
+		{"group_daily_cost", func() (proto.Message, error) {
+			return server.GetGroupDailyCost(ctx, &pb.GroupDailyCostRequest{Group: "platform", Intervals: "R2/P30D/2021-09-01"})
+		}},
+		{"project_daily_cost", func() (proto.Message, error) {
+			return server.GetProjectDailyCost(ctx, &pb.ProjectDailyCostRequest{Project: "111111111111", Intervals: "R2/P30D/2021-09-01"})
+		}},
+		{"product_insights", func() (proto.Message, error) {
+			return server.GetProductInsights(ctx, &pb.ProductInsightsRequest{Product: "EC2", Group: "platform", Intervals: "R2/P30D/2021-09-01"})
+		}},
+		{"product_insights_breakdown", func() (proto.Message, error) {
+			return server.GetProductInsights(ctx, &pb.ProductInsightsRequest{Product: "S3", Group: "platform", Intervals: "R2/P30D/2021-10-15"})
+		}},
+		{"alerts", func() (proto.Message, error) {
+			return server.GetAlerts(ctx, &pb.AlertRequest{Group: "platform"})
+		}},
+		{"cost_forecast", func() (proto.Message, error) {
+			return server.GetCostForecast(ctx, &pb.CostForecastRequest{Group: "platform", Horizon: "quarter", Date: "2021-08-15"})
+		}},
+		{"commitment_coverage", func() (proto.Message, error) {
+			return server.GetCommitmentCoverage(ctx, &pb.CommitmentRequest{Group: "platform", Intervals: "R2/P7D/2021-09-01"})
+		}},
+		{"commitment_utilization", func() (proto.Message, error) {
+			return server.GetCommitmentUtilization(ctx, &pb.CommitmentRequest{Group: "data", Project: "data-lake", Service: "RDS", Intervals: "R2/P7D/2021-09-01"})
+		}},
+		{"products", func() (proto.Message, error) {
+			return server.GetProducts(ctx, &pb.ProductsRequest{Group: "data", Project: "data-lake", Intervals: "R1/P30D/2021-09-01"})
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp, err := test.call()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			checkGolden(t, test.name, resp)
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package svc
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	ceTypes "github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+	"github.com/spf13/viper"
+
+	"github.com/seizadi/cost-insights-backend/pkg/accounts"
+	"github.com/seizadi/cost-insights-backend/pkg/pb"
+	"github.com/seizadi/cost-insights-backend/pkg/types"
+	"github.com/seizadi/cost-insights-backend/pkg/utils"
+)
+
+// rightsizingService is the only service Cost Explorer recommends rightsizing for
+const rightsizingService = "AmazonEC2"
+
+// rightsizingTargets are the instance families Cost Explorer may recommend resizing to
+var rightsizingTargets = map[ceTypes.RecommendationTarget]bool{
+	ceTypes.RecommendationTargetSameInstanceFamily:  true,
+	ceTypes.RecommendationTargetCrossInstanceFamily: true,
+}
+
+// rightsizingAlerts
+// returns a RightsizingAlert for every account in the scope of the rule whose EC2 instances
+// Cost Explorer recommends to modify or terminate when they save the account at least the
+// threshold of the rule a month. The services of an alert are its instances, the largest
+// savings first, and the alerts are sorted by their monthly savings, the largest first.
+func (m costInsightsAwsServer) rightsizingAlerts(ctx context.Context, date time.Time, group string, rule AlertRule) ([]*pb.Entity, error) {
+	linkedAccounts, err := m.ruleAccounts(group, rule)
+	if err != nil {
+		return nil, err
+	}
+	target := ceTypes.RecommendationTarget(viper.GetString("cost.alerts.rightsizing.target"))
+	if !rightsizingTargets[target] {
+		return nil, errors.New("unknown cost.alerts.rightsizing.target: " + string(target))
+	}
+	service := rightsizingService
+	params := &costexplorer.GetRightsizingRecommendationInput{
+		Service: &service,
+		Configuration: &ceTypes.RightsizingRecommendationConfiguration{
+			RecommendationTarget: target,
+			BenefitsConsidered:   viper.GetBool("cost.alerts.rightsizing.benefits_considered"),
+		},
+		Filter: linkedAccountFilter(linkedAccounts),
+	}
+
+	instances := map[string][]*pb.Entity{}
+	for {
+		resp, err := m.client.GetRightsizingRecommendation(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		for _, recommendation := range resp.RightsizingRecommendations {
+			instance := rightsizingInstance(recommendation)
+			if instance == nil {
+				continue
+			}
+			account := ""
+			if recommendation.AccountId != nil {
+				account = *recommendation.AccountId
+			}
+			instances[account] = append(instances[account], instance)
+		}
+		if resp.NextPageToken == nil || *resp.NextPageToken == "" {
+			break
+		}
+		params.NextPageToken = resp.NextPageToken
+	}
+
+	interval, err := utils.ParseIntervals(rule.intervals(1, date))
+	if err != nil {
+		return nil, err
+	}
+	alerts := []*pb.Entity{}
+	for account, accountInstances := range instances {
+		total := savingsAmounts{}
+		for _, instance := range accountInstances {
+			total.onDemand += instance.Aggregation[0]
+			total.savings += instance.Aggregation[0] - instance.Aggregation[1]
+		}
+		sort.SliceStable(accountInstances, func(i, j int) bool {
+			if accountInstances[i].Change.Amount != accountInstances[j].Change.Amount {
+				return accountInstances[i].Change.Amount < accountInstances[j].Change.Amount
+			}
+			return accountInstances[i].Id < accountInstances[j].Id
+		})
+		alert := &pb.Entity{
+			Type:        RightsizingAlert,
+			Id:          accounts.NameOf(ctx, m.accounts, account),
+			Project:     rule.scope(group),
+			StartDate:   interval.StartDate,
+			EndDate:     date.Format(types.DEFAULT_DATE_FORMAT),
+			Aggregation: total.aggregation(),
+			Change:      total.change(),
+			Services:    accountInstances,
+		}
+		alert.MonthlySavings = -alert.Change.Amount
+		if alert.MonthlySavings <= 0 || alert.MonthlySavings < rule.Threshold {
+			continue
+		}
+		alerts = append(alerts, alert)
+	}
+	sort.Slice(alerts, func(i, j int) bool {
+		if alerts[i].MonthlySavings != alerts[j].MonthlySavings {
+			return alerts[i].MonthlySavings > alerts[j].MonthlySavings
+		}
+		return alerts[i].Id < alerts[j].Id
+	})
+	return alerts, nil
+}
+
+// rightsizingInstance
+// returns the instance of a recommendation, its aggregation compares the current monthly cost
+// with the cost of the recommended instance type, nothing for a recommendation without savings
+func rightsizingInstance(recommendation ceTypes.RightsizingRecommendation) *pb.Entity {
+	if recommendation.CurrentInstance == nil {
+		return nil
+	}
+	current := recommendation.CurrentInstance
+	instance := &pb.Entity{Id: stringOf(current.ResourceId)}
+	if current.ResourceDetails != nil && current.ResourceDetails.EC2ResourceDetails != nil {
+		instance.InstanceType = stringOf(current.ResourceDetails.EC2ResourceDetails.InstanceType)
+	}
+	amounts := savingsAmounts{onDemand: recommendationAmount(current.MonthlyCost)}
+	switch recommendation.RightsizingType {
+	case ceTypes.RightsizingTypeModify:
+		if recommendation.ModifyRecommendationDetail == nil || len(recommendation.ModifyRecommendationDetail.TargetInstances) == 0 {
+			return nil
+		}
+		targets := recommendation.ModifyRecommendationDetail.TargetInstances
+		target := targets[0]
+		for _, t := range targets {
+			if t.DefaultTargetInstance {
+				target = t
+				break
+			}
+		}
+		if target.ResourceDetails != nil && target.ResourceDetails.EC2ResourceDetails != nil {
+			instance.RecommendedInstanceType = stringOf(target.ResourceDetails.EC2ResourceDetails.InstanceType)
+		}
+		amounts.savings = recommendationAmount(target.EstimatedMonthlySavings)
+	case ceTypes.RightsizingTypeTerminate:
+		if recommendation.TerminateRecommendationDetail == nil {
+			return nil
+		}
+		amounts.savings = recommendationAmount(recommendation.TerminateRecommendationDetail.EstimatedMonthlySavings)
+	default:
+		return nil
+	}
+	if amounts.savings <= 0 {
+		return nil
+	}
+	instance.Aggregation = amounts.aggregation()
+	instance.Change = amounts.change()
+	return instance
+}
+
+// stringOf returns the value of a Cost Explorer string, empty when it is missing
+func stringOf(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -0,0 +1,293 @@
+package svc
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	ceTypes "github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+	"github.com/spf13/viper"
+
+	"github.com/seizadi/cost-insights-backend/pkg/accounts"
+	"github.com/seizadi/cost-insights-backend/pkg/pb"
+	"github.com/seizadi/cost-insights-backend/pkg/types"
+	"github.com/seizadi/cost-insights-backend/pkg/utils"
+)
+
+// savingsLookbacks
+// are the Cost Explorer lookback periods of the recommendations by the window of a savings rule
+var savingsLookbacks = map[string]ceTypes.LookbackPeriodInDays{
+	"P7D":  ceTypes.LookbackPeriodInDaysSevenDays,
+	"P30D": ceTypes.LookbackPeriodInDaysThirtyDays,
+	"P60D": ceTypes.LookbackPeriodInDaysSixtyDays,
+}
+
+// savingsTerms are the ISO 8601 durations of the terms of the recommended purchases
+var savingsTerms = map[ceTypes.TermInYears]string{
+	ceTypes.TermInYearsOneYear:    "P1Y",
+	ceTypes.TermInYearsThreeYears: "P3Y",
+}
+
+// savingsPlansServices
+// are the Cost Explorer services whose usage the Savings Plans of a type apply to
+var savingsPlansServices = map[ceTypes.SupportedSavingsPlansType][]string{
+	ceTypes.SupportedSavingsPlansTypeComputeSp:     {AWS_SERVICE["EC2"], "Amazon Elastic Container Service", AWS_SERVICE["Lambda"]},
+	ceTypes.SupportedSavingsPlansTypeEc2InstanceSp: {AWS_SERVICE["EC2"]},
+	ceTypes.SupportedSavingsPlansTypeSagemakerSp:   {"Amazon SageMaker"},
+}
+
+// hoursPerMonth converts the hourly on-demand spend of a Savings Plans recommendation to a month
+const hoursPerMonth = 730
+
+// savingsAlerts
+// returns a SavingsPlansAlert for every Savings Plans type of cost.alerts.savings.plans and a
+// ReservedInstanceAlert for every service of cost.alerts.savings.reservations when Cost Explorer
+// recommends purchases for the accounts in the scope of the rule that save at least the
+// threshold of the rule a month. A rule with a service only alerts on the plans applying to
+// the service and on its reservations. The alerts are sorted by their monthly savings, the
+// largest first.
+func (m costInsightsAwsServer) savingsAlerts(ctx context.Context, date time.Time, group string, rule AlertRule) ([]*pb.Entity, error) {
+	linkedAccounts, err := m.ruleAccounts(group, rule)
+	if err != nil {
+		return nil, err
+	}
+	service := rule.Service
+	if name, ok := AWS_SERVICE[service]; ok {
+		service = name
+	}
+	term := ceTypes.TermInYears(viper.GetString("cost.alerts.savings.term"))
+	if _, ok := savingsTerms[term]; !ok {
+		return nil, errors.New("unknown cost.alerts.savings.term: " + string(term))
+	}
+	payment := ceTypes.PaymentOption(viper.GetString("cost.alerts.savings.payment_option"))
+	lookback := savingsLookbacks[rule.Window]
+
+	alerts := []*pb.Entity{}
+	for _, planType := range viper.GetStringSlice("cost.alerts.savings.plans") {
+		services, ok := savingsPlansServices[ceTypes.SupportedSavingsPlansType(planType)]
+		if !ok {
+			return nil, errors.New("unknown savings plans type in cost.alerts.savings.plans: " + planType)
+		}
+		if service != "" && !containsString(services, service) {
+			continue
+		}
+		alert, err := m.savingsPlansAlert(ctx, &costexplorer.GetSavingsPlansPurchaseRecommendationInput{
+			SavingsPlansType:     ceTypes.SupportedSavingsPlansType(planType),
+			TermInYears:          term,
+			PaymentOption:        payment,
+			LookbackPeriodInDays: lookback,
+			AccountScope:         ceTypes.AccountScopeLinked,
+			Filter:               linkedAccountFilter(linkedAccounts),
+		}, services)
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, alert)
+	}
+	for _, reserved := range viper.GetStringSlice("cost.alerts.savings.reservations") {
+		if name, ok := AWS_SERVICE[reserved]; ok {
+			reserved = name
+		}
+		if service != "" && reserved != service {
+			continue
+		}
+		alert, err := m.reservationAlert(ctx, &costexplorer.GetReservationPurchaseRecommendationInput{
+			Service:              &reserved,
+			TermInYears:          term,
+			PaymentOption:        payment,
+			LookbackPeriodInDays: lookback,
+			AccountScope:         ceTypes.AccountScopeLinked,
+		}, linkedAccounts)
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, alert)
+	}
+
+	interval, err := utils.ParseIntervals(rule.intervals(1, date))
+	if err != nil {
+		return nil, err
+	}
+	ruleAlerts := []*pb.Entity{}
+	for _, alert := range alerts {
+		if alert.MonthlySavings <= 0 || alert.MonthlySavings < rule.Threshold {
+			continue
+		}
+		alert.Project = rule.scope(group)
+		alert.StartDate = interval.StartDate
+		alert.EndDate = date.Format(types.DEFAULT_DATE_FORMAT)
+		alert.Term = savingsTerms[term]
+		ruleAlerts = append(ruleAlerts, alert)
+	}
+	sort.SliceStable(ruleAlerts, func(i, j int) bool {
+		return ruleAlerts[i].MonthlySavings > ruleAlerts[j].MonthlySavings
+	})
+	return ruleAlerts, nil
+}
+
+// ruleAccounts
+// returns the linked accounts in the scope of the rule, the accounts of its project or else of
+// the group, or none for all accounts
+func (m costInsightsAwsServer) ruleAccounts(group string, rule AlertRule) ([]string, error) {
+	if rule.Project != "" {
+		return m.groups.LinkedAccountsOf(rule.Project)
+	}
+	return m.groups.LinkedAccountsOfGroup(group)
+}
+
+// savingsPlansAlert
+// returns the SavingsPlansAlert of the recommended Savings Plans purchase of every page of the
+// query, the services are the services the plans apply to
+func (m costInsightsAwsServer) savingsPlansAlert(ctx context.Context, params *costexplorer.GetSavingsPlansPurchaseRecommendationInput, services []string) (*pb.Entity, error) {
+	projects := savingsProjects{}
+	for {
+		resp, err := m.client.GetSavingsPlansPurchaseRecommendation(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		if resp.SavingsPlansPurchaseRecommendation != nil {
+			for _, detail := range resp.SavingsPlansPurchaseRecommendation.SavingsPlansPurchaseRecommendationDetails {
+				project := projects.of(detail.AccountId)
+				project.onDemand += recommendationAmount(detail.CurrentAverageHourlyOnDemandSpend) * hoursPerMonth
+				project.savings += recommendationAmount(detail.EstimatedMonthlySavingsAmount)
+			}
+		}
+		if resp.NextPageToken == nil || *resp.NextPageToken == "" {
+			break
+		}
+		params.NextPageToken = resp.NextPageToken
+	}
+
+	alert := m.savingsAlert(ctx, SavingsPlansAlert, string(params.SavingsPlansType), projects)
+	for _, service := range services {
+		alert.Services = append(alert.Services, &pb.Entity{Id: service})
+	}
+	return alert, nil
+}
+
+// reservationAlert
+// returns the ReservedInstanceAlert of the recommended reservations of the service for the
+// linked accounts, or for all accounts without linked accounts. Cost Explorer does not filter
+// the recommendations by account, the recommendations of other accounts are left out.
+func (m costInsightsAwsServer) reservationAlert(ctx context.Context, params *costexplorer.GetReservationPurchaseRecommendationInput, linkedAccounts []string) (*pb.Entity, error) {
+	projects := savingsProjects{}
+	for {
+		resp, err := m.client.GetReservationPurchaseRecommendation(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		for _, recommendation := range resp.Recommendations {
+			for _, detail := range recommendation.RecommendationDetails {
+				if len(linkedAccounts) > 0 && (detail.AccountId == nil || !containsString(linkedAccounts, *detail.AccountId)) {
+					continue
+				}
+				project := projects.of(detail.AccountId)
+				project.onDemand += recommendationAmount(detail.EstimatedMonthlyOnDemandCost)
+				project.savings += recommendationAmount(detail.EstimatedMonthlySavingsAmount)
+			}
+		}
+		if resp.NextPageToken == nil || *resp.NextPageToken == "" {
+			break
+		}
+		params.NextPageToken = resp.NextPageToken
+	}
+
+	alert := m.savingsAlert(ctx, ReservedInstanceAlert, *params.Service, projects)
+	alert.Services = []*pb.Entity{
+		{
+			Id:          *params.Service,
+			Aggregation: alert.Aggregation,
+			Change:      alert.Change,
+		},
+	}
+	return alert, nil
+}
+
+// savingsAmounts is the monthly on-demand cost and the estimated monthly savings of an account
+type savingsAmounts struct {
+	onDemand float64
+	savings  float64
+}
+
+type savingsProjects map[string]*savingsAmounts
+
+func (p savingsProjects) of(accountId *string) *savingsAmounts {
+	account := ""
+	if accountId != nil {
+		account = *accountId
+	}
+	if _, ok := p[account]; !ok {
+		p[account] = &savingsAmounts{}
+	}
+	return p[account]
+}
+
+// savingsAlert
+// returns the alert of the monthly amounts of the accounts. The aggregation of the alert and
+// of its projects, the accounts with the largest savings first, compares the monthly on-demand
+// cost with the cost after the purchase.
+func (m costInsightsAwsServer) savingsAlert(ctx context.Context, alertType string, id string, projects savingsProjects) *pb.Entity {
+	alert := &pb.Entity{Type: alertType, Id: id}
+	total := savingsAmounts{}
+	for account, amounts := range projects {
+		if amounts.savings <= 0 {
+			continue
+		}
+		alert.Projects = append(alert.Projects, &pb.Entity{
+			Id:          accounts.NameOf(ctx, m.accounts, account),
+			Aggregation: amounts.aggregation(),
+			Change:      amounts.change(),
+		})
+		total.onDemand += amounts.onDemand
+		total.savings += amounts.savings
+	}
+	sort.Slice(alert.Projects, func(i, j int) bool {
+		if alert.Projects[i].Change.Amount != alert.Projects[j].Change.Amount {
+			return alert.Projects[i].Change.Amount < alert.Projects[j].Change.Amount
+		}
+		return alert.Projects[i].Id < alert.Projects[j].Id
+	})
+	alert.Aggregation = total.aggregation()
+	alert.Change = total.change()
+	alert.MonthlySavings = -alert.Change.Amount
+	return alert
+}
+
+func (a savingsAmounts) aggregation() []float64 {
+	onDemand, after := a.onDemand, a.onDemand-a.savings
+	if viper.GetBool("cost.round") {
+		onDemand, after = math.Round(onDemand), math.Round(after)
+	}
+	return []float64{onDemand, after}
+}
+
+func (a savingsAmounts) change() *pb.ChangeStatistic {
+	aggregation := a.aggregation()
+	change := &pb.ChangeStatistic{Amount: aggregation[1] - aggregation[0]}
+	if aggregation[0] > 0 {
+		change.Ratio = float32(change.Amount / aggregation[0])
+	}
+	return change
+}
+
+// recommendationAmount returns the amount of a recommendation, zero when it is missing
+func recommendationAmount(amount *string) float64 {
+	if amount == nil {
+		return 0
+	}
+	value, _ := strconv.ParseFloat(*amount, 64)
+	return value
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
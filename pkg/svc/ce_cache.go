@@ -0,0 +1,267 @@
+package svc
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spf13/viper"
+
+	"github.com/seizadi/cost-insights-backend/pkg/cache"
+	"github.com/seizadi/cost-insights-backend/pkg/types"
+)
+
+var ceCacheRequests = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "cost_insights_ce_cache_requests_total",
+		Help: "Cost Explorer queries served by the query cache, by operation and result (hit or miss).",
+	},
+	[]string{"operation", "result"},
+)
+
+func init() {
+	prometheus.MustRegister(ceCacheRequests)
+}
+
+// cachingCeClient
+// serves repeated Cost Explorer queries from a cache. Queries ending before the open billing
+// days are final and cached for closedTtl, queries over the last openDays days may still
+// change and are cached for openTtl.
+type cachingCeClient struct {
+	client    CostExplorerClient
+	cache     cache.Cache
+	openDays  int
+	openTtl   time.Duration
+	closedTtl time.Duration
+	now       func() time.Time
+}
+
+// NewCachingCeClient
+// returns a client caching the responses of client with the cost.cache.* ttl configuration
+func NewCachingCeClient(client CostExplorerClient, c cache.Cache) CostExplorerClient {
+	return &cachingCeClient{
+		client:    client,
+		cache:     c,
+		openDays:  viper.GetInt("cost.cache.open_days"),
+		openTtl:   viper.GetDuration("cost.cache.open_ttl"),
+		closedTtl: viper.GetDuration("cost.cache.closed_ttl"),
+		now:       time.Now,
+	}
+}
+
+// ceCacheKey
+// returns the cache key of an operation and its input, normalized by its JSON encoding
+func ceCacheKey(operation string, input interface{}) (string, error) {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return "", err
+	}
+	sum := sha1.Sum(append([]byte(operation), data...))
+	return "ce:" + operation + ":" + hex.EncodeToString(sum[:]), nil
+}
+
+// ttl
+// returns how long the result of a query ending on end is cached
+func (c cachingCeClient) ttl(end string) time.Duration {
+	endDate, err := time.Parse(types.DEFAULT_DATE_FORMAT, end)
+	if err != nil {
+		return c.openTtl
+	}
+	closed := c.now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -c.openDays)
+	if endDate.After(closed) {
+		return c.openTtl
+	}
+	return c.closedTtl
+}
+
+// cached
+// decodes the cached output of the query, or calls the query and caches its output. Cache
+// errors are not fatal, the query is sent to Cost Explorer instead.
+func (c cachingCeClient) cached(ctx context.Context, operation string, input interface{}, end string, output interface{}, call func() (interface{}, error)) error {
+	key, err := ceCacheKey(operation, input)
+	if err != nil {
+		return err
+	}
+
+	data, ok, err := c.cache.Get(ctx, key)
+	if err == nil && ok && json.Unmarshal(data, output) == nil {
+		ceCacheRequests.WithLabelValues(operation, "hit").Inc()
+		return nil
+	}
+	ceCacheRequests.WithLabelValues(operation, "miss").Inc()
+
+	resp, err := call()
+	if err != nil {
+		return err
+	}
+	if data, err = json.Marshal(resp); err != nil {
+		return err
+	}
+	c.cache.Set(ctx, key, data, c.ttl(end))
+	return json.Unmarshal(data, output)
+}
+
+func (c cachingCeClient) GetCostAndUsage(ctx context.Context, params *costexplorer.GetCostAndUsageInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostAndUsageOutput, error) {
+	end := ""
+	if params.TimePeriod != nil && params.TimePeriod.End != nil {
+		end = *params.TimePeriod.End
+	}
+	resp := &costexplorer.GetCostAndUsageOutput{}
+	err := c.cached(ctx, "GetCostAndUsage", params, end, resp, func() (interface{}, error) {
+		return c.client.GetCostAndUsage(ctx, params, optFns...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c cachingCeClient) GetCostForecast(ctx context.Context, params *costexplorer.GetCostForecastInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostForecastOutput, error) {
+	end := ""
+	if params.TimePeriod != nil && params.TimePeriod.End != nil {
+		end = *params.TimePeriod.End
+	}
+	resp := &costexplorer.GetCostForecastOutput{}
+	err := c.cached(ctx, "GetCostForecast", params, end, resp, func() (interface{}, error) {
+		return c.client.GetCostForecast(ctx, params, optFns...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetSavingsPlansPurchaseRecommendation
+// caches the recommendations for the open ttl, they are recomputed daily over the lookback period
+func (c cachingCeClient) GetSavingsPlansPurchaseRecommendation(ctx context.Context, params *costexplorer.GetSavingsPlansPurchaseRecommendationInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetSavingsPlansPurchaseRecommendationOutput, error) {
+	resp := &costexplorer.GetSavingsPlansPurchaseRecommendationOutput{}
+	err := c.cached(ctx, "GetSavingsPlansPurchaseRecommendation", params, "", resp, func() (interface{}, error) {
+		return c.client.GetSavingsPlansPurchaseRecommendation(ctx, params, optFns...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetReservationPurchaseRecommendation
+// caches the recommendations for the open ttl, they are recomputed daily over the lookback period
+func (c cachingCeClient) GetReservationPurchaseRecommendation(ctx context.Context, params *costexplorer.GetReservationPurchaseRecommendationInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetReservationPurchaseRecommendationOutput, error) {
+	resp := &costexplorer.GetReservationPurchaseRecommendationOutput{}
+	err := c.cached(ctx, "GetReservationPurchaseRecommendation", params, "", resp, func() (interface{}, error) {
+		return c.client.GetReservationPurchaseRecommendation(ctx, params, optFns...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c cachingCeClient) GetSavingsPlansCoverage(ctx context.Context, params *costexplorer.GetSavingsPlansCoverageInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetSavingsPlansCoverageOutput, error) {
+	end := ""
+	if params.TimePeriod != nil && params.TimePeriod.End != nil {
+		end = *params.TimePeriod.End
+	}
+	resp := &costexplorer.GetSavingsPlansCoverageOutput{}
+	err := c.cached(ctx, "GetSavingsPlansCoverage", params, end, resp, func() (interface{}, error) {
+		return c.client.GetSavingsPlansCoverage(ctx, params, optFns...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c cachingCeClient) GetSavingsPlansUtilization(ctx context.Context, params *costexplorer.GetSavingsPlansUtilizationInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetSavingsPlansUtilizationOutput, error) {
+	end := ""
+	if params.TimePeriod != nil && params.TimePeriod.End != nil {
+		end = *params.TimePeriod.End
+	}
+	resp := &costexplorer.GetSavingsPlansUtilizationOutput{}
+	err := c.cached(ctx, "GetSavingsPlansUtilization", params, end, resp, func() (interface{}, error) {
+		return c.client.GetSavingsPlansUtilization(ctx, params, optFns...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c cachingCeClient) GetReservationCoverage(ctx context.Context, params *costexplorer.GetReservationCoverageInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetReservationCoverageOutput, error) {
+	end := ""
+	if params.TimePeriod != nil && params.TimePeriod.End != nil {
+		end = *params.TimePeriod.End
+	}
+	resp := &costexplorer.GetReservationCoverageOutput{}
+	err := c.cached(ctx, "GetReservationCoverage", params, end, resp, func() (interface{}, error) {
+		return c.client.GetReservationCoverage(ctx, params, optFns...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c cachingCeClient) GetReservationUtilization(ctx context.Context, params *costexplorer.GetReservationUtilizationInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetReservationUtilizationOutput, error) {
+	end := ""
+	if params.TimePeriod != nil && params.TimePeriod.End != nil {
+		end = *params.TimePeriod.End
+	}
+	resp := &costexplorer.GetReservationUtilizationOutput{}
+	err := c.cached(ctx, "GetReservationUtilization", params, end, resp, func() (interface{}, error) {
+		return c.client.GetReservationUtilization(ctx, params, optFns...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetRightsizingRecommendation
+// caches the recommendations for the open ttl, they are recomputed daily over the last 14 days
+func (c cachingCeClient) GetRightsizingRecommendation(ctx context.Context, params *costexplorer.GetRightsizingRecommendationInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetRightsizingRecommendationOutput, error) {
+	resp := &costexplorer.GetRightsizingRecommendationOutput{}
+	err := c.cached(ctx, "GetRightsizingRecommendation", params, "", resp, func() (interface{}, error) {
+		return c.client.GetRightsizingRecommendation(ctx, params, optFns...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetDimensionValues
+// caches the values of the dimension like the costs of the time period of the query
+func (c cachingCeClient) GetDimensionValues(ctx context.Context, params *costexplorer.GetDimensionValuesInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetDimensionValuesOutput, error) {
+	end := ""
+	if params.TimePeriod != nil && params.TimePeriod.End != nil {
+		end = *params.TimePeriod.End
+	}
+	resp := &costexplorer.GetDimensionValuesOutput{}
+	err := c.cached(ctx, "GetDimensionValues", params, end, resp, func() (interface{}, error) {
+		return c.client.GetDimensionValues(ctx, params, optFns...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c cachingCeClient) GetCostAndUsageWithResources(ctx context.Context, params *costexplorer.GetCostAndUsageWithResourcesInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostAndUsageWithResourcesOutput, error) {
+	end := ""
+	if params.TimePeriod != nil && params.TimePeriod.End != nil {
+		end = *params.TimePeriod.End
+	}
+	resp := &costexplorer.GetCostAndUsageWithResourcesOutput{}
+	err := c.cached(ctx, "GetCostAndUsageWithResources", params, end, resp, func() (interface{}, error) {
+		return c.client.GetCostAndUsageWithResources(ctx, params, optFns...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
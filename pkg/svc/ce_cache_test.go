@@ -0,0 +1,88 @@
+package svc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	ceTypes "github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/seizadi/cost-insights-backend/pkg/cache"
+)
+
+// ttlCache
+// remembers the ttl of every value it stores
+type ttlCache struct {
+	cache.Cache
+	ttls map[string]time.Duration
+}
+
+func (c ttlCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.ttls[key] = ttl
+	return c.Cache.Set(ctx, key, value, ttl)
+}
+
+func ceCacheCount(t *testing.T, result string) float64 {
+	var metric dto.Metric
+	if err := ceCacheRequests.WithLabelValues("GetCostAndUsage", result).Write(&metric); err != nil {
+		t.Fatal(err)
+	}
+	return metric.GetCounter().GetValue()
+}
+
+func TestCachingCeClient(t *testing.T) {
+	capturing := &capturingCeClient{}
+	c := ttlCache{Cache: cache.NewLRUCache(10), ttls: map[string]time.Duration{}}
+	client := &cachingCeClient{
+		client:    capturing,
+		cache:     c,
+		openDays:  3,
+		openTtl:   time.Hour,
+		closedTtl: 24 * time.Hour,
+		now:       func() time.Time { return time.Date(2021, 9, 10, 12, 0, 0, 0, time.UTC) },
+	}
+
+	query := func(start string, end string) *costexplorer.GetCostAndUsageInput {
+		return &costexplorer.GetCostAndUsageInput{
+			TimePeriod:  &ceTypes.DateInterval{Start: &start, End: &end},
+			Metrics:     []string{string(ceTypes.MetricNetAmortizedCost)},
+			Granularity: ceTypes.GranularityDaily,
+		}
+	}
+
+	var tests = []struct {
+		name    string
+		input   *costexplorer.GetCostAndUsageInput
+		queries int
+		ttl     time.Duration
+	}{
+		{name: "miss on closed days", input: query("2021-08-01", "2021-09-01"), queries: 1, ttl: 24 * time.Hour},
+		{name: "hit on the same query", input: query("2021-08-01", "2021-09-01"), queries: 1, ttl: 24 * time.Hour},
+		{name: "miss on open days", input: query("2021-09-01", "2021-09-10"), queries: 2, ttl: time.Hour},
+		{name: "last closed day", input: query("2021-09-01", "2021-09-07"), queries: 3, ttl: 24 * time.Hour},
+	}
+	hits := ceCacheCount(t, "hit")
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp, err := client.GetCostAndUsage(context.Background(), test.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(resp.ResultsByTime) == 0 {
+				t.Error("Expected the results of the query")
+			}
+			if len(capturing.inputs) != test.queries {
+				t.Errorf("Output %d queries not equal to expected %d", len(capturing.inputs), test.queries)
+			}
+			key, _ := ceCacheKey("GetCostAndUsage", test.input)
+			if c.ttls[key] != test.ttl {
+				t.Errorf("Output ttl %v not equal to expected %v", c.ttls[key], test.ttl)
+			}
+		})
+	}
+	if actual := ceCacheCount(t, "hit") - hits; actual != 1 {
+		t.Errorf("Output %v cache hits not equal to expected 1", actual)
+	}
+}
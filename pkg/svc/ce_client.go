@@ -0,0 +1,364 @@
+package svc
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	"github.com/spf13/viper"
+
+	"github.com/seizadi/cost-insights-backend/pkg/cache"
+	"github.com/seizadi/cost-insights-backend/pkg/store"
+)
+
+// Modes of the Cost Explorer client selected with the cost.aws.client configuration
+const (
+	LiveCeClient   = "live"
+	RecordCeClient = "record"
+	ReplayCeClient = "replay"
+	StoreCeClient  = "store"
+)
+
+// CostExplorerClient
+// is the subset of the AWS Cost Explorer API used by the AWS cost provider. It is satisfied by
+// *costexplorer.Client as well as the recording and replay clients below, which lets the
+// handlers run against fixtures without access to AWS.
+type CostExplorerClient interface {
+	GetCostAndUsage(ctx context.Context, params *costexplorer.GetCostAndUsageInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostAndUsageOutput, error)
+	GetCostForecast(ctx context.Context, params *costexplorer.GetCostForecastInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostForecastOutput, error)
+	GetSavingsPlansPurchaseRecommendation(ctx context.Context, params *costexplorer.GetSavingsPlansPurchaseRecommendationInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetSavingsPlansPurchaseRecommendationOutput, error)
+	GetReservationPurchaseRecommendation(ctx context.Context, params *costexplorer.GetReservationPurchaseRecommendationInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetReservationPurchaseRecommendationOutput, error)
+	GetSavingsPlansCoverage(ctx context.Context, params *costexplorer.GetSavingsPlansCoverageInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetSavingsPlansCoverageOutput, error)
+	GetSavingsPlansUtilization(ctx context.Context, params *costexplorer.GetSavingsPlansUtilizationInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetSavingsPlansUtilizationOutput, error)
+	GetReservationCoverage(ctx context.Context, params *costexplorer.GetReservationCoverageInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetReservationCoverageOutput, error)
+	GetReservationUtilization(ctx context.Context, params *costexplorer.GetReservationUtilizationInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetReservationUtilizationOutput, error)
+	GetRightsizingRecommendation(ctx context.Context, params *costexplorer.GetRightsizingRecommendationInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetRightsizingRecommendationOutput, error)
+	GetDimensionValues(ctx context.Context, params *costexplorer.GetDimensionValuesInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetDimensionValuesOutput, error)
+	GetCostAndUsageWithResources(ctx context.Context, params *costexplorer.GetCostAndUsageWithResourcesInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostAndUsageWithResourcesOutput, error)
+}
+
+// NewCeClient
+// returns the Cost Explorer client for the cost.aws.client mode: live queries AWS, record
+// queries AWS and saves every response to the cost.aws.fixtures directory, replay serves the
+// saved responses without calling AWS, and store serves the daily cost snapshots of the
+// database s. Queries to AWS go through the cost.cache.backend cache when one is configured.
+func NewCeClient(s store.Store) (CostExplorerClient, error) {
+	mode := viper.GetString("cost.aws.client")
+
+	switch mode {
+	case ReplayCeClient:
+		return NewAwsCeClient()
+	case StoreCeClient:
+		if s == nil {
+			return nil, errors.New("the store cost explorer client requires database.enable")
+		}
+		return NewStoreCeClient(s), nil
+	}
+
+	client, err := NewAwsCeClient()
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := cache.NewCache()
+	if err != nil {
+		return nil, err
+	}
+	if c != nil {
+		client = NewCachingCeClient(client, c)
+	}
+	return client, nil
+}
+
+// NewAwsCeClient
+// returns the uncached Cost Explorer client of the cost.aws.client mode, the store mode queries
+// AWS as the snapshots are ingested from AWS.
+func NewAwsCeClient() (CostExplorerClient, error) {
+	mode := viper.GetString("cost.aws.client")
+	fixtures := viper.GetString("cost.aws.fixtures")
+
+	switch mode {
+	case ReplayCeClient:
+		return NewReplayCeClient(fixtures), nil
+	case LiveCeClient, RecordCeClient, StoreCeClient, "":
+	default:
+		return nil, errors.New("unknown cost explorer client: " + mode)
+	}
+
+	cfg, err := config.LoadDefaultConfig(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	client := costexplorer.NewFromConfig(cfg)
+	if mode == RecordCeClient {
+		return NewRecordingCeClient(client, fixtures), nil
+	}
+	return client, nil
+}
+
+// ceFixture
+// is the JSON document saved for each recorded Cost Explorer call
+type ceFixture struct {
+	Operation string          `json:"Operation"`
+	Input     json.RawMessage `json:"Input"`
+	Output    json.RawMessage `json:"Output"`
+}
+
+// ceFixturePath
+// returns the fixture file for an operation and its input. The input is normalized by its JSON
+// encoding so the same query always maps to the same file.
+func ceFixturePath(dir string, operation string, input interface{}) (string, []byte, error) {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return "", nil, err
+	}
+	sum := sha1.Sum(append([]byte(operation), data...))
+	return filepath.Join(dir, operation+"-"+hex.EncodeToString(sum[:])[:16]+".json"), data, nil
+}
+
+// recordingCeClient
+// forwards every call to Cost Explorer and saves the response as a fixture
+type recordingCeClient struct {
+	client CostExplorerClient
+	dir    string
+}
+
+// NewRecordingCeClient
+// returns a client that saves the responses of client to JSON fixtures in dir
+func NewRecordingCeClient(client CostExplorerClient, dir string) CostExplorerClient {
+	return &recordingCeClient{client: client, dir: dir}
+}
+
+func (r recordingCeClient) record(operation string, input interface{}, output interface{}) error {
+	path, inputData, err := ceFixturePath(r.dir, operation, input)
+	if err != nil {
+		return err
+	}
+	outputData, err := json.Marshal(output)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(ceFixture{Operation: operation, Input: inputData, Output: outputData})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(r.dir, 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+func (r recordingCeClient) GetCostAndUsage(ctx context.Context, params *costexplorer.GetCostAndUsageInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostAndUsageOutput, error) {
+	resp, err := r.client.GetCostAndUsage(ctx, params, optFns...)
+	if err != nil {
+		return nil, err
+	}
+	return resp, r.record("GetCostAndUsage", params, resp)
+}
+
+func (r recordingCeClient) GetCostForecast(ctx context.Context, params *costexplorer.GetCostForecastInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostForecastOutput, error) {
+	resp, err := r.client.GetCostForecast(ctx, params, optFns...)
+	if err != nil {
+		return nil, err
+	}
+	return resp, r.record("GetCostForecast", params, resp)
+}
+
+func (r recordingCeClient) GetSavingsPlansPurchaseRecommendation(ctx context.Context, params *costexplorer.GetSavingsPlansPurchaseRecommendationInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetSavingsPlansPurchaseRecommendationOutput, error) {
+	resp, err := r.client.GetSavingsPlansPurchaseRecommendation(ctx, params, optFns...)
+	if err != nil {
+		return nil, err
+	}
+	return resp, r.record("GetSavingsPlansPurchaseRecommendation", params, resp)
+}
+
+func (r recordingCeClient) GetReservationPurchaseRecommendation(ctx context.Context, params *costexplorer.GetReservationPurchaseRecommendationInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetReservationPurchaseRecommendationOutput, error) {
+	resp, err := r.client.GetReservationPurchaseRecommendation(ctx, params, optFns...)
+	if err != nil {
+		return nil, err
+	}
+	return resp, r.record("GetReservationPurchaseRecommendation", params, resp)
+}
+
+func (r recordingCeClient) GetSavingsPlansCoverage(ctx context.Context, params *costexplorer.GetSavingsPlansCoverageInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetSavingsPlansCoverageOutput, error) {
+	resp, err := r.client.GetSavingsPlansCoverage(ctx, params, optFns...)
+	if err != nil {
+		return nil, err
+	}
+	return resp, r.record("GetSavingsPlansCoverage", params, resp)
+}
+
+func (r recordingCeClient) GetSavingsPlansUtilization(ctx context.Context, params *costexplorer.GetSavingsPlansUtilizationInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetSavingsPlansUtilizationOutput, error) {
+	resp, err := r.client.GetSavingsPlansUtilization(ctx, params, optFns...)
+	if err != nil {
+		return nil, err
+	}
+	return resp, r.record("GetSavingsPlansUtilization", params, resp)
+}
+
+func (r recordingCeClient) GetReservationCoverage(ctx context.Context, params *costexplorer.GetReservationCoverageInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetReservationCoverageOutput, error) {
+	resp, err := r.client.GetReservationCoverage(ctx, params, optFns...)
+	if err != nil {
+		return nil, err
+	}
+	return resp, r.record("GetReservationCoverage", params, resp)
+}
+
+func (r recordingCeClient) GetReservationUtilization(ctx context.Context, params *costexplorer.GetReservationUtilizationInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetReservationUtilizationOutput, error) {
+	resp, err := r.client.GetReservationUtilization(ctx, params, optFns...)
+	if err != nil {
+		return nil, err
+	}
+	return resp, r.record("GetReservationUtilization", params, resp)
+}
+
+func (r recordingCeClient) GetRightsizingRecommendation(ctx context.Context, params *costexplorer.GetRightsizingRecommendationInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetRightsizingRecommendationOutput, error) {
+	resp, err := r.client.GetRightsizingRecommendation(ctx, params, optFns...)
+	if err != nil {
+		return nil, err
+	}
+	return resp, r.record("GetRightsizingRecommendation", params, resp)
+}
+
+func (r recordingCeClient) GetDimensionValues(ctx context.Context, params *costexplorer.GetDimensionValuesInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetDimensionValuesOutput, error) {
+	resp, err := r.client.GetDimensionValues(ctx, params, optFns...)
+	if err != nil {
+		return nil, err
+	}
+	return resp, r.record("GetDimensionValues", params, resp)
+}
+
+func (r recordingCeClient) GetCostAndUsageWithResources(ctx context.Context, params *costexplorer.GetCostAndUsageWithResourcesInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostAndUsageWithResourcesOutput, error) {
+	resp, err := r.client.GetCostAndUsageWithResources(ctx, params, optFns...)
+	if err != nil {
+		return nil, err
+	}
+	return resp, r.record("GetCostAndUsageWithResources", params, resp)
+}
+
+// replayCeClient
+// serves Cost Explorer responses from the fixtures saved by the recording client
+type replayCeClient struct {
+	dir string
+}
+
+// NewReplayCeClient
+// returns a client that serves the JSON fixtures in dir
+func NewReplayCeClient(dir string) CostExplorerClient {
+	return &replayCeClient{dir: dir}
+}
+
+func (r replayCeClient) replay(operation string, input interface{}, output interface{}) error {
+	path, _, err := ceFixturePath(r.dir, operation, input)
+	if err != nil {
+		return err
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return errors.New("no fixture recorded for " + operation + ": " + path)
+		}
+		return err
+	}
+	var fixture ceFixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return err
+	}
+	return json.Unmarshal(fixture.Output, output)
+}
+
+func (r replayCeClient) GetCostAndUsage(ctx context.Context, params *costexplorer.GetCostAndUsageInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostAndUsageOutput, error) {
+	resp := &costexplorer.GetCostAndUsageOutput{}
+	if err := r.replay("GetCostAndUsage", params, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (r replayCeClient) GetCostForecast(ctx context.Context, params *costexplorer.GetCostForecastInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostForecastOutput, error) {
+	resp := &costexplorer.GetCostForecastOutput{}
+	if err := r.replay("GetCostForecast", params, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (r replayCeClient) GetSavingsPlansPurchaseRecommendation(ctx context.Context, params *costexplorer.GetSavingsPlansPurchaseRecommendationInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetSavingsPlansPurchaseRecommendationOutput, error) {
+	resp := &costexplorer.GetSavingsPlansPurchaseRecommendationOutput{}
+	if err := r.replay("GetSavingsPlansPurchaseRecommendation", params, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (r replayCeClient) GetReservationPurchaseRecommendation(ctx context.Context, params *costexplorer.GetReservationPurchaseRecommendationInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetReservationPurchaseRecommendationOutput, error) {
+	resp := &costexplorer.GetReservationPurchaseRecommendationOutput{}
+	if err := r.replay("GetReservationPurchaseRecommendation", params, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (r replayCeClient) GetSavingsPlansCoverage(ctx context.Context, params *costexplorer.GetSavingsPlansCoverageInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetSavingsPlansCoverageOutput, error) {
+	resp := &costexplorer.GetSavingsPlansCoverageOutput{}
+	if err := r.replay("GetSavingsPlansCoverage", params, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (r replayCeClient) GetSavingsPlansUtilization(ctx context.Context, params *costexplorer.GetSavingsPlansUtilizationInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetSavingsPlansUtilizationOutput, error) {
+	resp := &costexplorer.GetSavingsPlansUtilizationOutput{}
+	if err := r.replay("GetSavingsPlansUtilization", params, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (r replayCeClient) GetReservationCoverage(ctx context.Context, params *costexplorer.GetReservationCoverageInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetReservationCoverageOutput, error) {
+	resp := &costexplorer.GetReservationCoverageOutput{}
+	if err := r.replay("GetReservationCoverage", params, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (r replayCeClient) GetReservationUtilization(ctx context.Context, params *costexplorer.GetReservationUtilizationInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetReservationUtilizationOutput, error) {
+	resp := &costexplorer.GetReservationUtilizationOutput{}
+	if err := r.replay("GetReservationUtilization", params, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (r replayCeClient) GetRightsizingRecommendation(ctx context.Context, params *costexplorer.GetRightsizingRecommendationInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetRightsizingRecommendationOutput, error) {
+	resp := &costexplorer.GetRightsizingRecommendationOutput{}
+	if err := r.replay("GetRightsizingRecommendation", params, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (r replayCeClient) GetDimensionValues(ctx context.Context, params *costexplorer.GetDimensionValuesInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetDimensionValuesOutput, error) {
+	resp := &costexplorer.GetDimensionValuesOutput{}
+	if err := r.replay("GetDimensionValues", params, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (r replayCeClient) GetCostAndUsageWithResources(ctx context.Context, params *costexplorer.GetCostAndUsageWithResourcesInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostAndUsageWithResourcesOutput, error) {
+	resp := &costexplorer.GetCostAndUsageWithResourcesOutput{}
+	if err := r.replay("GetCostAndUsageWithResources", params, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,270 @@
+package svc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	ceTypes "github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+	"github.com/spf13/viper"
+
+	"github.com/seizadi/cost-insights-backend/pkg/store"
+	"github.com/seizadi/cost-insights-backend/pkg/types"
+)
+
+// storeCeClient
+// answers the daily Cost Explorer queries of the AWS provider from the daily cost snapshots in
+// the store, which keeps the history beyond the 14 months retained by Cost Explorer.
+type storeCeClient struct {
+	store store.Store
+}
+
+// NewStoreCeClient
+// returns a client serving the daily costs saved in the store
+func NewStoreCeClient(s store.Store) CostExplorerClient {
+	return &storeCeClient{store: s}
+}
+
+// storeFilter
+// adds the LINKED_ACCOUNT and SERVICE values of a Cost Explorer filter to the store query, the
+// values of a dimension filtered more than once in an And are intersected like Cost Explorer
+// does. An empty intersection leaves an empty, not nil, list that matches no costs.
+func storeFilter(filter *ceTypes.Expression, query *store.Query) error {
+	if filter == nil {
+		return nil
+	}
+	for i := range filter.And {
+		if err := storeFilter(&filter.And[i], query); err != nil {
+			return err
+		}
+	}
+	if filter.Dimensions != nil {
+		switch filter.Dimensions.Key {
+		case ceTypes.DimensionLinkedAccount:
+			query.Accounts = intersectValues(query.Accounts, filter.Dimensions.Values)
+		case ceTypes.DimensionService:
+			query.Services = intersectValues(query.Services, filter.Dimensions.Values)
+		default:
+			return errors.New("unsupported filter dimension in store: " + string(filter.Dimensions.Key))
+		}
+	}
+	if len(filter.Or) > 0 || filter.Not != nil || filter.Tags != nil || filter.CostCategories != nil {
+		return errors.New("unsupported filter in store")
+	}
+	return nil
+}
+
+// intersectValues
+// returns the values also in the filtered values, all the values when nothing is filtered yet
+func intersectValues(filtered []string, values []string) []string {
+	if filtered == nil {
+		return append([]string{}, values...)
+	}
+	intersection := []string{}
+	for _, value := range values {
+		if containsString(filtered, value) {
+			intersection = append(intersection, value)
+		}
+	}
+	return intersection
+}
+
+// storeMatchesNothing returns true when a filtered dimension of the query has no values left
+func storeMatchesNothing(query store.Query) bool {
+	return (query.Accounts != nil && len(query.Accounts) == 0) || (query.Services != nil && len(query.Services) == 0)
+}
+
+// storeGroupKey
+// returns the Cost Explorer group key of the daily cost
+func storeGroupKey(group ceTypes.GroupDefinition, cost store.DailyCost) (string, error) {
+	switch {
+	case group.Type == ceTypes.GroupDefinitionTypeDimension && *group.Key == string(ceTypes.DimensionLinkedAccount):
+		return cost.Account, nil
+	case group.Type == ceTypes.GroupDefinitionTypeDimension && *group.Key == string(ceTypes.DimensionService):
+		return cost.Service, nil
+	case group.Type == ceTypes.GroupDefinitionTypeTag:
+		// Costs without the tag are grouped under the empty value like Cost Explorer does
+		if strings.HasPrefix(cost.Tag, *group.Key+"$") {
+			return cost.Tag, nil
+		}
+		return *group.Key + "$", nil
+	}
+	return "", fmt.Errorf("unsupported group in store: %s %s", group.Type, *group.Key)
+}
+
+func (c storeCeClient) GetCostAndUsage(ctx context.Context, params *costexplorer.GetCostAndUsageInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostAndUsageOutput, error) {
+	if params.Granularity != ceTypes.GranularityDaily {
+		return nil, errors.New("store only serves daily costs")
+	}
+	start, err := time.Parse(types.DEFAULT_DATE_FORMAT, *params.TimePeriod.Start)
+	if err != nil {
+		return nil, err
+	}
+	end, err := time.Parse(types.DEFAULT_DATE_FORMAT, *params.TimePeriod.End)
+	if err != nil {
+		return nil, err
+	}
+
+	// The amounts of a day by metric and group key, the empty key is the total
+	amounts := map[string]map[string]map[string]float64{}
+	for _, metric := range params.Metrics {
+		query := store.Query{Start: *params.TimePeriod.Start, End: *params.TimePeriod.End, Metric: metric}
+		if err := storeFilter(params.Filter, &query); err != nil {
+			return nil, err
+		}
+		if storeMatchesNothing(query) {
+			continue
+		}
+		costs, err := c.store.DailyCosts(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		for _, cost := range costs {
+			keys := []string{}
+			for _, group := range params.GroupBy {
+				key, err := storeGroupKey(group, cost)
+				if err != nil {
+					return nil, err
+				}
+				keys = append(keys, key)
+			}
+			if amounts[cost.Date] == nil {
+				amounts[cost.Date] = map[string]map[string]float64{}
+			}
+			groupKey := strings.Join(keys, "\x00")
+			if amounts[cost.Date][groupKey] == nil {
+				amounts[cost.Date][groupKey] = map[string]float64{}
+			}
+			amounts[cost.Date][groupKey][metric] += cost.Amount
+		}
+	}
+
+	unit := "USD"
+	metricValues := func(amounts map[string]float64) map[string]ceTypes.MetricValue {
+		values := map[string]ceTypes.MetricValue{}
+		for _, metric := range params.Metrics {
+			amount := fmt.Sprintf("%f", amounts[metric])
+			values[metric] = ceTypes.MetricValue{Amount: &amount, Unit: &unit}
+		}
+		return values
+	}
+
+	resp := &costexplorer.GetCostAndUsageOutput{}
+	for date := start; date.Before(end); date = date.AddDate(0, 0, 1) {
+		dayStart := date.Format(types.DEFAULT_DATE_FORMAT)
+		dayEnd := date.AddDate(0, 0, 1).Format(types.DEFAULT_DATE_FORMAT)
+		result := ceTypes.ResultByTime{
+			TimePeriod: &ceTypes.DateInterval{Start: &dayStart, End: &dayEnd},
+			Total:      map[string]ceTypes.MetricValue{},
+		}
+		if len(params.GroupBy) == 0 {
+			result.Total = metricValues(amounts[dayStart][""])
+		} else {
+			groupKeys := []string{}
+			for key := range amounts[dayStart] {
+				groupKeys = append(groupKeys, key)
+			}
+			sort.Strings(groupKeys)
+			for _, key := range groupKeys {
+				result.Groups = append(result.Groups, ceTypes.Group{
+					Keys:    strings.Split(key, "\x00"),
+					Metrics: metricValues(amounts[dayStart][key]),
+				})
+			}
+		}
+		resp.ResultsByTime = append(resp.ResultsByTime, result)
+	}
+	return resp, nil
+}
+
+// GetCostForecast
+// is not served by the store, which only holds the past daily costs
+func (c storeCeClient) GetCostForecast(ctx context.Context, params *costexplorer.GetCostForecastInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostForecastOutput, error) {
+	return nil, errors.New("store does not serve cost forecasts")
+}
+
+// GetSavingsPlansPurchaseRecommendation
+// is not served by the store, which does not hold the usage Cost Explorer recommends from
+func (c storeCeClient) GetSavingsPlansPurchaseRecommendation(ctx context.Context, params *costexplorer.GetSavingsPlansPurchaseRecommendationInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetSavingsPlansPurchaseRecommendationOutput, error) {
+	return nil, errors.New("store does not serve savings plans recommendations")
+}
+
+// GetReservationPurchaseRecommendation
+// is not served by the store, which does not hold the usage Cost Explorer recommends from
+func (c storeCeClient) GetReservationPurchaseRecommendation(ctx context.Context, params *costexplorer.GetReservationPurchaseRecommendationInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetReservationPurchaseRecommendationOutput, error) {
+	return nil, errors.New("store does not serve reservation recommendations")
+}
+
+// GetSavingsPlansCoverage
+// is not served by the store, which does not hold the commitments of the accounts
+func (c storeCeClient) GetSavingsPlansCoverage(ctx context.Context, params *costexplorer.GetSavingsPlansCoverageInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetSavingsPlansCoverageOutput, error) {
+	return nil, errors.New("store does not serve savings plans coverage")
+}
+
+// GetSavingsPlansUtilization
+// is not served by the store, which does not hold the commitments of the accounts
+func (c storeCeClient) GetSavingsPlansUtilization(ctx context.Context, params *costexplorer.GetSavingsPlansUtilizationInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetSavingsPlansUtilizationOutput, error) {
+	return nil, errors.New("store does not serve savings plans utilization")
+}
+
+// GetReservationCoverage
+// is not served by the store, which does not hold the commitments of the accounts
+func (c storeCeClient) GetReservationCoverage(ctx context.Context, params *costexplorer.GetReservationCoverageInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetReservationCoverageOutput, error) {
+	return nil, errors.New("store does not serve reservation coverage")
+}
+
+// GetReservationUtilization
+// is not served by the store, which does not hold the commitments of the accounts
+func (c storeCeClient) GetReservationUtilization(ctx context.Context, params *costexplorer.GetReservationUtilizationInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetReservationUtilizationOutput, error) {
+	return nil, errors.New("store does not serve reservation utilization")
+}
+
+// GetRightsizingRecommendation
+// is not served by the store, which does not hold the instances of the accounts
+func (c storeCeClient) GetRightsizingRecommendation(ctx context.Context, params *costexplorer.GetRightsizingRecommendationInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetRightsizingRecommendationOutput, error) {
+	return nil, errors.New("store does not serve rightsizing recommendations")
+}
+
+// GetDimensionValues
+// returns the services, or the linked accounts, of the cost.aws.datasets daily costs of the time
+// period in the store
+func (c storeCeClient) GetDimensionValues(ctx context.Context, params *costexplorer.GetDimensionValuesInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetDimensionValuesOutput, error) {
+	if params.Dimension != ceTypes.DimensionService && params.Dimension != ceTypes.DimensionLinkedAccount {
+		return nil, errors.New("unsupported dimension in store: " + string(params.Dimension))
+	}
+	query := store.Query{Start: *params.TimePeriod.Start, End: *params.TimePeriod.End, Metric: viper.GetString("cost.aws.datasets")}
+	if err := storeFilter(params.Filter, &query); err != nil {
+		return nil, err
+	}
+	costs, err := c.store.DailyCosts(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]bool{}
+	for _, cost := range costs {
+		if params.Dimension == ceTypes.DimensionLinkedAccount {
+			values[cost.Account] = true
+		} else {
+			values[cost.Service] = true
+		}
+	}
+	resp := &costexplorer.GetDimensionValuesOutput{}
+	for value := range values {
+		value := value
+		resp.DimensionValues = append(resp.DimensionValues, ceTypes.DimensionValuesWithAttributes{Value: &value})
+	}
+	sort.Slice(resp.DimensionValues, func(i, j int) bool {
+		return *resp.DimensionValues[i].Value < *resp.DimensionValues[j].Value
+	})
+	return resp, nil
+}
+
+// GetCostAndUsageWithResources
+// is not served by the store, which does not hold the costs by resource
+func (c storeCeClient) GetCostAndUsageWithResources(ctx context.Context, params *costexplorer.GetCostAndUsageWithResourcesInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostAndUsageWithResourcesOutput, error) {
+	return nil, errors.New("store does not serve costs by resource")
+}
@@ -0,0 +1,196 @@
+package svc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	ceTypes "github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+	"github.com/spf13/viper"
+
+	"github.com/seizadi/cost-insights-backend/pkg/pb"
+	"github.com/seizadi/cost-insights-backend/pkg/store"
+)
+
+func newTestStore(t *testing.T) store.Store {
+	if !store.SqliteSupported {
+		t.Skip("the SQLite driver needs cgo")
+	}
+	s, err := store.Open(store.SqliteStore, ":memory:")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	metric := string(ceTypes.MetricNetAmortizedCost)
+	err = s.Save(context.Background(), []store.DailyCost{
+		{Date: "2021-09-01", Account: "111111111111", Service: "AWS Lambda", Tag: "Product$service-a", Metric: metric, Amount: 1},
+		{Date: "2021-09-01", Account: "111111111111", Service: "AWS Lambda", Tag: "", Metric: metric, Amount: 2},
+		{Date: "2021-09-01", Account: "222222222222", Service: "Amazon Simple Storage Service", Tag: "", Metric: metric, Amount: 4},
+		{Date: "2021-09-02", Account: "111111111111", Service: "Amazon Simple Storage Service", Tag: "Product$service-b", Metric: metric, Amount: 8},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return s
+}
+
+func TestStoreCeClient(t *testing.T) {
+	s := newTestStore(t)
+	defer s.Close()
+	client := NewStoreCeClient(s)
+
+	start := "2021-09-01"
+	end := "2021-09-03"
+	query := func(filter *ceTypes.Expression, groupBy ...ceTypes.GroupDefinition) map[string]float64 {
+		resp, err := client.GetCostAndUsage(context.Background(), &costexplorer.GetCostAndUsageInput{
+			TimePeriod:  &ceTypes.DateInterval{Start: &start, End: &end},
+			Metrics:     []string{string(ceTypes.MetricNetAmortizedCost)},
+			Filter:      filter,
+			Granularity: ceTypes.GranularityDaily,
+			GroupBy:     groupBy,
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(resp.ResultsByTime) != 2 {
+			t.Fatalf("Output %d days not equal to expected 2", len(resp.ResultsByTime))
+		}
+		metric := string(ceTypes.MetricNetAmortizedCost)
+		amounts := map[string]float64{}
+		for _, result := range resp.ResultsByTime {
+			if total, ok := result.Total[metric]; ok {
+				amounts[*result.TimePeriod.Start] += getAwsMetricAmount(total)
+			}
+			for _, group := range result.Groups {
+				amounts[*result.TimePeriod.Start+" "+group.Keys[0]] += getAwsMetricAmount(group.Metrics[metric])
+			}
+		}
+		return amounts
+	}
+
+	serviceKey := "SERVICE"
+	tagKey := "Product"
+	var tests = []struct {
+		name     string
+		actual   map[string]float64
+		expected map[string]float64
+	}{
+		{
+			name:     "total",
+			actual:   query(nil),
+			expected: map[string]float64{"2021-09-01": 7, "2021-09-02": 8},
+		},
+		{
+			name:     "grouped by service on an account",
+			actual:   query(linkedAccountFilter([]string{"111111111111"}), ceTypes.GroupDefinition{Key: &serviceKey, Type: ceTypes.GroupDefinitionTypeDimension}),
+			expected: map[string]float64{"2021-09-01 AWS Lambda": 3, "2021-09-02 Amazon Simple Storage Service": 8},
+		},
+		{
+			name: "intersection of account filters",
+			actual: query(&ceTypes.Expression{And: []ceTypes.Expression{
+				*linkedAccountFilter([]string{"111111111111", "222222222222"}),
+				*linkedAccountFilter([]string{"222222222222"}),
+			}}),
+			expected: map[string]float64{"2021-09-01": 4, "2021-09-02": 0},
+		},
+		{
+			name: "empty intersection of account filters",
+			actual: query(&ceTypes.Expression{And: []ceTypes.Expression{
+				*linkedAccountFilter([]string{"111111111111"}),
+				*linkedAccountFilter([]string{"222222222222"}),
+			}}),
+			expected: map[string]float64{"2021-09-01": 0, "2021-09-02": 0},
+		},
+		{
+			name:     "grouped by tag",
+			actual:   query(nil, ceTypes.GroupDefinition{Key: &tagKey, Type: ceTypes.GroupDefinitionTypeTag}),
+			expected: map[string]float64{"2021-09-01 Product$": 6, "2021-09-01 Product$service-a": 1, "2021-09-02 Product$service-b": 8},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if len(test.actual) != len(test.expected) {
+				t.Fatalf("Output %v not equal to expected %v", test.actual, test.expected)
+			}
+			for key, amount := range test.expected {
+				if test.actual[key] != amount {
+					t.Errorf("Output %s %v not equal to expected %v", key, test.actual[key], amount)
+				}
+			}
+		})
+	}
+}
+
+func TestAwsServerFromStore(t *testing.T) {
+	setTestCostConfig()
+	s := newTestStore(t)
+	defer s.Close()
+	// The trendline of the change statistic needs a few more days
+	metric := string(ceTypes.MetricNetAmortizedCost)
+	for _, date := range []string{"2021-09-03", "2021-09-04", "2021-09-05"} {
+		err := s.Save(context.Background(), []store.DailyCost{{Date: date, Account: "111111111111", Service: "AWS Lambda", Metric: metric, Amount: 5}})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	server := costInsightsAwsServer{client: NewStoreCeClient(s), groups: testGroupDirectory}
+
+	resp, err := server.GetProjectDailyCost(context.Background(), &pb.ProjectDailyCostRequest{Project: "111111111111", Intervals: "R2/P7D/2021-09-08"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	amounts := map[string]float64{}
+	for _, aggregation := range resp.Aggregation {
+		amounts[aggregation.Date] = aggregation.Amount
+	}
+	if amounts["2021-09-01"] != 3 || amounts["2021-09-02"] != 8 {
+		t.Errorf("Output %v not equal to the stored costs of the project", amounts)
+	}
+
+	products, err := server.GetProducts(context.Background(), &pb.ProductsRequest{Group: "platform", Project: "111111111111", Intervals: "R1/P7D/2021-09-02"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(products.Products) != 1 || products.Products[0].Id != "Lambda" || products.Products[0].Name != "AWS Lambda" {
+		t.Errorf("Output %v not equal to the stored services of the project", products.Products)
+	}
+
+	start, end := "2021-09-01", "2021-09-02"
+	accounts, err := server.client.GetDimensionValues(context.Background(), &costexplorer.GetDimensionValuesInput{
+		TimePeriod: &ceTypes.DateInterval{Start: &start, End: &end},
+		Dimension:  ceTypes.DimensionLinkedAccount,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(accounts.DimensionValues) != 2 || *accounts.DimensionValues[0].Value != "111111111111" || *accounts.DimensionValues[1].Value != "222222222222" {
+		t.Errorf("Output %v not equal to the stored accounts of the day", accounts.DimensionValues)
+	}
+}
+
+func TestSharedStore(t *testing.T) {
+	s := newTestStore(t)
+	defer s.Close()
+	mode := viper.GetString("cost.aws.client")
+	defer viper.Set("cost.aws.client", mode)
+
+	// The Cost Explorer client and the alert statuses use the store of the provider
+	viper.Set("cost.aws.client", StoreCeClient)
+	client, err := NewCeClient(s)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if c, ok := client.(*storeCeClient); !ok || c.store != s {
+		t.Errorf("Output %v is not a client of the store", client)
+	}
+	if statuses := NewAlertStatusStore(s); statuses != AlertStatusStore(s) {
+		t.Errorf("Output %v not equal to the store", statuses)
+	}
+
+	// Without a database the alert statuses are kept in memory and the store client fails
+	if _, ok := NewAlertStatusStore(nil).(*memoryAlertStatusStore); !ok {
+		t.Errorf("Expected the in-memory alert statuses without a store")
+	}
+	if _, err := NewCeClient(nil); err == nil {
+		t.Errorf("Expected an error for the store client without a store")
+	}
+}
@@ -0,0 +1,123 @@
+package svc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/logrus/ctxlogrus"
+	"github.com/sirupsen/logrus"
+
+	"github.com/seizadi/cost-insights-backend/pkg/cur"
+	"github.com/seizadi/cost-insights-backend/pkg/pb"
+)
+
+// costInsightsCurServer
+// serves the Cost Insights API from an AWS Cost and Usage Report export. The daily line items
+// are served like the file cost records, the Project is the usage account, the Product the AWS
+// product and the Entity the resource, or the usage type of line items without a resource.
+// AWS updates the export of the current month several times a day, the export is read again in
+// the background by the first call after the records are older than ttl.
+type costInsightsCurServer struct {
+	// costInsightsFileServer serves the calls that do not depend on the records
+	costInsightsFileServer
+	source     cur.Source
+	costColumn string
+	ttl        time.Duration
+	now        func() time.Time
+
+	mu      sync.Mutex
+	records costInsightsFileServer
+	loaded  time.Time
+	loading bool
+}
+
+// NewCostInsightsApiCurServer
+// returns a cost provider serving the export in source, costColumn is the CUR column of the cost.
+// The export is read again every ttl, never with a zero ttl.
+func NewCostInsightsApiCurServer(source cur.Source, costColumn string, ttl time.Duration) (CostProvider, error) {
+	m := &costInsightsCurServer{source: source, costColumn: costColumn, ttl: ttl, now: time.Now}
+	items, err := cur.Read(context.Background(), source, costColumn)
+	if err != nil {
+		return nil, err
+	}
+	m.records, m.loaded = costInsightsFileServer{records: curRecords(items)}, m.now()
+	return m, nil
+}
+
+// current
+// returns the file server of the records read last, and starts reading the export again when
+// they are older than the ttl. A failed read is logged and retried after the ttl, the old
+// records are served until a read succeeds.
+func (m *costInsightsCurServer) current(ctx context.Context) costInsightsFileServer {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.ttl > 0 && !m.loading && m.now().Sub(m.loaded) >= m.ttl {
+		m.loading = true
+		go m.reload(ctxlogrus.Extract(ctx))
+	}
+	return m.records
+}
+
+// reload
+// reads the export and replaces the records
+func (m *costInsightsCurServer) reload(logger *logrus.Entry) {
+	items, err := cur.Read(context.Background(), m.source, m.costColumn)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.loading, m.loaded = false, m.now()
+	if err != nil {
+		logger.Warnf("reading the cost and usage report export: %v", err)
+		return
+	}
+	m.records = costInsightsFileServer{records: curRecords(items)}
+}
+
+func (*costInsightsCurServer) Name() string {
+	return CurCostProvider
+}
+
+func (m *costInsightsCurServer) GetLastCompleteBillingDate(ctx context.Context, req *empty.Empty) (*pb.LastCompleteBillingDateResponse, error) {
+	return m.current(ctx).GetLastCompleteBillingDate(ctx, req)
+}
+
+func (m *costInsightsCurServer) GetGroupProjects(ctx context.Context, req *pb.GroupProjectsRequest) (*pb.GroupProjectsResponse, error) {
+	return m.current(ctx).GetGroupProjects(ctx, req)
+}
+
+func (m *costInsightsCurServer) GetGroupDailyCost(ctx context.Context, req *pb.GroupDailyCostRequest) (*pb.GroupDailyCostResponse, error) {
+	return m.current(ctx).GetGroupDailyCost(ctx, req)
+}
+
+func (m *costInsightsCurServer) GetProjectDailyCost(ctx context.Context, req *pb.ProjectDailyCostRequest) (*pb.ProjectDailyCostResponse, error) {
+	return m.current(ctx).GetProjectDailyCost(ctx, req)
+}
+
+func (m *costInsightsCurServer) GetProductInsights(ctx context.Context, req *pb.ProductInsightsRequest) (*pb.Entity, error) {
+	return m.current(ctx).GetProductInsights(ctx, req)
+}
+
+func (m *costInsightsCurServer) GetCostForecast(ctx context.Context, req *pb.CostForecastRequest) (*pb.CostForecastResponse, error) {
+	return m.current(ctx).GetCostForecast(ctx, req)
+}
+
+func (m *costInsightsCurServer) GetProducts(ctx context.Context, req *pb.ProductsRequest) (*pb.ProductsResponse, error) {
+	return m.current(ctx).GetProducts(ctx, req)
+}
+
+// curRecords
+// converts the line items, summed by date, account, product and entity, to file cost records
+func curRecords(items []cur.LineItem) []FileCostRecord {
+	records := []FileCostRecord{}
+	for _, item := range items {
+		records = append(records, FileCostRecord{
+			Date:    item.Date,
+			Project: item.Account,
+			Product: item.Product,
+			Entity:  item.Entity,
+			Amount:  item.Cost,
+		})
+	}
+	return records
+}
@@ -0,0 +1,362 @@
+package svc
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"sort"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/seizadi/cost-insights-backend/metrics"
+	"github.com/seizadi/cost-insights-backend/pkg/pb"
+	"github.com/seizadi/cost-insights-backend/pkg/types"
+	"github.com/seizadi/cost-insights-backend/pkg/utils"
+)
+
+// FileCostRecord
+// is a daily cost line item served by the file cost provider. Project is the billing entity
+// (AWS Account), Product the cloud product (AWS Service) and Entity the optional resource
+// used to break down product insights.
+type FileCostRecord struct {
+	Date    string  `json:"Date"`
+	Project string  `json:"Project"`
+	Product string  `json:"Product"`
+	Entity  string  `json:"Entity"`
+	Amount  float64 `json:"Amount"`
+}
+
+// costInsightsFileServer
+// serves the Cost Insights API from daily cost records loaded from a JSON file, which lets
+// development and demo environments run without access to AWS.
+type costInsightsFileServer struct {
+	records []FileCostRecord
+}
+
+// NewCostInsightsApiFileServer
+// returns a cost provider serving the cost records in the JSON file at path
+func NewCostInsightsApiFileServer(path string) (CostProvider, error) {
+	byteValue, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []FileCostRecord
+	err = json.Unmarshal(byteValue, &records)
+	if err != nil {
+		return nil, err
+	}
+
+	return &costInsightsFileServer{records: records}, nil
+}
+
+func (costInsightsFileServer) Name() string {
+	return FileCostProvider
+}
+
+// fileRecordsFor
+// returns the records in the interval window that match the filter
+func (m costInsightsFileServer) fileRecordsFor(intervals string, filter func(FileCostRecord) bool) ([]FileCostRecord, types.IntervalFields, error) {
+	interval, err := utils.ParseIntervals(intervals)
+	if err != nil {
+		return nil, interval, err
+	}
+
+	startDate := interval.StartDate
+
+	records := []FileCostRecord{}
+	for _, record := range m.records {
+		if record.Date < startDate || record.Date >= interval.EndDate {
+			continue
+		}
+		if filter != nil && !filter(record) {
+			continue
+		}
+		records = append(records, record)
+	}
+	return records, interval, nil
+}
+
+// aggregationForFile
+// sums the records by date into a CostInsights DateAggregation array sorted by date
+func aggregationForFile(records []FileCostRecord) []*pb.DateAggregation {
+	amounts := map[string]float64{}
+	for _, record := range records {
+		amounts[record.Date] += record.Amount
+	}
+
+	aggregation := []*pb.DateAggregation{}
+	for date, amount := range amounts {
+		aggregation = append(aggregation, &pb.DateAggregation{Date: date, Amount: amount})
+	}
+	sort.Slice(aggregation, func(i, j int) bool {
+		return aggregation[i].Date < aggregation[j].Date
+	})
+	return aggregation
+}
+
+// groupFileRecords
+// splits the records by the key returned for each record, returning the keys in sorted order
+func groupFileRecords(records []FileCostRecord, keyOf func(FileCostRecord) string) ([]string, map[string][]FileCostRecord) {
+	groups := map[string][]FileCostRecord{}
+	for _, record := range records {
+		key := keyOf(record)
+		groups[key] = append(groups[key], record)
+	}
+
+	keys := []string{}
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, groups
+}
+
+func getGroupedFileProducts(records []FileCostRecord) []*pb.ProductCost {
+	keys, groups := groupFileRecords(records, func(r FileCostRecord) string { return r.Product })
+	costs := []*pb.ProductCost{}
+	for _, key := range keys {
+		costs = append(costs, &pb.ProductCost{Id: key, Aggregation: aggregationForFile(groups[key])})
+	}
+	return costs
+}
+
+func getGroupedFileProjects(records []FileCostRecord) []*pb.ProjectCost {
+	keys, groups := groupFileRecords(records, func(r FileCostRecord) string { return r.Project })
+	costs := []*pb.ProjectCost{}
+	for _, key := range keys {
+		costs = append(costs, &pb.ProjectCost{Id: key, Aggregation: aggregationForFile(groups[key])})
+	}
+	return costs
+}
+
+// GetLastCompleteBillingDate
+// returns the latest date in the cost records, or yesterday when there are none.
+func (m costInsightsFileServer) GetLastCompleteBillingDate(context.Context, *empty.Empty) (*pb.LastCompleteBillingDateResponse, error) {
+	date := ""
+	for _, record := range m.records {
+		if record.Date > date {
+			date = record.Date
+		}
+	}
+	if date == "" {
+		date = time.Now().AddDate(0, 0, -1).Format(types.DEFAULT_DATE_FORMAT)
+	}
+	return &pb.LastCompleteBillingDateResponse{Date: date}, nil
+}
+
+func (costInsightsFileServer) GetUserGroups(context.Context, *pb.UserGroupsRequest) (*pb.UserGroupsResponse, error) {
+	groups := []*pb.Group{
+		{Id: "default-group"},
+	}
+	return &pb.UserGroupsResponse{Groups: groups}, nil
+}
+
+// GetGroupProjects
+// returns every project that appears in the cost records
+func (m costInsightsFileServer) GetGroupProjects(context.Context, *pb.GroupProjectsRequest) (*pb.GroupProjectsResponse, error) {
+	keys, _ := groupFileRecords(m.records, func(r FileCostRecord) string { return r.Project })
+	projects := []*pb.Project{}
+	for _, key := range keys {
+		projects = append(projects, &pb.Project{Id: key})
+	}
+	return &pb.GroupProjectsResponse{Projects: projects}, nil
+}
+
+func (costInsightsFileServer) GetDailyMetricData(ctx context.Context, req *pb.DailyMetricDataRequest) (*pb.DailyMetricDataResponse, error) {
+	cost := pb.DailyMetricDataResponse{}
+	cost.Format = "number"
+	aggregation, err := metrics.GetMetrics(req.Metric, req.Intervals)
+	if err != nil {
+		return &pb.DailyMetricDataResponse{}, err
+	}
+	cost.Aggregation = aggregation
+	cost.Change, err = utils.ChangeOf(aggregation, req.Intervals)
+	if err != nil {
+		return &pb.DailyMetricDataResponse{}, err
+	}
+	trendline, err := utils.TrendlineOf(aggregation)
+	if err != nil {
+		return &pb.DailyMetricDataResponse{}, err
+	}
+	cost.Trendline = trendline
+
+	return &cost, nil
+}
+
+func (m costInsightsFileServer) GetGroupDailyCost(ctx context.Context, req *pb.GroupDailyCostRequest) (*pb.GroupDailyCostResponse, error) {
+	cost := pb.GroupDailyCostResponse{}
+	cost.Format = "number"
+
+	records, _, err := m.fileRecordsFor(req.Intervals, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	aggregation := aggregationForFile(records)
+	cost.Aggregation = aggregation
+	cost.Change, err = utils.ChangeOf(aggregation, req.Intervals)
+	if err != nil {
+		return &pb.GroupDailyCostResponse{}, err
+	}
+	trendline, err := utils.TrendlineOf(aggregation)
+	if err != nil {
+		return &pb.GroupDailyCostResponse{}, err
+	}
+	cost.Trendline = trendline
+
+	cost.GroupedCosts = &pb.GroupedCosts{
+		Product: getGroupedFileProducts(records),
+		Project: getGroupedFileProjects(records),
+	}
+
+	return &cost, nil
+}
+
+func (m costInsightsFileServer) GetProjectDailyCost(ctx context.Context, req *pb.ProjectDailyCostRequest) (*pb.ProjectDailyCostResponse, error) {
+	cost := pb.ProjectDailyCostResponse{}
+	cost.Format = "number"
+
+	records, _, err := m.fileRecordsFor(req.Intervals, func(r FileCostRecord) bool {
+		return req.Project == "" || r.Project == req.Project
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	aggregation := aggregationForFile(records)
+	cost.Aggregation = aggregation
+	cost.Change, err = utils.ChangeOf(aggregation, req.Intervals)
+	if err != nil {
+		return &pb.ProjectDailyCostResponse{}, err
+	}
+	trendline, err := utils.TrendlineOf(aggregation)
+	if err != nil {
+		return &pb.ProjectDailyCostResponse{}, err
+	}
+	cost.Trendline = trendline
+
+	cost.GroupedCosts = &pb.GroupedCosts{
+		Product: getGroupedFileProducts(records),
+	}
+
+	return &cost, nil
+}
+
+// GetProductInsights
+// breaks down the product cost by the record Entity, comparing the buckets of the interval.
+func (m costInsightsFileServer) GetProductInsights(ctx context.Context, req *pb.ProductInsightsRequest) (*pb.Entity, error) {
+	records, interval, err := m.fileRecordsFor(req.Intervals, func(r FileCostRecord) bool {
+		return r.Product == req.Product && (req.Project == "" || r.Project == req.Project)
+	})
+	if err != nil {
+		return nil, err
+	}
+	periods, err := utils.Buckets(interval)
+	if err != nil {
+		return nil, err
+	}
+
+	entity := &pb.Entity{Id: req.Product}
+	keys, groups := groupFileRecords(records, func(r FileCostRecord) string { return r.Entity })
+	entities := []*pb.Entity{}
+	for _, key := range keys {
+		aggregation := make([]float64, len(periods))
+		for _, record := range groups[key] {
+			if bucket := utils.BucketOf(periods, record.Date); bucket >= 0 {
+				aggregation[bucket] += record.Amount
+			}
+		}
+		entities = append(entities, &pb.Entity{
+			Id:          key,
+			Aggregation: aggregation,
+			Change:      utils.ChangeOfEntity(aggregation),
+			Entities:    &pb.Record{},
+		})
+	}
+	entity.Entities = &pb.Record{Service: entities}
+	entity.Aggregation = utils.AggregationOfEntities(entities, len(periods))
+	entity.Change = utils.ChangeOfEntity(entity.Aggregation)
+
+	return entity, nil
+}
+
+func (costInsightsFileServer) GetAlerts(ctx context.Context, req *pb.AlertRequest) (*pb.AlertResponse, error) {
+	return &pb.AlertResponse{Alerts: []*pb.Entity{}}, nil
+}
+
+// SetAlertStatus
+// the file provider raises no alerts
+func (costInsightsFileServer) SetAlertStatus(ctx context.Context, req *pb.AlertStatusRequest) (*pb.AlertStatusResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "the file cost provider has no alerts")
+}
+
+// GetCostForecast
+// forecasts the cost of the records of the project, or of all the records for a group, to the
+// end of the horizon
+func (m costInsightsFileServer) GetCostForecast(ctx context.Context, req *pb.CostForecastRequest) (*pb.CostForecastResponse, error) {
+	w, err := forecastWindowFor(ctx, m, req)
+	if err != nil {
+		return nil, err
+	}
+	records, _, err := m.fileRecordsFor(w.historyIntervals(), func(r FileCostRecord) bool {
+		return req.Project == "" || r.Project == req.Project
+	})
+	if err != nil {
+		return nil, err
+	}
+	id := req.Group
+	if req.Project != "" {
+		id = req.Project
+	}
+	return costForecastOf(id, w, aggregationForFile(records))
+}
+
+// GetProducts
+// returns the products of the records of the project in the intervals, or of all records
+func (m costInsightsFileServer) GetProducts(ctx context.Context, req *pb.ProductsRequest) (*pb.ProductsResponse, error) {
+	filter := func(r FileCostRecord) bool {
+		return req.Project == "" || r.Project == req.Project
+	}
+	records := []FileCostRecord{}
+	if req.Intervals == "" {
+		for _, record := range m.records {
+			if filter(record) {
+				records = append(records, record)
+			}
+		}
+	} else {
+		var err error
+		if records, _, err = m.fileRecordsFor(req.Intervals, filter); err != nil {
+			return nil, err
+		}
+	}
+
+	names := map[string]bool{}
+	for _, record := range records {
+		names[record.Product] = true
+	}
+	resp := &pb.ProductsResponse{Products: []*pb.Product{}}
+	for name := range names {
+		resp.Products = append(resp.Products, &pb.Product{Id: name, Name: name})
+	}
+	sort.Slice(resp.Products, func(i, j int) bool {
+		return resp.Products[i].Id < resp.Products[j].Id
+	})
+	return resp, nil
+}
+
+// GetCommitmentCoverage
+// the cost records of the file have no Savings Plans or reservations
+func (costInsightsFileServer) GetCommitmentCoverage(ctx context.Context, req *pb.CommitmentRequest) (*pb.CommitmentResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "the file cost provider has no commitments")
+}
+
+// GetCommitmentUtilization
+// the cost records of the file have no Savings Plans or reservations
+func (costInsightsFileServer) GetCommitmentUtilization(ctx context.Context, req *pb.CommitmentRequest) (*pb.CommitmentResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "the file cost provider has no commitments")
+}
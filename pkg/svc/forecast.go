@@ -0,0 +1,175 @@
+package svc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/spf13/viper"
+
+	"github.com/seizadi/cost-insights-backend/pkg/pb"
+	"github.com/seizadi/cost-insights-backend/pkg/types"
+	"github.com/seizadi/cost-insights-backend/pkg/utils"
+)
+
+// Horizons of the cost forecast, the periods of the fiscal calendar it forecasts to the end of
+const (
+	MonthHorizon   = "month"
+	QuarterHorizon = "quarter"
+	YearHorizon    = "year"
+)
+
+var horizonMonths = map[string]int{
+	MonthHorizon:   1,
+	QuarterHorizon: 3,
+	YearHorizon:    12,
+}
+
+// forecastWindow
+// is the time frame of a cost forecast, the daily costs from HistoryStart up to and including
+// Date are forecast to the end of the horizon period from Start to the exclusive End.
+type forecastWindow struct {
+	Horizon      string
+	Confidence   float64
+	Date         time.Time
+	Start        time.Time
+	End          time.Time
+	HistoryStart time.Time
+}
+
+// forecastWindowFor
+// returns the window of the forecast request. The last day of actual costs is the last complete
+// billing date of the server unless the request sets one, the history covers the
+// cost.forecast.history_days days up to it and at least the period so far.
+func forecastWindowFor(ctx context.Context, server pb.CostInsightsApiServer, req *pb.CostForecastRequest) (forecastWindow, error) {
+	w := forecastWindow{Horizon: strings.ToLower(req.Horizon), Confidence: req.Confidence}
+	if w.Horizon == "" {
+		w.Horizon = MonthHorizon
+	}
+	months, ok := horizonMonths[w.Horizon]
+	if !ok {
+		return w, errors.New("unknown forecast horizon: " + req.Horizon)
+	}
+	if w.Confidence == 0 {
+		w.Confidence = viper.GetFloat64("cost.forecast.confidence")
+	}
+	if w.Confidence <= 0 || w.Confidence >= 1 {
+		return w, fmt.Errorf("invalid forecast confidence: %v", w.Confidence)
+	}
+
+	date := req.Date
+	if date == "" {
+		billing, err := server.GetLastCompleteBillingDate(ctx, &empty.Empty{})
+		if err != nil {
+			return w, err
+		}
+		date = billing.Date
+	}
+	var err error
+	if w.Date, err = time.Parse(types.DEFAULT_DATE_FORMAT, date); err != nil {
+		return w, err
+	}
+
+	calendar, err := utils.NewFiscalCalendar()
+	if err != nil {
+		return w, err
+	}
+	w.Start, w.End = calendar.PeriodOf(w.Date, months)
+
+	days := viper.GetInt("cost.forecast.history_days")
+	if days < 1 {
+		days = 1
+	}
+	w.HistoryStart = w.Date.AddDate(0, 0, 1-days)
+	if w.Start.Before(w.HistoryStart) {
+		w.HistoryStart = w.Start
+	}
+	return w, nil
+}
+
+// historyEnd
+// returns the exclusive end of the history, the day after the last actual cost
+func (w forecastWindow) historyEnd() string {
+	return w.Date.AddDate(0, 0, 1).Format(types.DEFAULT_DATE_FORMAT)
+}
+
+// historyIntervals
+// returns the history as a repeating interval of a single bucket, for the providers that read
+// their costs by intervals
+func (w forecastWindow) historyIntervals() string {
+	days := int(w.Date.Sub(w.HistoryStart).Hours()/24) + 1
+	return fmt.Sprintf("R1/P%dD/%s", days, w.historyEnd())
+}
+
+// costForecastOf
+// forecasts the daily costs of the history aggregation to the end of the window. Days of the
+// history without costs are forecast from as zero cost days so the weekdays stay aligned.
+func costForecastOf(id string, w forecastWindow, aggregation []*pb.DateAggregation) (*pb.CostForecastResponse, error) {
+	amounts := map[string]float64{}
+	for _, day := range aggregation {
+		amounts[day.Date] += day.Amount
+	}
+
+	resp := &pb.CostForecastResponse{
+		Id:         id,
+		Format:     "number",
+		Horizon:    w.Horizon,
+		StartDate:  w.Start.Format(types.DEFAULT_DATE_FORMAT),
+		EndDate:    w.End.Format(types.DEFAULT_DATE_FORMAT),
+		Confidence: w.Confidence,
+	}
+	series := []float64{}
+	for day := w.HistoryStart; !day.After(w.Date); day = day.AddDate(0, 0, 1) {
+		date := day.Format(types.DEFAULT_DATE_FORMAT)
+		series = append(series, amounts[date])
+		resp.Aggregation = append(resp.Aggregation, &pb.DateAggregation{Date: date, Amount: amounts[date]})
+		if !day.Before(w.Start) {
+			resp.Actual += amounts[date]
+		}
+	}
+
+	days := 0
+	if w.End.After(w.Date) {
+		days = int(w.End.Sub(w.Date).Hours()/24) - 1
+	}
+	forecast, err := utils.ForecastOf(series, days, w.Confidence)
+	if err != nil {
+		return nil, err
+	}
+	resp.Model = forecast.Model
+	for h := 0; h < days; h++ {
+		resp.Forecast = append(resp.Forecast, &pb.ForecastAggregation{
+			Date:   w.Date.AddDate(0, 0, h+1).Format(types.DEFAULT_DATE_FORMAT),
+			Amount: forecast.Amount[h],
+			Lower:  forecast.Lower[h],
+			Upper:  forecast.Upper[h],
+		})
+	}
+	resp.Total = &pb.ForecastAggregation{
+		Date:   w.End.AddDate(0, 0, -1).Format(types.DEFAULT_DATE_FORMAT),
+		Amount: resp.Actual + forecast.Total,
+		Lower:  resp.Actual + forecast.TotalLower,
+		Upper:  resp.Actual + forecast.TotalUpper,
+	}
+	return resp, nil
+}
+
+// roundForecast
+// rounds the forecast amounts to whole numbers, the way cost.round rounds the daily costs
+func roundForecast(resp *pb.CostForecastResponse) {
+	round := func(a *pb.ForecastAggregation) {
+		if a == nil {
+			return
+		}
+		a.Amount, a.Lower, a.Upper = math.Round(a.Amount), math.Round(a.Lower), math.Round(a.Upper)
+	}
+	for _, day := range resp.Forecast {
+		round(day)
+	}
+	round(resp.Total)
+	resp.Actual = math.Round(resp.Actual)
+}
@@ -0,0 +1,257 @@
+package svc
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+
+	"github.com/spf13/viper"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	ceTypes "github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+
+	"github.com/seizadi/cost-insights-backend/pkg/pb"
+)
+
+// DefaultGroup is the only group served when no group mapping is configured
+const DefaultGroup = "default-group"
+
+// AllAccounts is the account of a group that sees the cost of all accounts
+const AllAccounts = "*"
+
+// GroupAccount
+// is an AWS Account (i.e. Project) owned by a group. A project spanning several AWS Accounts
+// lists them in LinkedAccounts, otherwise the project is the account Id.
+type GroupAccount struct {
+	Id             string   `mapstructure:"id"`
+	Name           string   `mapstructure:"name"`
+	LinkedAccounts []string `mapstructure:"linked_accounts"`
+}
+
+// linkedAccounts returns the AWS Accounts of the project
+func (a GroupAccount) linkedAccounts() []string {
+	if len(a.LinkedAccounts) > 0 {
+		return a.LinkedAccounts
+	}
+	return []string{a.Id}
+}
+
+var awsAccountId = regexp.MustCompile(`^\d{12}$`)
+
+// GroupMapping
+// maps a Cost Insights group to its members and the AWS Accounts whose cost it sees. A group
+// sees no accounts unless its accounts list them, or the "*" wildcard for all accounts.
+type GroupMapping struct {
+	Id       string         `mapstructure:"id"`
+	Name     string         `mapstructure:"name"`
+	Users    []string       `mapstructure:"users"`
+	Accounts []GroupAccount `mapstructure:"accounts"`
+}
+
+// GroupDirectory
+// resolves users to groups and groups to AWS Accounts from the cost.groups configuration, e.g.
+//
+//	cost:
+//	  groups:
+//	    - id: platform
+//	      users: [alice, bob]
+//	      accounts:
+//	        - id: "111111111111"
+//	          name: platform-prod
+//	    - id: finance
+//	      users: [carol]
+//	      accounts: ["*"]
+//
+// Without configuration every user belongs to the default group which sees all accounts.
+type GroupDirectory struct {
+	groups []GroupMapping
+}
+
+// NewGroupDirectory
+// returns the group directory loaded from the cost.groups configuration, an account given as a
+// string is the account with that id. Every group must have users.
+func NewGroupDirectory() (*GroupDirectory, error) {
+	var groups []GroupMapping
+	if err := viper.UnmarshalKey("cost.groups", &groups, viper.DecodeHook(groupAccountOfString)); err != nil {
+		return nil, err
+	}
+	for _, group := range groups {
+		if len(group.Users) == 0 {
+			return nil, fmt.Errorf("group %q of cost.groups has no users", group.Id)
+		}
+	}
+	return &GroupDirectory{groups: groups}, nil
+}
+
+// groupAccountOfString
+// decodes an account of a group given as a string, such as the "*" wildcard, into the account
+// with that id
+func groupAccountOfString(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+	if from.Kind() != reflect.String || to != reflect.TypeOf(GroupAccount{}) {
+		return data, nil
+	}
+	return GroupAccount{Id: data.(string)}, nil
+}
+
+// allAccounts returns true when the group sees the cost of all accounts
+func (g GroupMapping) allAccounts() bool {
+	for _, account := range g.Accounts {
+		if account.Id == AllAccounts {
+			return true
+		}
+	}
+	return false
+}
+
+// Configured returns true when a group mapping is configured
+func (d GroupDirectory) Configured() bool {
+	return len(d.groups) > 0
+}
+
+// SeesAllAccounts
+// returns true when the group has the "*" wildcard, or when no mapping is configured. An unknown
+// group sees no accounts.
+func (d GroupDirectory) SeesAllAccounts(group string) bool {
+	g, err := d.groupOf(group)
+	return err == nil && (g == nil || g.allAccounts())
+}
+
+// anySeesAllAccounts returns true when a configured group has the "*" wildcard
+func (d GroupDirectory) anySeesAllAccounts() bool {
+	for _, g := range d.groups {
+		if g.allAccounts() {
+			return true
+		}
+	}
+	return false
+}
+
+// GroupsOf
+// returns the groups the user belongs to
+func (d GroupDirectory) GroupsOf(user string) []*pb.Group {
+	if !d.Configured() {
+		return []*pb.Group{{Id: DefaultGroup}}
+	}
+
+	groups := []*pb.Group{}
+	for _, group := range d.groups {
+		member := false
+		for _, u := range group.Users {
+			if u == user {
+				member = true
+				break
+			}
+		}
+		if member {
+			groups = append(groups, &pb.Group{Id: group.Id})
+		}
+	}
+	return groups
+}
+
+// AccountsOf
+// returns the projects of a group, without the "*" wildcard. It returns no accounts when no
+// mapping is configured and a NotFound status when the group is not configured.
+func (d GroupDirectory) AccountsOf(group string) ([]GroupAccount, error) {
+	g, err := d.groupOf(group)
+	if err != nil || g == nil {
+		return nil, err
+	}
+	accounts := []GroupAccount{}
+	for _, account := range g.Accounts {
+		if account.Id != AllAccounts {
+			accounts = append(accounts, account)
+		}
+	}
+	return accounts, nil
+}
+
+// LinkedAccountsOfGroup
+// returns the AWS Accounts of every project of a group, no accounts, meaning no filter, when no
+// mapping is configured or the group has the "*" wildcard. A group without accounts sees no
+// cost and returns a PermissionDenied status, a group that is not configured a NotFound status.
+func (d GroupDirectory) LinkedAccountsOfGroup(group string) ([]string, error) {
+	g, err := d.groupOf(group)
+	if err != nil || g == nil || g.allAccounts() {
+		return nil, err
+	}
+	if len(g.Accounts) == 0 {
+		return nil, status.Errorf(codes.PermissionDenied, "group %q has no accounts", group)
+	}
+	values := []string{}
+	for _, account := range g.Accounts {
+		values = append(values, account.linkedAccounts()...)
+	}
+	return values, nil
+}
+
+// groupOf
+// returns the mapping of a group, nil when no mapping is configured and a NotFound status when
+// the group is not configured
+func (d GroupDirectory) groupOf(group string) (*GroupMapping, error) {
+	if !d.Configured() {
+		return nil, nil
+	}
+	for i := range d.groups {
+		if d.groups[i].Id == group {
+			return &d.groups[i], nil
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "group %q not found", group)
+}
+
+// LinkedAccountsOf
+// returns the AWS Accounts of a project. Without a mapping any AWS Account Id is a project,
+// otherwise the project must belong to a configured group, or be an AWS Account Id seen by a
+// group with the "*" wildcard. Unknown projects return a NotFound status.
+func (d GroupDirectory) LinkedAccountsOf(project string) ([]string, error) {
+	for _, g := range d.groups {
+		for _, account := range g.Accounts {
+			if account.Id == project {
+				return account.linkedAccounts(), nil
+			}
+		}
+	}
+	if (!d.Configured() || d.anySeesAllAccounts()) && awsAccountId.MatchString(project) {
+		return []string{project}, nil
+	}
+	return nil, status.Errorf(codes.NotFound, "project %q not found", project)
+}
+
+// LinkedAccountsOfGroupProject
+// returns the AWS Accounts of a project of a group, a group with the "*" wildcard or without a
+// mapping sees every project and any AWS Account Id. A project of another group returns a PermissionDenied status, an
+// unknown group or project a NotFound status.
+func (d GroupDirectory) LinkedAccountsOfGroupProject(group string, project string) ([]string, error) {
+	g, err := d.groupOf(group)
+	if err != nil {
+		return nil, err
+	}
+	if g == nil || g.allAccounts() {
+		return d.LinkedAccountsOf(project)
+	}
+	for _, account := range g.Accounts {
+		if account.Id == project {
+			return account.linkedAccounts(), nil
+		}
+	}
+	if _, err := d.LinkedAccountsOf(project); err != nil {
+		return nil, err
+	}
+	return nil, status.Errorf(codes.PermissionDenied, "project %q is not a project of group %q", project, group)
+}
+
+// linkedAccountFilter
+// returns a CostExplorer filter on the given accounts, or nil to query all accounts
+func linkedAccountFilter(values []string) *ceTypes.Expression {
+	if len(values) == 0 {
+		return nil
+	}
+	return &ceTypes.Expression{
+		Dimensions: &ceTypes.DimensionValues{
+			Key:    ceTypes.DimensionLinkedAccount,
+			Values: values,
+		},
+	}
+}
@@ -0,0 +1,271 @@
+package svc
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/spf13/viper"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/seizadi/cost-insights-backend/pkg/accounts"
+	"github.com/seizadi/cost-insights-backend/pkg/pb"
+)
+
+func TestGroupDirectory(t *testing.T) {
+	var tests = []struct {
+		name      string
+		directory *GroupDirectory
+		user      string
+		groups    []string
+	}{
+		{name: "default group without mapping", directory: &GroupDirectory{}, user: "user", groups: []string{DefaultGroup}},
+		{name: "member of one group", directory: testGroupDirectory, user: "user", groups: []string{"platform"}},
+		{name: "not a member", directory: testGroupDirectory, user: "guest", groups: []string{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			groups := test.directory.GroupsOf(test.user)
+			if len(groups) != len(test.groups) {
+				t.Fatalf("Output %v not equal to expected %v", groups, test.groups)
+			}
+			for i, group := range groups {
+				if group.Id != test.groups[i] {
+					t.Errorf("Output %q not equal to expected %q", group.Id, test.groups[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNewGroupDirectory(t *testing.T) {
+	defer viper.Set("cost.groups", nil)
+	viper.Set("cost.groups", []interface{}{
+		map[string]interface{}{"id": "platform", "users": []interface{}{"user"}, "accounts": []interface{}{
+			map[string]interface{}{"id": "111111111111", "name": "platform-prod"},
+		}},
+		map[string]interface{}{"id": "finance", "users": []interface{}{"controller"}, "accounts": []interface{}{"*"}},
+	})
+	directory, err := NewGroupDirectory()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if accounts, err := directory.LinkedAccountsOfGroup("platform"); err != nil || len(accounts) != 1 || accounts[0] != "111111111111" {
+		t.Errorf("Output %v %v not equal to expected the platform account", accounts, err)
+	}
+	if accounts, err := directory.LinkedAccountsOfGroup("finance"); err != nil || accounts != nil {
+		t.Errorf("Output %v %v not equal to expected all accounts", accounts, err)
+	}
+	if projects, err := directory.AccountsOf("finance"); err != nil || len(projects) != 0 {
+		t.Errorf("Output %v %v not equal to expected no projects", projects, err)
+	}
+
+	// Every group must have users
+	viper.Set("cost.groups", []interface{}{map[string]interface{}{"id": "finance", "accounts": []interface{}{"*"}}})
+	if _, err := NewGroupDirectory(); err == nil {
+		t.Errorf("Expected an error for a group without users")
+	}
+}
+
+func TestGroupDailyCostAccountFilter(t *testing.T) {
+	setTestCostConfig()
+	client := &capturingCeClient{}
+	server := costInsightsAwsServer{client: client, groups: testGroupDirectory}
+
+	_, err := server.GetGroupDailyCost(context.Background(), &pb.GroupDailyCostRequest{Group: "platform", Intervals: "R2/P7D/2021-09-01"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(client.inputs) != 3 {
+		t.Fatalf("Output %d queries not equal to expected 3", len(client.inputs))
+	}
+	for _, input := range client.inputs {
+		if input.Filter == nil || input.Filter.Dimensions == nil ||
+			len(input.Filter.Dimensions.Values) != 1 || input.Filter.Dimensions.Values[0] != "111111111111" {
+			t.Errorf("Query is not filtered on the group account: %v", input.Filter)
+		}
+	}
+
+	// A group with the wildcard account sees all accounts
+	client.inputs = nil
+	_, err = server.GetGroupDailyCost(context.Background(), &pb.GroupDailyCostRequest{Group: "finance", Intervals: "R2/P7D/2021-09-01"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, input := range client.inputs {
+		if input.Filter != nil {
+			t.Errorf("Unexpected filter: %v", input.Filter)
+		}
+	}
+
+	// A group without accounts sees no cost
+	client.inputs = nil
+	_, err = server.GetGroupDailyCost(context.Background(), &pb.GroupDailyCostRequest{Group: "audit", Intervals: "R2/P7D/2021-09-01"})
+	if status.Code(err) != codes.PermissionDenied || len(client.inputs) != 0 {
+		t.Errorf("Output %v not equal to expected PermissionDenied without queries", err)
+	}
+
+	_, err = server.GetGroupDailyCost(context.Background(), &pb.GroupDailyCostRequest{Group: "unknown", Intervals: "R2/P7D/2021-09-01"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Output %v not equal to expected NotFound", err)
+	}
+}
+
+func TestProjectAccountFilter(t *testing.T) {
+	setTestCostConfig()
+	client := &capturingCeClient{}
+	server := costInsightsAwsServer{client: client, groups: testGroupDirectory}
+
+	_, err := server.GetProjectDailyCost(context.Background(), &pb.ProjectDailyCostRequest{Project: "data-lake", Intervals: "R2/P7D/2021-09-01"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, input := range client.inputs {
+		if input.Filter == nil || input.Filter.Dimensions == nil || len(input.Filter.Dimensions.Values) != 2 {
+			t.Errorf("Query is not filtered on the project accounts: %v", input.Filter)
+		}
+	}
+
+	client.inputs = nil
+	_, err = server.GetProductInsights(context.Background(), &pb.ProductInsightsRequest{Product: "EC2", Group: "platform", Project: "111111111111", Intervals: "R2/P7D/2021-09-01"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	filter := client.inputs[0].Filter
+	if filter == nil || len(filter.And) != 2 || filter.And[1].Dimensions.Values[0] != "111111111111" {
+		t.Errorf("Query is not filtered on the product and project: %v", filter)
+	}
+
+	var tests = []struct {
+		name      string
+		directory *GroupDirectory
+		group     string
+		project   string
+		code      codes.Code
+	}{
+		{name: "configured project", directory: testGroupDirectory, group: "data", project: "data-lake", code: codes.OK},
+		{name: "unknown project", directory: testGroupDirectory, group: "data", project: "project-a", code: codes.NotFound},
+		{name: "account without mapping", directory: &GroupDirectory{}, project: "111111111111", code: codes.OK},
+		{name: "unknown project without mapping", directory: &GroupDirectory{}, project: "project-a", code: codes.NotFound},
+		{name: "account of a wildcard group", directory: testGroupDirectory, group: "finance", project: "444444444444", code: codes.OK},
+		{name: "account without a wildcard group", directory: &GroupDirectory{groups: testGroupDirectory.groups[:2]}, group: "data", project: "444444444444", code: codes.NotFound},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := costInsightsAwsServer{client: &capturingCeClient{}, groups: test.directory}
+			_, err := server.GetProjectDailyCost(context.Background(), &pb.ProjectDailyCostRequest{Project: test.project, Intervals: "R2/P7D/2021-09-01"})
+			if status.Code(err) != test.code {
+				t.Errorf("Output %v not equal to expected %v", err, test.code)
+			}
+			_, err = server.GetProductInsights(context.Background(), &pb.ProductInsightsRequest{Product: "EC2", Group: test.group, Project: test.project, Intervals: "R2/P7D/2021-09-01"})
+			if status.Code(err) != test.code {
+				t.Errorf("Output %v not equal to expected %v", err, test.code)
+			}
+		})
+	}
+}
+
+func TestProjectOfGroup(t *testing.T) {
+	setTestCostConfig()
+	server := costInsightsAwsServer{client: &capturingCeClient{}, groups: testGroupDirectory, now: testNow}
+	ctx := context.Background()
+
+	// A project of another group is denied
+	_, err := server.GetProductInsights(ctx, &pb.ProductInsightsRequest{Product: "EC2", Group: "platform", Project: "data-lake", Intervals: "R2/P7D/2021-09-01"})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Output %v not equal to expected PermissionDenied", err)
+	}
+	_, err = server.GetCostForecast(ctx, &pb.CostForecastRequest{Group: "platform", Project: "data-lake"})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Output %v not equal to expected PermissionDenied", err)
+	}
+	_, err = server.GetCommitmentCoverage(ctx, &pb.CommitmentRequest{Group: "platform", Project: "data-lake", Intervals: "R2/P7D/2021-09-01"})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Output %v not equal to expected PermissionDenied", err)
+	}
+
+	// A group of all accounts sees every project
+	if accounts, err := testGroupDirectory.LinkedAccountsOfGroupProject("finance", "data-lake"); err != nil || len(accounts) != 2 {
+		t.Errorf("Output %v %v not equal to expected the data-lake accounts", accounts, err)
+	}
+	if _, err := testGroupDirectory.LinkedAccountsOfGroupProject("ops", "data-lake"); status.Code(err) != codes.NotFound {
+		t.Errorf("Output %v not equal to expected NotFound", err)
+	}
+}
+
+func TestWildcardGroupProjects(t *testing.T) {
+	setTestCostConfig()
+	ctx := context.Background()
+
+	var tests = []struct {
+		name      string
+		directory *GroupDirectory
+		group     string
+		projects  []string
+	}{
+		{name: "wildcard group", directory: testGroupDirectory, group: "finance", projects: []string{"111111111111", "222222222222"}},
+		{name: "without mapping or account directory", directory: &GroupDirectory{}, group: DefaultGroup, projects: []string{"111111111111", "222222222222"}},
+		{name: "group of projects", directory: testGroupDirectory, group: "data", projects: []string{"data-lake"}},
+		{name: "group without accounts", directory: testGroupDirectory, group: "audit", projects: []string{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// The accounts of a wildcard group are the linked accounts of Cost Explorer
+			server := costInsightsAwsServer{client: fakeCeClient{}, groups: test.directory, now: testNow}
+			resp, err := server.GetGroupProjects(ctx, &pb.GroupProjectsRequest{Group: test.group})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			projects := []string{}
+			for _, project := range resp.Projects {
+				projects = append(projects, project.Id)
+			}
+			if fmt.Sprint(projects) != fmt.Sprint(test.projects) {
+				t.Errorf("Output %v not equal to expected %v", projects, test.projects)
+			}
+		})
+	}
+
+	// Any account is a project of a wildcard group, only its own of another group
+	if accounts, err := testGroupDirectory.LinkedAccountsOfGroupProject("finance", "444444444444"); err != nil || len(accounts) != 1 || accounts[0] != "444444444444" {
+		t.Errorf("Output %v %v not equal to expected the account", accounts, err)
+	}
+	if _, err := testGroupDirectory.LinkedAccountsOfGroupProject("platform", "444444444444"); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Output %v not equal to expected PermissionDenied", err)
+	}
+	if _, err := testGroupDirectory.LinkedAccountsOfGroupProject("finance", "project-a"); status.Code(err) != codes.NotFound {
+		t.Errorf("Output %v not equal to expected NotFound", err)
+	}
+}
+
+func TestAccountDirectoryNames(t *testing.T) {
+	setTestCostConfig()
+	directory := accounts.NewMemoryDirectory([]accounts.Account{
+		{Id: "111111111111", Name: "platform-prod"},
+		{Id: "222222222222", Name: "platform-dev"},
+	})
+
+	// Without a group mapping the default group lists the accounts of the directory
+	server := costInsightsAwsServer{client: fakeCeClient{}, groups: &GroupDirectory{}, accounts: directory}
+	resp, err := server.GetGroupProjects(context.Background(), &pb.GroupProjectsRequest{Group: DefaultGroup})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(resp.Projects) != 2 || resp.Projects[1].Id != "222222222222" || resp.Projects[1].Name != "platform-dev" {
+		t.Errorf("Output %v not equal to the directory accounts", resp.Projects)
+	}
+
+	// The project breakdown of the group cost keeps the account Ids and shows the account names
+	cost, err := server.GetGroupDailyCost(context.Background(), &pb.GroupDailyCostRequest{Group: DefaultGroup, Intervals: "R2/P7D/2021-09-01"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	names := map[string]string{}
+	for _, project := range cost.GroupedCosts.Project {
+		names[project.Id] = project.Name
+	}
+	if names["111111111111"] != "platform-prod" || names["222222222222"] != "platform-dev" {
+		t.Errorf("Output %v does not show the account names", names)
+	}
+}
@@ -0,0 +1,188 @@
+package svc
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	ceTypes "github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+	"github.com/spf13/viper"
+
+	"github.com/seizadi/cost-insights-backend/pkg/pb"
+	"github.com/seizadi/cost-insights-backend/pkg/types"
+)
+
+// Kinds of the nested entities of the breakdown of a product
+const (
+	SKUEntities        = "sku"
+	DeploymentEntities = "deployment"
+)
+
+// resourceHistoryDays is the number of days before today GetCostAndUsageWithResources serves
+const resourceHistoryDays = 14
+
+// ProductBreakdown
+// is how GetProductInsights breaks down the cost of a product, configured in
+// cost.insights.products by product id, e.g.
+//
+//	cost:
+//	  insights:
+//	    group_by: tag:Product
+//	    products:
+//	      EC2:
+//	        group_by: INSTANCE_TYPE
+//	        sub_group_by: RESOURCE_ID
+//	        sub_entities: deployment
+//	      S3:
+//	        group_by: cost_category:Team
+//	        sub_group_by: USAGE_TYPE
+//
+// GroupBy is a Cost Explorer dimension such as USAGE_TYPE, OPERATION, INSTANCE_TYPE or REGION,
+// tag:key for a cost allocation tag or cost_category:name for a cost category,
+// cost.insights.group_by by default. The entities of the product are its GroupBy values, with a
+// SubGroupBy the cost of every entity is broken down further into its SKU entities, or its
+// deployment entities with a SubEntities of deployment. Cost Explorer serves the costs by
+// RESOURCE_ID only for the last 14 days and with resource-level data enabled, RESOURCE_ID is only
+// a SubGroupBy and its entities hold the cost of the part of the intervals in the last 14 days.
+type ProductBreakdown struct {
+	GroupBy     string `mapstructure:"group_by"`
+	SubGroupBy  string `mapstructure:"sub_group_by"`
+	SubEntities string `mapstructure:"sub_entities"`
+}
+
+// productBreakdownOf
+// returns the breakdown of the product from cost.insights.products, or the cost.insights.group_by
+// breakdown. Viper lowercases the keys of the configuration, the product id is matched
+// regardless of case.
+func productBreakdownOf(product string) (ProductBreakdown, error) {
+	var breakdowns map[string]ProductBreakdown
+	if err := viper.UnmarshalKey("cost.insights.products", &breakdowns); err != nil {
+		return ProductBreakdown{}, err
+	}
+	breakdown := breakdowns[strings.ToLower(product)]
+	if breakdown.GroupBy == "" {
+		breakdown.GroupBy = viper.GetString("cost.insights.group_by")
+	}
+	if breakdown.SubEntities == "" {
+		breakdown.SubEntities = SKUEntities
+	}
+	breakdown.SubEntities = strings.ToLower(breakdown.SubEntities)
+	if breakdown.SubEntities != SKUEntities && breakdown.SubEntities != DeploymentEntities {
+		return breakdown, fmt.Errorf("sub_entities of the insights of product %s is not sku or deployment: %s", product, breakdown.SubEntities)
+	}
+	if strings.EqualFold(breakdown.GroupBy, string(ceTypes.DimensionResourceId)) {
+		return breakdown, fmt.Errorf("group_by of the insights of product %s is RESOURCE_ID, which Cost Explorer only serves for the last %d days, use it as the sub_group_by", product, resourceHistoryDays)
+	}
+	return breakdown, nil
+}
+
+// ValidateProductBreakdowns
+// returns an error when the breakdown of a product in cost.insights.products, or the
+// cost.insights.group_by breakdown, is invalid, so that the server fails at startup instead of
+// every GetProductInsights call
+func ValidateProductBreakdowns() error {
+	var breakdowns map[string]ProductBreakdown
+	if err := viper.UnmarshalKey("cost.insights.products", &breakdowns); err != nil {
+		return err
+	}
+	products := []string{""}
+	for product := range breakdowns {
+		products = append(products, product)
+	}
+	for _, product := range products {
+		breakdown, err := productBreakdownOf(product)
+		if err != nil {
+			return err
+		}
+		if _, err := breakdown.groupBy(); err != nil {
+			return fmt.Errorf("insights of product %s: %v", product, err)
+		}
+	}
+	return nil
+}
+
+// groupBy
+// returns the Cost Explorer groups of the breakdown, the sub group second when it is set
+func (b ProductBreakdown) groupBy() ([]ceTypes.GroupDefinition, error) {
+	groups := []ceTypes.GroupDefinition{}
+	for _, spec := range []string{b.GroupBy, b.SubGroupBy} {
+		if spec == "" {
+			continue
+		}
+		group, err := insightGroupOf(spec)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, group)
+	}
+	if len(groups) == 0 {
+		return nil, errors.New("product insights without a group_by")
+	}
+	return groups, nil
+}
+
+// subEntities
+// returns the nested entities of a record filled by the sub group of the breakdown, nil
+// without a sub group
+func (b ProductBreakdown) subEntities() func(*pb.Record) *[]*pb.Entity {
+	switch {
+	case b.SubGroupBy == "":
+		return nil
+	case b.SubEntities == DeploymentEntities:
+		return func(r *pb.Record) *[]*pb.Entity { return &r.Deployment }
+	}
+	return func(r *pb.Record) *[]*pb.Entity { return &r.SKU }
+}
+
+// insightGroupOf
+// returns the Cost Explorer group of a tag:key, a cost_category:name or a dimension
+func insightGroupOf(spec string) (ceTypes.GroupDefinition, error) {
+	if i := strings.Index(spec, ":"); i >= 0 {
+		key := spec[i+1:]
+		if key == "" {
+			return ceTypes.GroupDefinition{}, errors.New("insights group without a key: " + spec)
+		}
+		switch strings.ToLower(spec[:i]) {
+		case "tag":
+			return ceTypes.GroupDefinition{Key: &key, Type: ceTypes.GroupDefinitionTypeTag}, nil
+		case "cost_category":
+			return ceTypes.GroupDefinition{Key: &key, Type: ceTypes.GroupDefinitionTypeCostCategory}, nil
+		}
+		return ceTypes.GroupDefinition{}, errors.New("unknown insights group type, not tag or cost_category: " + spec)
+	}
+	key := strings.ToUpper(spec)
+	for _, dimension := range ceTypes.Dimension("").Values() {
+		if string(dimension) == key {
+			return ceTypes.GroupDefinition{Key: &key, Type: ceTypes.GroupDefinitionTypeDimension}, nil
+		}
+	}
+	return ceTypes.GroupDefinition{}, errors.New("unknown insights group dimension: " + spec)
+}
+
+// groupsByResource
+// returns true when the groups include RESOURCE_ID, which only GetCostAndUsageWithResources
+// answers for the last 14 days
+func groupsByResource(groups []ceTypes.GroupDefinition) bool {
+	for _, group := range groups {
+		if group.Type == ceTypes.GroupDefinitionTypeDimension && group.Key != nil && *group.Key == string(ceTypes.DimensionResourceId) {
+			return true
+		}
+	}
+	return false
+}
+
+// resourceWindow
+// returns the part of the period in the last resourceHistoryDays days before now, the costs
+// GetCostAndUsageWithResources serves, nil when the period ends before
+func resourceWindow(period *ceTypes.DateInterval, now time.Time) *ceTypes.DateInterval {
+	start := now.UTC().AddDate(0, 0, -resourceHistoryDays).Format(types.DEFAULT_DATE_FORMAT)
+	if *period.Start > start {
+		start = *period.Start
+	}
+	end := *period.End
+	if start >= end {
+		return nil
+	}
+	return &ceTypes.DateInterval{Start: &start, End: &end}
+}
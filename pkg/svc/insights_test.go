@@ -0,0 +1,121 @@
+package svc
+
+import (
+	"context"
+	"testing"
+
+	ceTypes "github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+	"github.com/spf13/viper"
+
+	"github.com/seizadi/cost-insights-backend/pkg/pb"
+)
+
+func TestInsightGroupOf(t *testing.T) {
+	var tests = []struct {
+		spec         string
+		expectedKey  string
+		expectedType ceTypes.GroupDefinitionType
+	}{
+		{"tag:Product", "Product", ceTypes.GroupDefinitionTypeTag},
+		{"cost_category:Team", "Team", ceTypes.GroupDefinitionTypeCostCategory},
+		{"usage_type", "USAGE_TYPE", ceTypes.GroupDefinitionTypeDimension},
+		{"RESOURCE_ID", "RESOURCE_ID", ceTypes.GroupDefinitionTypeDimension},
+	}
+	for _, test := range tests {
+		group, err := insightGroupOf(test.spec)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if *group.Key != test.expectedKey || group.Type != test.expectedType {
+			t.Errorf("Output %s %s not equal to expected %s %s", group.Type, *group.Key, test.expectedType, test.expectedKey)
+		}
+	}
+	for _, spec := range []string{"tag:", "label:team", "INSTANCE_SIZE"} {
+		if _, err := insightGroupOf(spec); err == nil {
+			t.Errorf("Expected error for group %s", spec)
+		}
+	}
+}
+
+func TestValidateProductBreakdowns(t *testing.T) {
+	setTestCostConfig()
+	defer setTestCostConfig()
+	if err := ValidateProductBreakdowns(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var tests = []map[string]interface{}{
+		{"group_by": "RESOURCE_ID"},
+		{"group_by": "INSTANCE_SIZE"},
+		{"group_by": "REGION", "sub_group_by": "tag:"},
+		{"group_by": "REGION", "sub_entities": "resource"},
+	}
+	for _, test := range tests {
+		viper.Set("cost.insights.products", map[string]interface{}{"ec2": test})
+		if err := ValidateProductBreakdowns(); err == nil {
+			t.Errorf("Expected error for breakdown %v", test)
+		}
+	}
+}
+
+func TestGetProductInsightsBreakdown(t *testing.T) {
+	setTestCostConfig()
+	client := &capturingCeClient{}
+	server := costInsightsAwsServer{client: client, groups: testGroupDirectory, now: testNow}
+
+	// The configured products are matched regardless of case, the intervals are in the last 14
+	// days of the costs by resource
+	entity, err := server.GetProductInsights(context.Background(), &pb.ProductInsightsRequest{Product: "S3", Group: "platform", Intervals: "R2/P7D/2021-10-15"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(entity.Entities.Service) != 2 || entity.Entities.Service[0].Id != "m5.large" {
+		t.Fatalf("Unexpected instance type entities %v", entity.Entities.Service)
+	}
+	for _, service := range entity.Entities.Service {
+		deployments := service.Entities.Deployment
+		if len(deployments) != 2 || deployments[0].Id != "i-0a1b2c3d" || len(service.Entities.SKU) != 0 {
+			t.Errorf("Unexpected resource entities of %s: %v", service.Id, service.Entities)
+		}
+		for _, deployment := range deployments {
+			if deployment.Aggregation[0] == 0 || deployment.Aggregation[1] == 0 {
+				t.Errorf("Output %v resource cost of %s not in both intervals", deployment.Aggregation, deployment.Id)
+			}
+		}
+	}
+
+	// The entities of longer intervals cover the intervals and their resources the last 14 days
+	client.inputs = nil
+	entity, err = server.GetProductInsights(context.Background(), &pb.ProductInsightsRequest{Product: "S3", Group: "platform", Intervals: "R2/P30D/2021-10-15"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(client.inputs) != 1 || *client.inputs[0].TimePeriod.Start != "2021-08-16" || len(client.inputs[0].GroupBy) != 1 {
+		t.Errorf("Unexpected instance type query %v", client.inputs)
+	}
+	for _, service := range entity.Entities.Service {
+		deployments := service.Entities.Deployment
+		if len(deployments) != 2 || deployments[0].Aggregation[0] != 0 || deployments[0].Aggregation[1] == 0 {
+			t.Errorf("Unexpected resource entities of %s outside of the last 14 days: %v", service.Id, deployments)
+		}
+	}
+
+	// Intervals before the last 14 days have no resources
+	entity, err = server.GetProductInsights(context.Background(), &pb.ProductInsightsRequest{Product: "S3", Group: "platform", Intervals: "R2/P7D/2021-09-01"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(entity.Entities.Service) != 2 || len(entity.Entities.Service[0].Entities.Deployment) != 0 {
+		t.Errorf("Unexpected resource entities before the last 14 days: %v", entity.Entities.Service)
+	}
+
+	// Other products are broken down by cost.insights.group_by without nested entities
+	viper.Set("cost.insights.group_by", "REGION")
+	defer viper.Set("cost.insights.group_by", "tag:Product")
+	if _, err := server.GetProductInsights(context.Background(), &pb.ProductInsightsRequest{Product: "EC2", Group: "platform", Intervals: "R2/P7D/2021-10-01"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	groupBy := client.inputs[len(client.inputs)-1].GroupBy
+	if len(groupBy) != 1 || *groupBy[0].Key != "REGION" || groupBy[0].Type != ceTypes.GroupDefinitionTypeDimension {
+		t.Errorf("Unexpected group by %v", groupBy)
+	}
+}
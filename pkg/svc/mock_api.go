@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/seizadi/cost-insights-backend/pkg/pb"
 	"github.com/seizadi/cost-insights-backend/pkg/types"
@@ -23,10 +25,14 @@ type costInsightsMockServer struct{}
 
 // NewCostInsightsApiMockServer
 // returns an instance of the default server interface
-func NewCostInsightsApiMockServer() (pb.CostInsightsApiServer, error) {
+func NewCostInsightsApiMockServer() (CostProvider, error) {
 	return &costInsightsMockServer{}, nil
 }
 
+func (costInsightsMockServer) Name() string {
+	return MockCostProvider
+}
+
 // GetLastCompleteBillingDate
 // returns the most current date for which billing data is complete, in YYYY-MM-DD format. This helps
 // define the intervals used in other API methods to avoid showing incomplete cost. The costs for
@@ -97,7 +103,10 @@ func (costInsightsMockServer) GetGroupDailyCost(ctx context.Context, req *pb.Gro
 		return &pb.GroupDailyCostResponse{}, err
 	}
 	cost.Aggregation = aggregation
-	cost.Change = utils.ChangeOf(aggregation)
+	cost.Change, err = utils.ChangeOf(aggregation, req.Intervals)
+	if err != nil {
+		return &pb.GroupDailyCostResponse{}, err
+	}
 	trendline, err := utils.TrendlineOf(aggregation)
 	if err != nil {
 		return &pb.GroupDailyCostResponse{}, err
@@ -141,7 +150,10 @@ func (costInsightsMockServer) GetDailyMetricData(ctx context.Context, req *pb.Da
 		return &pb.DailyMetricDataResponse{}, err
 	}
 	cost.Aggregation = aggregation
-	cost.Change = utils.ChangeOf(aggregation)
+	cost.Change, err = utils.ChangeOf(aggregation, req.Intervals)
+	if err != nil {
+		return &pb.DailyMetricDataResponse{}, err
+	}
 	trendline, err := utils.TrendlineOf(aggregation)
 	if err != nil {
 		return &pb.DailyMetricDataResponse{}, err
@@ -176,7 +188,10 @@ func (costInsightsMockServer) GetProjectDailyCost(ctx context.Context, req *pb.P
 		return &pb.ProjectDailyCostResponse{}, err
 	}
 	cost.Aggregation = aggregation
-	cost.Change = utils.ChangeOf(aggregation)
+	cost.Change, err = utils.ChangeOf(aggregation, req.Intervals)
+	if err != nil {
+		return &pb.ProjectDailyCostResponse{}, err
+	}
 	trendline, err := utils.TrendlineOf(aggregation)
 	if err != nil {
 		return &pb.ProjectDailyCostResponse{}, err
@@ -224,7 +239,6 @@ func (costInsightsMockServer) GetProductInsights(ctx context.Context, req *pb.Pr
 	default:
 		return &pb.Entity{}, errors.New("failed to get insights for " + req.Product + " product must match product property in configuration(app-info.yaml)")
 	}
-	return &pb.Entity{}, nil
 }
 
 // GetAlerts
@@ -237,3 +251,51 @@ func (costInsightsMockServer) GetProductInsights(ctx context.Context, req *pb.Pr
 func (costInsightsMockServer) GetAlerts(ctx context.Context, req *pb.AlertRequest) (*pb.AlertResponse, error) {
 	return &pb.AlertResponse{Alerts: utils.MockAlerts()}, nil
 }
+
+// SetAlertStatus
+// the mock alerts are fixed and have no status
+func (costInsightsMockServer) SetAlertStatus(ctx context.Context, req *pb.AlertStatusRequest) (*pb.AlertStatusResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "the mock cost provider does not keep alert statuses")
+}
+
+// GetCostForecast
+// forecasts the mock group cost to the end of the horizon
+func (m costInsightsMockServer) GetCostForecast(ctx context.Context, req *pb.CostForecastRequest) (*pb.CostForecastResponse, error) {
+	w, err := forecastWindowFor(ctx, m, req)
+	if err != nil {
+		return nil, err
+	}
+	aggregation, err := utils.AggregationFor(w.historyIntervals(), types.GROUP_COST)
+	if err != nil {
+		return nil, err
+	}
+	id := req.Group
+	if req.Project != "" {
+		id = req.Project
+	}
+	return costForecastOf(id, w, aggregation)
+}
+
+// GetProducts
+// returns the mock products of GetProductInsights
+func (costInsightsMockServer) GetProducts(ctx context.Context, req *pb.ProductsRequest) (*pb.ProductsResponse, error) {
+	return &pb.ProductsResponse{Products: []*pb.Product{
+		{Id: "bigQuery", Name: "BigQuery"},
+		{Id: "cloudDataflow", Name: "Cloud Dataflow"},
+		{Id: "cloudStorage", Name: "Cloud Storage"},
+		{Id: "computeEngine", Name: "Compute Engine"},
+		{Id: "events", Name: "Events"},
+	}}, nil
+}
+
+// GetCommitmentCoverage
+// the mock accounts have no Savings Plans or reservations
+func (costInsightsMockServer) GetCommitmentCoverage(ctx context.Context, req *pb.CommitmentRequest) (*pb.CommitmentResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "the mock cost provider has no commitments")
+}
+
+// GetCommitmentUtilization
+// the mock accounts have no Savings Plans or reservations
+func (costInsightsMockServer) GetCommitmentUtilization(ctx context.Context, req *pb.CommitmentRequest) (*pb.CommitmentResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "the mock cost provider has no commitments")
+}
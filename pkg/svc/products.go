@@ -0,0 +1,140 @@
+package svc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	ceTypes "github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/seizadi/cost-insights-backend/pkg/cache"
+	"github.com/seizadi/cost-insights-backend/pkg/pb"
+)
+
+// productCatalogSize is the number of periods and account scopes whose products are cached
+const productCatalogSize = 256
+
+// productCatalog
+// caches the products discovered from the Cost Explorer services with cost in a period, for
+// cost.products.ttl, so that resolving the product of every GetProductInsights call does not
+// query Cost Explorer
+type productCatalog struct {
+	cache cache.Cache
+	ttl   time.Duration
+}
+
+// newProductCatalog
+// returns a catalog caching the discovered products in memory for cost.products.ttl
+func newProductCatalog() *productCatalog {
+	return &productCatalog{cache: cache.NewLRUCache(productCatalogSize), ttl: viper.GetDuration("cost.products.ttl")}
+}
+
+// products
+// returns the products of the key from the cache, or discovers and caches them. A nil catalog
+// discovers the products of every call.
+func (c *productCatalog) products(ctx context.Context, key string, discover func() ([]*pb.Product, error)) ([]*pb.Product, error) {
+	if c == nil {
+		return discover()
+	}
+	if data, ok, err := c.cache.Get(ctx, key); err == nil && ok {
+		products := []*pb.Product{}
+		if err := json.Unmarshal(data, &products); err == nil {
+			return products, nil
+		}
+	}
+	products, err := discover()
+	if err != nil {
+		return nil, err
+	}
+	if data, err := json.Marshal(products); err == nil {
+		_ = c.cache.Set(ctx, key, data, c.ttl)
+	}
+	return products, nil
+}
+
+// productIdOf
+// returns the stable short id of a Cost Explorer service, its AWS_SERVICE name or else the words
+// of the service without the Amazon or AWS prefix, e.g. SimpleEmailService for Amazon Simple
+// Email Service
+func productIdOf(service string) string {
+	for id, name := range AWS_SERVICE {
+		if name == service {
+			return id
+		}
+	}
+	name := strings.TrimPrefix(strings.TrimPrefix(service, "Amazon "), "AWS ")
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	id := ""
+	for _, word := range words {
+		id += strings.ToUpper(word[:1]) + word[1:]
+	}
+	if id == "" {
+		return service
+	}
+	return id
+}
+
+// discoverProducts
+// returns the products of the Cost Explorer services with cost in the period for the linked
+// accounts, or for all accounts without linked accounts, sorted by id
+func (m costInsightsAwsServer) discoverProducts(ctx context.Context, period *ceTypes.DateInterval, linkedAccounts []string) ([]*pb.Product, error) {
+	key := fmt.Sprintf("products:%s:%s:%s", *period.Start, *period.End, strings.Join(linkedAccounts, ","))
+	return m.catalog.products(ctx, key, func() ([]*pb.Product, error) {
+		params := &costexplorer.GetDimensionValuesInput{
+			TimePeriod: period,
+			Dimension:  ceTypes.DimensionService,
+			Context:    ceTypes.ContextCostAndUsage,
+			Filter:     linkedAccountFilter(linkedAccounts),
+		}
+		products := []*pb.Product{}
+		for {
+			resp, err := m.client.GetDimensionValues(ctx, params)
+			if err != nil {
+				return nil, err
+			}
+			for _, value := range resp.DimensionValues {
+				if value.Value == nil || *value.Value == "" {
+					continue
+				}
+				products = append(products, &pb.Product{Id: productIdOf(*value.Value), Name: *value.Value})
+			}
+			if resp.NextPageToken == nil || *resp.NextPageToken == "" {
+				break
+			}
+			params.NextPageToken = resp.NextPageToken
+		}
+		sort.Slice(products, func(i, j int) bool {
+			return products[i].Id < products[j].Id
+		})
+		return products, nil
+	})
+}
+
+// productService
+// returns the Cost Explorer service of a product, the id or service of a product discovered in the
+// period for the linked accounts or else an AWS_SERVICE name of a service without cost
+func (m costInsightsAwsServer) productService(ctx context.Context, product string, period *ceTypes.DateInterval, linkedAccounts []string) (string, error) {
+	products, err := m.discoverProducts(ctx, period, linkedAccounts)
+	if err != nil {
+		return "", err
+	}
+	for _, p := range products {
+		if p.Id == product || p.Name == product {
+			return p.Name, nil
+		}
+	}
+	if service, ok := AWS_SERVICE[product]; ok {
+		return service, nil
+	}
+	return "", status.Errorf(codes.NotFound, "unknown product %s, not one of the products of GetProducts", product)
+}
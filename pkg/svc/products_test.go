@@ -0,0 +1,88 @@
+package svc
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/seizadi/cost-insights-backend/pkg/pb"
+)
+
+func TestProductIdOf(t *testing.T) {
+	var tests = []struct {
+		service  string
+		expected string
+	}{
+		{"Amazon Elastic Compute Cloud - Compute", "EC2"},
+		{"Amazon Elastic MapReduce", "EMR"},
+		{"AWS CloudTrail", "CloudTrail"},
+		{"Amazon OpenSearch Service", "ElasticSearch"},
+		{"Amazon Simple Email Service", "SimpleEmailService"},
+		{"AWS Key Management Service", "KeyManagementService"},
+		{"AmazonCloudWatch", "AmazonCloudWatch"},
+		{"Savings Plans for AWS Compute usage", "SavingsPlansForAWSComputeUsage"},
+		{"Tax", "Tax"},
+	}
+	for _, test := range tests {
+		if id := productIdOf(test.service); id != test.expected {
+			t.Errorf("Output %s not equal to expected %s for %s", id, test.expected, test.service)
+		}
+	}
+}
+
+func TestGetProductInsightsOfDiscoveredProduct(t *testing.T) {
+	setTestCostConfig()
+	client := &capturingCeClient{}
+	server := costInsightsAwsServer{client: client, groups: testGroupDirectory, catalog: newProductCatalog(), now: testNow}
+	ctx := context.Background()
+
+	req := &pb.ProductInsightsRequest{Product: "SimpleEmailService", Group: "data", Project: "data-lake", Intervals: "R2/P7D/2021-09-01"}
+	if _, err := server.GetProductInsights(ctx, req); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	filter := client.inputs[0].Filter
+	if services := fakeCeFilterValues(filter, "SERVICE", nil); len(services) != 1 || services[0] != "Amazon Simple Email Service" {
+		t.Errorf("Output %v services not equal to expected Amazon Simple Email Service", services)
+	}
+
+	// The service is not used by the accounts of the platform group
+	req = &pb.ProductInsightsRequest{Product: "SimpleEmailService", Group: "platform", Intervals: "R2/P7D/2021-09-01"}
+	if _, err := server.GetProductInsights(ctx, req); status.Code(err) != codes.NotFound {
+		t.Errorf("Output error %v not equal to expected NotFound", err)
+	}
+
+	// The AWS_SERVICE name of a service without cost in the period
+	req = &pb.ProductInsightsRequest{Product: "CloudTrail", Group: "platform", Intervals: "R2/P7D/2021-09-01"}
+	if _, err := server.GetProductInsights(ctx, req); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	filter = client.inputs[len(client.inputs)-1].Filter
+	if services := fakeCeFilterValues(filter, "SERVICE", nil); len(services) != 1 || services[0] != "AWS CloudTrail" {
+		t.Errorf("Output %v services not equal to expected AWS CloudTrail", services)
+	}
+}
+
+func TestGetProductsOfGroup(t *testing.T) {
+	setTestCostConfig()
+	server := costInsightsAwsServer{client: fakeCeClient{}, groups: testGroupDirectory, catalog: newProductCatalog(), now: testNow}
+	ctx := context.Background()
+
+	var tests = []struct {
+		req      *pb.ProductsRequest
+		expected codes.Code
+	}{
+		{&pb.ProductsRequest{Group: "data", Intervals: "R1/P30D/2021-09-01"}, codes.OK},
+		{&pb.ProductsRequest{Group: "data", Project: "data-lake", Intervals: "R1/P30D/2021-09-01"}, codes.OK},
+		{&pb.ProductsRequest{Group: "finance", Project: "data-lake", Intervals: "R1/P30D/2021-09-01"}, codes.OK},
+		{&pb.ProductsRequest{Intervals: "R1/P30D/2021-09-01"}, codes.InvalidArgument},
+		{&pb.ProductsRequest{Project: "data-lake", Intervals: "R1/P30D/2021-09-01"}, codes.InvalidArgument},
+		{&pb.ProductsRequest{Group: "platform", Project: "data-lake", Intervals: "R1/P30D/2021-09-01"}, codes.PermissionDenied},
+	}
+	for _, test := range tests {
+		if _, err := server.GetProducts(ctx, test.req); status.Code(err) != test.expected {
+			t.Errorf("Output error %v not equal to expected %s for %v", err, test.expected, test.req)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package svc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/spf13/viper"
+
+	"github.com/seizadi/cost-insights-backend/pkg/cur"
+	"github.com/seizadi/cost-insights-backend/pkg/pb"
+	"github.com/seizadi/cost-insights-backend/pkg/store"
+	"github.com/seizadi/cost-insights-backend/pkg/utils"
+)
+
+// Names of the cost providers that can be selected with the cost.provider configuration
+const (
+	AwsCostProvider       = "aws"
+	MockCostProvider      = "mock"
+	FileCostProvider      = "file"
+	CurCostProvider       = "cur"
+	CompositeCostProvider = "composite"
+)
+
+// CostProvider
+// is a backend that serves the Cost Insights API. Every provider implements the complete
+// CostInsightsApiServer interface so it can be registered with the gRPC server directly.
+type CostProvider interface {
+	pb.CostInsightsApiServer
+
+	// Name returns the cost.provider value that selects this backend
+	Name() string
+}
+
+// NewCostProvider
+// returns the cost provider selected by name, one of aws, mock, file, cur or composite. s is the
+// store of the database.* configuration shared by the providers, nil when database.enable is
+// false. An invalid cost.fiscal calendar fails every provider, so that the server fails at
+// startup instead of serving calendar quarters.
+func NewCostProvider(name string, s store.Store) (CostProvider, error) {
+	if _, err := utils.NewFiscalCalendar(); err != nil {
+		return nil, err
+	}
+	switch name {
+	case AwsCostProvider:
+		return NewCostInsightsApiAwsServer(s)
+	case MockCostProvider:
+		return NewCostInsightsApiMockServer()
+	case FileCostProvider:
+		return NewCostInsightsApiFileServer(viper.GetString("cost.file.path"))
+	case CurCostProvider:
+		source, err := cur.NewSource(viper.GetString("cost.cur.path"), viper.GetString("cost.cur.endpoint"), viper.GetString("cost.cur.region"))
+		if err != nil {
+			return nil, err
+		}
+		return NewCostInsightsApiCurServer(source, viper.GetString("cost.cur.cost_column"), viper.GetDuration("cost.cur.ttl"))
+	case CompositeCostProvider:
+		return NewCostInsightsApiCompositeServer(s)
+	}
+	return nil, errors.New("unknown cost provider: " + name)
+}
+
+// costInsightsCompositeServer
+// delegates each Cost Insights API call to the provider configured for it, so that for example
+// alerts can come from the mock backend while costs come from AWS.
+type costInsightsCompositeServer struct {
+	billingDate CostProvider
+	userGroups  CostProvider
+	projects    CostProvider
+	metricData  CostProvider
+	groupCost   CostProvider
+	projectCost CostProvider
+	insights    CostProvider
+	alerts      CostProvider
+	forecast    CostProvider
+	commitments CostProvider
+}
+
+// NewCostInsightsApiCompositeServer
+// returns a composite provider built from the cost.composite.* configuration. Each key names the
+// provider for one API call and falls back to cost.composite.default when it is not set.
+func NewCostInsightsApiCompositeServer(s store.Store) (CostProvider, error) {
+	providers := map[string]CostProvider{}
+	providerFor := func(key string) (CostProvider, error) {
+		name := viper.GetString("cost.composite." + key)
+		if name == "" {
+			name = viper.GetString("cost.composite.default")
+		}
+		if name == CompositeCostProvider {
+			return nil, errors.New("composite cost provider can not delegate to itself: cost.composite." + key)
+		}
+		if provider, ok := providers[name]; ok {
+			return provider, nil
+		}
+		provider, err := NewCostProvider(name, s)
+		if err != nil {
+			return nil, err
+		}
+		providers[name] = provider
+		return provider, nil
+	}
+
+	m := costInsightsCompositeServer{}
+	for key, target := range map[string]*CostProvider{
+		"billing_date": &m.billingDate,
+		"user_groups":  &m.userGroups,
+		"projects":     &m.projects,
+		"metric_data":  &m.metricData,
+		"group_cost":   &m.groupCost,
+		"project_cost": &m.projectCost,
+		"insights":     &m.insights,
+		"alerts":       &m.alerts,
+		"forecast":     &m.forecast,
+		"commitments":  &m.commitments,
+	} {
+		provider, err := providerFor(key)
+		if err != nil {
+			return nil, err
+		}
+		*target = provider
+	}
+
+	return &m, nil
+}
+
+func (costInsightsCompositeServer) Name() string {
+	return CompositeCostProvider
+}
+
+func (m costInsightsCompositeServer) GetLastCompleteBillingDate(ctx context.Context, req *empty.Empty) (*pb.LastCompleteBillingDateResponse, error) {
+	return m.billingDate.GetLastCompleteBillingDate(ctx, req)
+}
+
+func (m costInsightsCompositeServer) GetUserGroups(ctx context.Context, req *pb.UserGroupsRequest) (*pb.UserGroupsResponse, error) {
+	return m.userGroups.GetUserGroups(ctx, req)
+}
+
+func (m costInsightsCompositeServer) GetGroupProjects(ctx context.Context, req *pb.GroupProjectsRequest) (*pb.GroupProjectsResponse, error) {
+	return m.projects.GetGroupProjects(ctx, req)
+}
+
+func (m costInsightsCompositeServer) GetDailyMetricData(ctx context.Context, req *pb.DailyMetricDataRequest) (*pb.DailyMetricDataResponse, error) {
+	return m.metricData.GetDailyMetricData(ctx, req)
+}
+
+func (m costInsightsCompositeServer) GetGroupDailyCost(ctx context.Context, req *pb.GroupDailyCostRequest) (*pb.GroupDailyCostResponse, error) {
+	return m.groupCost.GetGroupDailyCost(ctx, req)
+}
+
+func (m costInsightsCompositeServer) GetProjectDailyCost(ctx context.Context, req *pb.ProjectDailyCostRequest) (*pb.ProjectDailyCostResponse, error) {
+	return m.projectCost.GetProjectDailyCost(ctx, req)
+}
+
+func (m costInsightsCompositeServer) GetProductInsights(ctx context.Context, req *pb.ProductInsightsRequest) (*pb.Entity, error) {
+	return m.insights.GetProductInsights(ctx, req)
+}
+
+func (m costInsightsCompositeServer) GetAlerts(ctx context.Context, req *pb.AlertRequest) (*pb.AlertResponse, error) {
+	return m.alerts.GetAlerts(ctx, req)
+}
+
+func (m costInsightsCompositeServer) GetCostForecast(ctx context.Context, req *pb.CostForecastRequest) (*pb.CostForecastResponse, error) {
+	return m.forecast.GetCostForecast(ctx, req)
+}
+
+func (m costInsightsCompositeServer) SetAlertStatus(ctx context.Context, req *pb.AlertStatusRequest) (*pb.AlertStatusResponse, error) {
+	return m.alerts.SetAlertStatus(ctx, req)
+}
+
+// GetProducts
+// is served by the insights provider whose GetProductInsights takes the product ids
+func (m costInsightsCompositeServer) GetProducts(ctx context.Context, req *pb.ProductsRequest) (*pb.ProductsResponse, error) {
+	return m.insights.GetProducts(ctx, req)
+}
+
+func (m costInsightsCompositeServer) GetCommitmentCoverage(ctx context.Context, req *pb.CommitmentRequest) (*pb.CommitmentResponse, error) {
+	return m.commitments.GetCommitmentCoverage(ctx, req)
+}
+
+func (m costInsightsCompositeServer) GetCommitmentUtilization(ctx context.Context, req *pb.CommitmentRequest) (*pb.CommitmentResponse, error) {
+	return m.commitments.GetCommitmentUtilization(ctx, req)
+}
@@ -0,0 +1,371 @@
+package svc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/seizadi/cost-insights-backend/pkg/cur"
+	"github.com/seizadi/cost-insights-backend/pkg/pb"
+	"github.com/seizadi/cost-insights-backend/pkg/utils"
+)
+
+const testCostRecords = `[
+  {"Date": "2021-08-01", "Project": "project-a", "Product": "EC2", "Entity": "service-a", "Amount": 10},
+  {"Date": "2021-08-01", "Project": "project-b", "Product": "S3", "Entity": "bucket-b", "Amount": 5},
+  {"Date": "2021-08-02", "Project": "project-a", "Product": "EC2", "Entity": "service-a", "Amount": 20},
+  {"Date": "2021-08-02", "Project": "project-b", "Product": "S3", "Entity": "bucket-b", "Amount": 5},
+  {"Date": "2021-08-03", "Project": "project-b", "Product": "S3", "Entity": "bucket-b", "Amount": 5},
+  {"Date": "2021-08-03", "Project": "project-a", "Product": "EC2", "Entity": "service-b", "Amount": 30},
+  {"Date": "2021-08-04", "Project": "project-b", "Product": "S3", "Entity": "bucket-b", "Amount": 40}
+]`
+
+func writeTestCostRecords(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "cost")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	path := filepath.Join(dir, "cost.json")
+	if err := ioutil.WriteFile(path, []byte(testCostRecords), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestNewCostProvider(t *testing.T) {
+	viper.Set("cost.file.path", writeTestCostRecords(t))
+	viper.Set("cost.composite.default", MockCostProvider)
+	viper.Set("cost.composite.alerts", FileCostProvider)
+	defer viper.Set("cost.composite.alerts", "")
+
+	var tests = []struct {
+		name     string
+		provider string
+		err      bool
+	}{
+		{name: "mock provider", provider: MockCostProvider},
+		{name: "file provider", provider: FileCostProvider},
+		{name: "composite provider", provider: CompositeCostProvider},
+		{name: "unknown provider", provider: "gcp", err: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			provider, err := NewCostProvider(test.provider, nil)
+			if test.err {
+				if err == nil {
+					t.Errorf("Expected error for provider %q", test.provider)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if provider.Name() != test.provider {
+				t.Errorf("Output %q not equal to expected %q", provider.Name(), test.provider)
+			}
+		})
+	}
+}
+
+func TestNewCostProviderFiscalCalendar(t *testing.T) {
+	viper.Set("cost.fiscal.pattern", "4-4-5")
+	defer viper.Set("cost.fiscal.pattern", utils.CalendarMonths)
+
+	if _, err := NewCostProvider(MockCostProvider, nil); err == nil {
+		t.Errorf("Expected error for an invalid fiscal calendar pattern")
+	}
+}
+
+func TestCompositeCostProvider(t *testing.T) {
+	viper.Set("cost.file.path", writeTestCostRecords(t))
+	viper.Set("cost.composite.default", MockCostProvider)
+	viper.Set("cost.composite.alerts", FileCostProvider)
+	defer viper.Set("cost.composite.alerts", "")
+
+	provider, err := NewCostProvider(CompositeCostProvider, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// alerts come from the file provider which has none, groups from the mock provider
+	alerts, err := provider.GetAlerts(context.Background(), &pb.AlertRequest{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(alerts.Alerts) != 0 {
+		t.Errorf("Output %d alerts not equal to expected 0", len(alerts.Alerts))
+	}
+	groups, err := provider.GetUserGroups(context.Background(), &pb.UserGroupsRequest{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if groups.Groups[0].Id != "pied-piper" {
+		t.Errorf("Output %q not equal to expected %q", groups.Groups[0].Id, "pied-piper")
+	}
+
+	viper.Set("cost.composite.alerts", CompositeCostProvider)
+	if _, err := NewCostProvider(CompositeCostProvider, nil); err == nil {
+		t.Error("Expected error when composite provider delegates to itself")
+	}
+}
+
+func TestFileCostProvider(t *testing.T) {
+	provider, err := NewCostInsightsApiFileServer(writeTestCostRecords(t))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	date, _ := provider.GetLastCompleteBillingDate(context.Background(), nil)
+	if date.Date != "2021-08-04" {
+		t.Errorf("Output %q not equal to expected %q", date.Date, "2021-08-04")
+	}
+
+	cost, err := provider.GetGroupDailyCost(context.Background(), &pb.GroupDailyCostRequest{Intervals: "R2/P7D/2021-08-05"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []float64{15, 25, 35, 40}
+	if len(cost.Aggregation) != len(expected) {
+		t.Fatalf("Output %d days not equal to expected %d", len(cost.Aggregation), len(expected))
+	}
+	for i, amount := range expected {
+		if cost.Aggregation[i].Amount != amount {
+			t.Errorf("Output %f not equal to expected %f", cost.Aggregation[i].Amount, amount)
+		}
+	}
+	if len(cost.GroupedCosts.Product) != 2 || len(cost.GroupedCosts.Project) != 2 {
+		t.Errorf("Unexpected grouped costs: %v", cost.GroupedCosts)
+	}
+
+	projectCost, err := provider.GetProjectDailyCost(context.Background(), &pb.ProjectDailyCostRequest{
+		Project: "project-b", Intervals: "R2/P7D/2021-08-05",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(projectCost.Aggregation) != 4 || projectCost.Aggregation[3].Amount != 40 {
+		t.Errorf("Unexpected project aggregation: %v", projectCost.Aggregation)
+	}
+
+	insights, err := provider.GetProductInsights(context.Background(), &pb.ProductInsightsRequest{
+		Product: "EC2", Intervals: "R2/P7D/2021-08-09",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if insights.Aggregation[0] != 10 || insights.Aggregation[1] != 50 {
+		t.Errorf("Unexpected insights aggregation: %v", insights.Aggregation)
+	}
+	if len(insights.Entities.Service) != 2 {
+		t.Errorf("Output %d entities not equal to expected 2", len(insights.Entities.Service))
+	}
+
+	// Every repeat of the interval is a bucket
+	insights, err = provider.GetProductInsights(context.Background(), &pb.ProductInsightsRequest{
+		Product: "EC2", Intervals: "R3/2021-08-01/P1D",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if fmt.Sprint(insights.Aggregation) != "[10 20 30]" || insights.Change.Amount != 20 {
+		t.Errorf("Unexpected insights aggregation: %v %v", insights.Aggregation, insights.Change)
+	}
+}
+
+func TestFileCostForecast(t *testing.T) {
+	viper.Set("cost.forecast.history_days", 7)
+	viper.Set("cost.forecast.confidence", 0.8)
+	provider, err := NewCostInsightsApiFileServer(writeTestCostRecords(t))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The forecast starts after the last billing date and runs to the end of August
+	forecast, err := provider.GetCostForecast(context.Background(), &pb.CostForecastRequest{Project: "project-b"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if forecast.Id != "project-b" || forecast.StartDate != "2021-08-01" || forecast.EndDate != "2021-09-01" {
+		t.Errorf("Unexpected forecast period: %s %s to %s", forecast.Id, forecast.StartDate, forecast.EndDate)
+	}
+	if len(forecast.Aggregation) != 7 || forecast.Actual != 55 || forecast.Model != "holt" {
+		t.Errorf("Unexpected forecast history: %d days, actual %v, model %s", len(forecast.Aggregation), forecast.Actual, forecast.Model)
+	}
+	if len(forecast.Forecast) != 27 || forecast.Forecast[0].Date != "2021-08-05" {
+		t.Fatalf("Unexpected forecast days: %v", forecast.Forecast)
+	}
+	if forecast.Total.Date != "2021-08-31" || forecast.Total.Amount < forecast.Actual ||
+		forecast.Total.Lower > forecast.Total.Amount || forecast.Total.Upper < forecast.Total.Amount {
+		t.Errorf("Unexpected forecast total: %v", forecast.Total)
+	}
+
+	quarter, err := provider.GetCostForecast(context.Background(), &pb.CostForecastRequest{Horizon: "quarter", Date: "2021-08-04"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if quarter.StartDate != "2021-07-01" || quarter.EndDate != "2021-10-01" || quarter.Actual != 115 {
+		t.Errorf("Unexpected quarter forecast: %s to %s, actual %v", quarter.StartDate, quarter.EndDate, quarter.Actual)
+	}
+
+	if _, err := provider.GetCostForecast(context.Background(), &pb.CostForecastRequest{Horizon: "week"}); err == nil {
+		t.Error("Expected error for an unknown forecast horizon")
+	}
+}
+
+func TestCurCostProvider(t *testing.T) {
+	viper.Set("cost.cur.path", "../cur/testdata/parquet")
+	viper.Set("cost.cur.cost_column", "lineItem/UnblendedCost")
+	provider, err := NewCostProvider(CurCostProvider, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if provider.Name() != CurCostProvider {
+		t.Errorf("Output %q not equal to expected %q", provider.Name(), CurCostProvider)
+	}
+
+	date, _ := provider.GetLastCompleteBillingDate(context.Background(), nil)
+	if date.Date != "2021-09-04" {
+		t.Errorf("Output %q not equal to expected %q", date.Date, "2021-09-04")
+	}
+
+	cost, err := provider.GetGroupDailyCost(context.Background(), &pb.GroupDailyCostRequest{Intervals: "R2/P7D/2021-09-05"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// Two hours of the instances of both accounts, the buckets and the tax
+	expected := []float64{7, 13, 19, 25}
+	if len(cost.Aggregation) != len(expected) {
+		t.Fatalf("Output %d days not equal to expected %d", len(cost.Aggregation), len(expected))
+	}
+	for i, amount := range expected {
+		if cost.Aggregation[i].Amount != amount {
+			t.Errorf("Output %f not equal to expected %f", cost.Aggregation[i].Amount, amount)
+		}
+	}
+	// The tax line items have no product name and are grouped by product code
+	if len(cost.GroupedCosts.Product) != 3 || len(cost.GroupedCosts.Project) != 2 {
+		t.Errorf("Unexpected grouped costs: %v", cost.GroupedCosts)
+	}
+
+	insights, err := provider.GetProductInsights(context.Background(), &pb.ProductInsightsRequest{
+		Product: "Amazon Elastic Compute Cloud", Project: "222222222222", Intervals: "R2/P7D/2021-09-10",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if insights.Aggregation[0] != 12 || insights.Aggregation[1] != 28 {
+		t.Errorf("Unexpected insights aggregation: %v", insights.Aggregation)
+	}
+	if len(insights.Entities.Service) != 1 || insights.Entities.Service[0].Id != "i-2" {
+		t.Errorf("Unexpected insights entities: %v", insights.Entities.Service)
+	}
+
+	products, err := provider.GetProducts(context.Background(), &pb.ProductsRequest{Project: "222222222222"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(products.Products) == 0 || products.Products[0].Id != "Amazon Elastic Compute Cloud" {
+		t.Errorf("Unexpected products: %v", products.Products)
+	}
+}
+
+// switchingSource
+// is a CUR export the test replaces, listing it fails while err is set
+type switchingSource struct {
+	mu     sync.Mutex
+	source cur.Source
+	err    error
+}
+
+func (s *switchingSource) set(source cur.Source, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.source, s.err = source, err
+}
+
+func (s *switchingSource) List(ctx context.Context) ([]string, error) {
+	s.mu.Lock()
+	source, err := s.source, s.err
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return source.List(ctx)
+}
+
+func (s *switchingSource) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	s.mu.Lock()
+	source := s.source
+	s.mu.Unlock()
+	return source.Open(ctx, key)
+}
+
+func TestCurCostProviderRefresh(t *testing.T) {
+	source := &switchingSource{source: cur.NewDirectorySource(t.TempDir())}
+	provider, err := NewCostInsightsApiCurServer(source, cur.UnblendedCostColumn, time.Hour)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	server := provider.(*costInsightsCurServer)
+	now := time.Now()
+	server.now = func() time.Time { return now }
+	// reloaded waits for the background read of the export to finish
+	reloaded := func() {
+		for i := 0; i < 500; i++ {
+			server.mu.Lock()
+			loading := server.loading
+			server.mu.Unlock()
+			if !loading {
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatal("The export was not read again")
+	}
+	billingDate := func() string {
+		date, err := server.GetLastCompleteBillingDate(context.Background(), nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return date.Date
+	}
+
+	empty := billingDate()
+	source.set(cur.NewDirectorySource("../cur/testdata/parquet"), nil)
+	billingDate()
+	reloaded()
+	if date := billingDate(); date != empty {
+		t.Errorf("Output %q not equal to expected %q before the ttl", date, empty)
+	}
+
+	now = now.Add(time.Hour)
+	billingDate()
+	reloaded()
+	if date := billingDate(); date != "2021-09-04" {
+		t.Errorf("Output %q not equal to expected %q after the ttl", date, "2021-09-04")
+	}
+
+	// A failed read keeps the records and is retried after the ttl
+	source.set(nil, errors.New("export unavailable"))
+	now = now.Add(time.Hour)
+	billingDate()
+	reloaded()
+	if date := billingDate(); date != "2021-09-04" {
+		t.Errorf("Output %q not equal to expected %q after a failed read", date, "2021-09-04")
+	}
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if server.loading || !server.loaded.Equal(now) {
+		t.Errorf("The failed read was retried before the ttl")
+	}
+}
@@ -11,8 +11,20 @@ const (
 	P3M Duration = "P3M"
 )
 
+// IntervalFields
+// of an ISO 8601 repeating interval, Repeats periods of Duration from StartDate (inclusive) to
+// EndDate (exclusive)
 type IntervalFields struct{
+	Repeats int
 	Duration Duration
+	StartDate string
 	EndDate string
 }
 
+// Period
+// is a bucket of a repeating interval from Start (inclusive) to End (exclusive)
+type Period struct {
+	Start string
+	End string
+}
+
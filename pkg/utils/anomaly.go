@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"math"
+)
+
+// madScale scales the median absolute deviation to the standard deviation of normal errors
+const madScale = 1.4826
+
+// Anomaly
+// is a run of consecutive days from Start up to the exclusive End whose costs are significantly
+// above the Expected costs, Actual is the cost of those days and Score the largest z-score.
+type Anomaly struct {
+	Start    int
+	End      int
+	Expected float64
+	Actual   float64
+	Score    float64
+}
+
+// Excess returns the cost of the anomaly above the expected cost
+func (a Anomaly) Excess() float64 {
+	return a.Actual - a.Expected
+}
+
+// seasonalDecomposition
+// splits the daily series into a trend line, Theil-Sen so that earlier spikes do not move it,
+// and the weekday seasonal components, the median difference of each weekday from the trend.
+// Series shorter than two weeks have no seasonal components.
+func seasonalDecomposition(series []float64) (slope float64, intercept float64, seasonal []float64) {
+	xs := make([]float64, len(series))
+	for t := range xs {
+		xs[t] = float64(t)
+	}
+	season := weekDays
+	if len(series) < 2*weekDays {
+		season = 1
+	}
+
+	slope, intercept = theilSen(xs, series)
+	seasonal = make([]float64, season)
+	if season > 1 {
+		for d := range seasonal {
+			residuals := []float64{}
+			for t := d; t < len(series); t += season {
+				residuals = append(residuals, series[t]-(intercept+slope*xs[t]))
+			}
+			seasonal[d] = median(residuals)
+		}
+		centre := mean(seasonal)
+		for d := range seasonal {
+			seasonal[d] -= centre
+		}
+		deseasonalized := make([]float64, len(series))
+		for t := range series {
+			deseasonalized[t] = series[t] - seasonal[t%season]
+		}
+		slope, intercept = theilSen(xs, deseasonalized)
+	}
+	return slope, intercept, seasonal
+}
+
+// AnomaliesOf
+// returns the cost anomalies in the last recent days of the daily series. The days before them
+// are the baseline, decomposed into a trend and weekday seasonality, and a day is anomalous when
+// its cost is threshold or more robust standard deviations above the expected cost. Consecutive
+// anomalous days are one anomaly, which is only returned when its excess cost is at least
+// minExcess so that spikes of a few cents on a cheap service are not reported.
+func AnomaliesOf(series []float64, recent int, threshold float64, minExcess float64) []Anomaly {
+	anomalies := []Anomaly{}
+	baseline := len(series) - recent
+	if recent < 1 || baseline < 3 {
+		return anomalies
+	}
+
+	slope, intercept, seasonal := seasonalDecomposition(series[:baseline])
+	expected := func(t int) float64 {
+		return math.Max(0, intercept+slope*float64(t)+seasonal[t%len(seasonal)])
+	}
+	residuals := make([]float64, baseline)
+	for t := 0; t < baseline; t++ {
+		residuals[t] = series[t] - expected(t)
+	}
+	centre := median(residuals)
+	deviations := make([]float64, baseline)
+	for t := range residuals {
+		deviations[t] = math.Abs(residuals[t] - centre)
+	}
+	sigma := madScale * median(deviations)
+
+	var current *Anomaly
+	for t := baseline; t < len(series); t++ {
+		excess := series[t] - expected(t)
+		score := math.Inf(1)
+		if sigma > 0 {
+			score = excess / sigma
+		}
+		if excess <= 0 || score < threshold {
+			if current != nil && current.Excess() >= minExcess {
+				anomalies = append(anomalies, *current)
+			}
+			current = nil
+			continue
+		}
+		if current == nil {
+			current = &Anomaly{Start: t}
+		}
+		current.End = t + 1
+		current.Expected += expected(t)
+		current.Actual += series[t]
+		current.Score = math.Max(current.Score, score)
+	}
+	if current != nil && current.Excess() >= minExcess {
+		anomalies = append(anomalies, *current)
+	}
+	return anomalies
+}
@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAnomaliesOf(t *testing.T) {
+	// Eight weeks of growing costs with a weekend dip and some noise, then a two day spike
+	series := weeklySeries(63, 3)
+	series[58] += 200
+	series[59] += 120
+
+	anomalies := AnomaliesOf(series, 7, 3, 10)
+	if len(anomalies) != 1 {
+		t.Fatalf("Output %d anomalies not equal to expected 1: %v", len(anomalies), anomalies)
+	}
+	anomaly := anomalies[0]
+	if anomaly.Start != 58 || anomaly.End != 60 {
+		t.Errorf("Output days %d to %d not equal to expected 58 to 60", anomaly.Start, anomaly.End)
+	}
+	if math.Abs(anomaly.Excess()-320) > 15 {
+		t.Errorf("Output excess %v not equal to expected 320", anomaly.Excess())
+	}
+	if anomaly.Score < 3 {
+		t.Errorf("Output score %v below the threshold", anomaly.Score)
+	}
+
+	// The weekend dip and the growth of the costs are expected
+	if anomalies := AnomaliesOf(weeklySeries(63, 3), 7, 3, 10); len(anomalies) != 0 {
+		t.Errorf("Output %v anomalies of a series without spikes", anomalies)
+	}
+}
+
+func TestAnomaliesOfBaselineSpike(t *testing.T) {
+	// A spike in the baseline neither moves the expected costs nor is reported again
+	series := weeklySeries(63, 3)
+	series[20] += 1000
+	if anomalies := AnomaliesOf(series, 7, 3, 10); len(anomalies) != 0 {
+		t.Errorf("Output %v anomalies after a spike in the baseline", anomalies)
+	}
+}
+
+func TestAnomaliesOfMinExcess(t *testing.T) {
+	// A flat cost of 10 a day, the spike of 5 is significant but below the minimum excess
+	series := make([]float64, 21)
+	for i := range series {
+		series[i] = 10
+	}
+	series[20] = 15
+	if anomalies := AnomaliesOf(series, 3, 3, 10); len(anomalies) != 0 {
+		t.Errorf("Output %v anomalies below the minimum excess", anomalies)
+	}
+	anomalies := AnomaliesOf(series, 3, 3, 5)
+	if len(anomalies) != 1 || anomalies[0].Start != 20 || anomalies[0].Expected != 10 {
+		t.Errorf("Unexpected anomalies %v", anomalies)
+	}
+
+	if anomalies := AnomaliesOf(series[:3], 3, 3, 5); len(anomalies) != 0 {
+		t.Errorf("Output %v anomalies without a baseline", anomalies)
+	}
+}
@@ -2,90 +2,102 @@ package utils
 
 import (
 	"errors"
-	"regexp"
-	"strconv"
-	"time"
-	
-	errorsPkg "github.com/pkg/errors"
-	"github.com/sajari/regression"
 	
 	"github.com/seizadi/cost-insights-backend/pkg/pb"
 	"github.com/seizadi/cost-insights-backend/pkg/types"
 )
 
 
+// getChange
+// returns the change from the first to the last amount. The ratio is omitted when the first
+// amount is zero, Backstage then shows ∞ or -∞ from the sign of the amount. A drop to zero is a
+// ratio of -1 and no cost in either amount is no change.
 func getChange(firstAmount float64, lastAmount float64) *pb.ChangeStatistic {
-	// if either the first or last amounts are zero, the rate of increase/decrease is infinite
-	if firstAmount == 0 || lastAmount == 0 {
+	if firstAmount == 0 {
 		return &pb.ChangeStatistic{
-			Amount: lastAmount - firstAmount,
+			Amount: lastAmount,
 		}
 	}
 	
 	return &pb.ChangeStatistic{
-		Ratio: float32(lastAmount - firstAmount) / float32(firstAmount),
+		Ratio: float32((lastAmount - firstAmount) / firstAmount),
 		Amount: lastAmount - firstAmount,
 	}
 }
 
-func ChangeOf(aggregation []*pb.DateAggregation) *pb.ChangeStatistic {
-	var firstAmount float64 = 0
-	var lastAmount float64 = 0
-	if len(aggregation) > 0 {
-		firstAmount = aggregation[0].Amount
-		lastAmount = aggregation[len(aggregation) - 1].Amount
-	}
-	
-	return getChange(firstAmount, lastAmount)
+// ChangeOf
+// returns the change between the buckets of the repeating intervals, the sum of the daily
+// amounts of the first bucket compared with the sum of the last one. A bucket without any day
+// in the aggregation is missing rather than zero and is skipped, with fewer than two buckets
+// of data the change is zero.
+func ChangeOf(aggregation []*pb.DateAggregation, intervals string) (*pb.ChangeStatistic, error) {
+	return changeOfBuckets(aggregation, intervals, func(sum float64, days int) float64 {
+		return sum
+	})
 }
 
-func ChangeOfEntity(aggregate []float64) *pb.ChangeStatistic {
-	return getChange(aggregate[0], aggregate[1])
+// MeanChangeOf
+// returns the change between the buckets of the repeating intervals like ChangeOf, but compares
+// the mean of the daily amounts of the buckets, for daily amounts such as percentages that do
+// not add up
+func MeanChangeOf(aggregation []*pb.DateAggregation, intervals string) (*pb.ChangeStatistic, error) {
+	return changeOfBuckets(aggregation, intervals, func(sum float64, days int) float64 {
+		return sum / float64(days)
+	})
 }
 
-func TrendlineOf(aggregation []*pb.DateAggregation) (*pb.Trendline, error) {
-	trend := pb.Trendline{}
-	r := new(regression.Regression)
-	r.SetVar(0, "amount")
-	for i:=0; i < len(aggregation); i++ {
-		t, err := time.Parse(types.DEFAULT_DATE_FORMAT, aggregation[i].Date)
-		if err != nil {
-			return &trend, errorsPkg.Wrap(err, "failed to parse date: " + aggregation[i].Date)
-		}
-		r.Train(regression.DataPoint(float64(t.UnixNano() / int64(time.Millisecond))/1000, []float64{float64(aggregation[i].Amount)}))
+// changeOfBuckets
+// returns the change from the first to the last bucket with days in the aggregation, the
+// amount of a bucket is the total of the sum and the number of its days
+func changeOfBuckets(aggregation []*pb.DateAggregation, intervals string, total func(sum float64, days int) float64) (*pb.ChangeStatistic, error) {
+	interval, err := ParseIntervals(intervals)
+	if err != nil {
+		return nil, err
 	}
-	err := r.Run()
+	periods, err := Buckets(interval)
 	if err != nil {
-		return &trend, errorsPkg.Wrap(err, "failed to train on data")
+		return nil, err
 	}
-	// Returns form:  Predicted = 67.0341 + amount*-0.0001
-	formula := r.Formula
-	// RegEx for Floating Point is: [+\-]?(?:(?:0|[1-9]\d*)(?:\.\d*)?|\.\d+)(?:\d[eE][+\-]?\d+)?
-	regEx := regexp.MustCompile(`Predicted = (?P<intercept>[+\-]?(?:(?:0|[1-9]\d*)(?:\.\d*)?|\.\d+)(?:\d[eE][+\-]?\d+)?) \+ amount\*(?P<slope>[+\-]?(?:(?:0|[1-9]\d*)(?:\.\d*)?|\.\d+)(?:\d[eE][+\-]?\d+)?)`)
-	matches := regEx.FindStringSubmatch(formula)
-	names := regEx.SubexpNames()
-	if len(matches) != 3 {
-		return &trend, errors.New("failed to parse formula: " + formula)
+
+	sums := make([]float64, len(periods))
+	days := make([]int, len(periods))
+	for _, day := range aggregation {
+		if bucket := BucketOf(periods, day.Date); bucket >= 0 {
+			sums[bucket] += day.Amount
+			days[bucket]++
+		}
 	}
-	
-	for i, match := range matches {
-		if i != 0 {
-			if names[i] == "intercept" {
-				intercept, err := strconv.ParseFloat(match, 32)
-				if err != nil {
-					return &trend, errors.New("failed to parse float: " + names[i])
-				}
-				trend.Intercept = float32(intercept)
-			} else if names[i] == "slope" {
-				slope, err := strconv.ParseFloat(match, 32)
-				if err != nil {
-					return &trend, errors.New("failed to parse float: " + names[i])
-				}
-				trend.Slope = float32(slope)
-			}
+	buckets := []float64{}
+	for i := range periods {
+		if days[i] > 0 {
+			buckets = append(buckets, total(sums[i], days[i]))
+		}
+	}
+	if len(buckets) < 2 {
+		return &pb.ChangeStatistic{}, nil
+	}
+	return getChange(buckets[0], buckets[len(buckets) - 1]), nil
+}
+
+// ChangeOfEntity
+// returns the change from the first to the last bucket of an entity aggregation
+func ChangeOfEntity(aggregate []float64) *pb.ChangeStatistic {
+	if len(aggregate) == 0 {
+		return getChange(0, 0)
+	}
+	return getChange(aggregate[0], aggregate[len(aggregate) - 1])
+}
+
+// AggregationOfEntities
+// sums the bucketed aggregations of the entities into the aggregation of their parent
+func AggregationOfEntities(entities []*pb.Entity, buckets int) []float64 {
+	aggregation := make([]float64, buckets)
+	for _, e := range entities {
+		for i := 0; i < buckets && i < len(e.Aggregation); i++ {
+			aggregation[i] += e.Aggregation[i]
 		}
 	}
-	return &trend, nil
+	return aggregation
 }
 
 func GetProductCost(product string, intervals string, cost float64) (*pb.ProductCost, error) {
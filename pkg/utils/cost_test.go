@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/seizadi/cost-insights-backend/pkg/pb"
+)
+
+func TestChangeOf(t *testing.T) {
+	days := func(amounts map[string]float64) []*pb.DateAggregation {
+		aggregation := []*pb.DateAggregation{}
+		for date, amount := range amounts {
+			aggregation = append(aggregation, &pb.DateAggregation{Date: date, Amount: amount})
+		}
+		return aggregation
+	}
+	var tests = []struct {
+		name        string
+		aggregation []*pb.DateAggregation
+		expected    pb.ChangeStatistic
+	}{
+		{
+			name:        "sums of buckets",
+			aggregation: days(map[string]float64{"2021-06-01": 10, "2021-06-30": 10, "2021-07-15": 5, "2021-08-01": 20, "2021-08-31": 30}),
+			expected:    pb.ChangeStatistic{Ratio: 1.5, Amount: 30},
+		},
+		{
+			name:        "days outside the buckets",
+			aggregation: days(map[string]float64{"2021-05-31": 100, "2021-06-01": 10, "2021-08-31": 5, "2021-09-01": 100}),
+			expected:    pb.ChangeStatistic{Ratio: -0.5, Amount: -5},
+		},
+		{
+			name:        "missing first bucket",
+			aggregation: days(map[string]float64{"2021-07-01": 10, "2021-08-01": 20}),
+			expected:    pb.ChangeStatistic{Ratio: 1, Amount: 10},
+		},
+		{
+			name:        "zero first bucket",
+			aggregation: days(map[string]float64{"2021-06-01": 0, "2021-08-01": 20}),
+			expected:    pb.ChangeStatistic{Amount: 20},
+		},
+		{
+			name:        "zero last bucket",
+			aggregation: days(map[string]float64{"2021-06-01": 20, "2021-08-01": 0}),
+			expected:    pb.ChangeStatistic{Ratio: -1, Amount: -20},
+		},
+		{
+			name:        "zero buckets",
+			aggregation: days(map[string]float64{"2021-06-01": 0, "2021-08-01": 0}),
+			expected:    pb.ChangeStatistic{},
+		},
+		{
+			name:        "single bucket",
+			aggregation: days(map[string]float64{"2021-07-01": 10, "2021-07-02": 20}),
+			expected:    pb.ChangeStatistic{},
+		},
+	}
+	for _, test := range tests {
+		actual, err := ChangeOf(test.aggregation, "R3/P1M/2021-09-01")
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %v", test.name, err)
+		}
+		if actual.Ratio != test.expected.Ratio || actual.Amount != test.expected.Amount {
+			t.Errorf("Output %v for %s not equal to expected %v", actual, test.name, &test.expected)
+		}
+	}
+
+	if _, err := ChangeOf(nil, "R3/P1M/2021-09"); err == nil {
+		t.Errorf("Expected error for invalid intervals")
+	}
+}
+
+func TestMeanChangeOf(t *testing.T) {
+	// A first bucket of more days with the same percentage is no change
+	aggregation := []*pb.DateAggregation{
+		{Date: "2021-06-01", Amount: 50}, {Date: "2021-06-02", Amount: 50}, {Date: "2021-06-03", Amount: 50},
+		{Date: "2021-08-01", Amount: 50},
+	}
+	actual, err := MeanChangeOf(aggregation, "R3/P1M/2021-09-01")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if actual.Ratio != 0 || actual.Amount != 0 {
+		t.Errorf("Output %v not equal to expected no change", actual)
+	}
+
+	aggregation = append(aggregation, &pb.DateAggregation{Date: "2021-08-02", Amount: 70})
+	actual, err = MeanChangeOf(aggregation, "R3/P1M/2021-09-01")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if actual.Ratio != 0.2 || actual.Amount != 10 {
+		t.Errorf("Output %v not equal to expected a change of 10", actual)
+	}
+}
@@ -4,7 +4,8 @@ import (
 	"errors"
 	"math"
 	"math/rand"
-	"regexp"
+	"strconv"
+	"strings"
 	"time"
 	
 	isoDuration "github.com/senseyeio/duration"
@@ -26,44 +27,199 @@ const (
 // @param intervals An ISO 8601 repeating interval string, such as R2/P30D/2020-09-01
 // https://en.wikipedia.org/wiki/ISO_8601#Repeating_intervals
 //
+// Both the Rn/duration/end and the Rn/start/duration forms are accepted, the end date is
+// exclusive. The duration is in years, months, weeks and days, such as P1Y2M or P2W, and the
+// repeat count is the number of buckets compared, R6/P1M/2021-09-01 compares six months.
+// Durations of whole quarters or years resolve to the periods of the fiscal calendar, the end
+// date of such an interval is the end of the last complete fiscal period before it.
 func ParseIntervals(intervals string) (types.IntervalFields, error) {
 	retIntervalFields := types.IntervalFields{}
-	r := regexp.MustCompile(`\/(?P<duration>P\d+[DM])\/(?P<date>\d{4}-\d{2}-\d{2})`)
-	matches := r.FindStringSubmatch(intervals)
-	names := r.SubexpNames()
-	if ( len(matches) != 3) {
-		return retIntervalFields, errors.New("invalid intervals: " +  intervals)
-	}
-
-	for i, match := range matches {
-		if i != 0 {
-			if names[i] == "duration" {
-				retIntervalFields.Duration = types.Duration(match)
-			} else if names[i] == "date" {
-				retIntervalFields.EndDate = match
-			}
+	parts := strings.Split(intervals, "/")
+	if len(parts) != 3 || !strings.HasPrefix(parts[0], "R") {
+		return retIntervalFields, errors.New("invalid intervals: " + intervals)
+	}
+	repeats, err := strconv.Atoi(parts[0][1:])
+	if err != nil || repeats < 1 {
+		return retIntervalFields, errors.New("invalid intervals repeat count: " + intervals)
+	}
+	retIntervalFields.Repeats = repeats
+
+	durationPart, datePart := parts[1], parts[2]
+	startForm := strings.HasPrefix(parts[2], "P")
+	if startForm {
+		durationPart, datePart = parts[2], parts[1]
+	}
+	d, err := ParseDuration(types.Duration(durationPart))
+	if err != nil {
+		return retIntervalFields, errors.New("invalid intervals duration: " + intervals)
+	}
+	retIntervalFields.Duration = types.Duration(durationPart)
+	date, err := time.Parse(types.DEFAULT_DATE_FORMAT, datePart)
+	if err != nil {
+		return retIntervalFields, errors.New("invalid intervals date: " + intervals)
+	}
+
+	if months := fiscalMonths(d); months > 0 {
+		c := fiscalCalendar()
+		start, _ := c.PeriodOf(date, months)
+		if startForm {
+			retIntervalFields.StartDate = start.Format(types.DEFAULT_DATE_FORMAT)
+			retIntervalFields.EndDate = c.AddMonths(start, repeats*months).Format(types.DEFAULT_DATE_FORMAT)
+		} else {
+			retIntervalFields.StartDate = c.AddMonths(start, -repeats*months).Format(types.DEFAULT_DATE_FORMAT)
+			retIntervalFields.EndDate = start.Format(types.DEFAULT_DATE_FORMAT)
 		}
+	} else if startForm {
+		retIntervalFields.StartDate = datePart
+		retIntervalFields.EndDate = AddDuration(date, d, repeats).Format(types.DEFAULT_DATE_FORMAT)
+	} else {
+		retIntervalFields.StartDate = AddDuration(date, d, -repeats).Format(types.DEFAULT_DATE_FORMAT)
+		retIntervalFields.EndDate = datePart
 	}
 	return retIntervalFields, nil
 }
 
+// ParseDuration
+// parses an ISO 8601 duration of years, months, weeks and days, the costs are daily so durations
+// with a time part are rejected
+func ParseDuration(duration types.Duration) (isoDuration.Duration, error) {
+	d, err := isoDuration.ParseISO8601(string(duration))
+	if err != nil {
+		return d, err
+	}
+	if d.HasTimePart() || d.IsZero() {
+		return d, errors.New(string("duration: " + duration + " unsupported"))
+	}
+	return d, nil
+}
+
+// fiscalMonths
+// returns the months of a duration of whole quarters or years, which resolve on the fiscal
+// calendar, and zero for any other duration
+func fiscalMonths(d isoDuration.Duration) int {
+	months := 12*d.Y + d.M
+	if d.W != 0 || d.D != 0 || months%3 != 0 {
+		return 0
+	}
+	return months
+}
+
+// AddDuration
+// adds n times the duration to t, n may be negative. Adding months keeps the day of the month
+// when the month has it and otherwise ends on the last day of the month, so 2021-08-31 minus
+// P6M is 2021-02-28 rather than rolling over into March.
+func AddDuration(t time.Time, d isoDuration.Duration, n int) time.Time {
+	months := n * (12*d.Y + d.M)
+	if months != 0 {
+		y, m, day := t.Date()
+		first := time.Date(y, m+time.Month(months), 1, 0, 0, 0, 0, t.Location())
+		last := first.AddDate(0, 1, -1).Day()
+		if day > last {
+			day = last
+		}
+		t = time.Date(first.Year(), first.Month(), day, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	}
+	return t.AddDate(0, 0, n*(7*d.W+d.D))
+}
+
+// Buckets
+// returns the Repeats consecutive periods of the interval in date order. The boundaries of an
+// Rn/duration/end interval are counted back from its end date and those of an Rn/start/duration
+// interval forward from its start date, so that months of different lengths do not make the
+// periods drift, e.g. the months of R2/P1M/2021-03-31 end on 2021-02-28 and 2021-03-31.
+func Buckets(interval types.IntervalFields) ([]types.Period, error) {
+	d, err := ParseDuration(interval.Duration)
+	if err != nil {
+		return nil, err
+	}
+	start, err := time.Parse(types.DEFAULT_DATE_FORMAT, interval.StartDate)
+	if err != nil {
+		return nil, err
+	}
+	end, err := time.Parse(types.DEFAULT_DATE_FORMAT, interval.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	boundary := func(n int) time.Time {
+		return AddDuration(start, d, n)
+	}
+	if AddDuration(end, d, -interval.Repeats).Equal(start) {
+		// The end date is the date of the interval, or counts back to the same boundaries
+		boundary = func(n int) time.Time {
+			return AddDuration(end, d, n-interval.Repeats)
+		}
+	}
+	if months := fiscalMonths(d); months > 0 {
+		c := fiscalCalendar()
+		boundary = func(n int) time.Time {
+			return c.AddMonths(start, n*months)
+		}
+	}
+	periods := []types.Period{}
+	for i := 0; i < interval.Repeats; i++ {
+		periods = append(periods, types.Period{
+			Start: boundary(i).Format(types.DEFAULT_DATE_FORMAT),
+			End:   boundary(i + 1).Format(types.DEFAULT_DATE_FORMAT),
+		})
+	}
+	return periods, nil
+}
+
+// BucketOf
+// returns the index of the period containing date, or -1 when it is outside the periods
+func BucketOf(periods []types.Period, date string) int {
+	for i, period := range periods {
+		if date >= period.Start && date < period.End {
+			return i
+		}
+	}
+	return -1
+}
+
+// PeriodLabel
+// returns the label of a bucket of the duration, the fiscal year or quarter such as 2021-Q3 for
+// durations of whole years or quarters, the month such as 2021-09 for buckets of whole months
+// starting on the first of a month and otherwise the start date of the bucket
+func PeriodLabel(period types.Period, duration types.Duration) (string, error) {
+	d, err := ParseDuration(duration)
+	if err != nil {
+		return "", err
+	}
+	start, err := time.Parse(types.DEFAULT_DATE_FORMAT, period.Start)
+	if err != nil {
+		return "", err
+	}
+	months := fiscalMonths(d)
+	switch {
+	case months > 0 && months%12 == 0:
+		return strconv.Itoa(fiscalCalendar().yearOf(start)), nil
+	case months > 0:
+		return fiscalCalendar().QuarterLabel(start), nil
+	case d.W == 0 && d.D == 0 && start.Day() == 1:
+		return start.Format("2006-01"), nil
+	}
+	return period.Start, nil
+}
+
+// LastPeriod
+// returns the fiscal period of period months before the one containing t, end is the last
+// instant of the period
 func LastPeriod(t time.Time, period time.Month) (start, end time.Time) {
-	y, m, _ := t.Date()
-	loc := t.Location()
-	start = time.Date(y, m-period, 1, 0, 0, 0, 0, loc)
-	end = time.Date(y, m, 1, 0, 0, 0, -1, loc)
-	return start, end
+	start, _ = CurrPeriod(t, period)
+	return CurrPeriod(start.AddDate(0, 0, -1), period)
 }
 
+// CurrPeriod
+// returns the fiscal period of period months containing t, such as the fiscal quarter for
+// QUARTER, end is the last instant of the period
 func CurrPeriod(t time.Time, period time.Month) (start, end time.Time) {
-	y, m, _ := t.Date()
-	loc := t.Location()
-	start = time.Date(y, m, 1, 0, 0, 0, 0, loc)
-	end = time.Date(y, m+period, 1, 0, 0, 0, -1, loc)
-	return start, end
+	start, next := fiscalCalendar().PeriodOf(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), int(period))
+	return start, next.Add(-time.Nanosecond)
 }
 
-// TODO - Not sure we need this code for Time pakcage!
+// QuarterEndDate
+// returns inclusiveEndDate when it is the last day of a fiscal quarter, otherwise the last day
+// of the previous fiscal quarter
 func QuarterEndDate(inclusiveEndDate string) (string, error) {
 	endDate, err := time.Parse(types.DEFAULT_DATE_FORMAT, inclusiveEndDate)
 	if err != nil {
@@ -81,63 +237,51 @@ func QuarterEndDate(inclusiveEndDate string) (string, error) {
 // InclusiveStartDateOf
 // Derive the start date of a given period, assuming two repeating intervals.
 //
-// @param duration see comment on Duration enum
+// @param duration an ISO 8601 duration of years, months, weeks and days
 // @param inclusiveEndDate from CostInsightsApi.getLastCompleteBillingDate
-//
-// TODO - Could support a wider range of the ISO durations than the fixed ones here.
-// We can also eliminate two sets of case statments by combining duratin:
-// 		days := d.W*7 + d.D
-//		t = t.AddDate(d.Y, d.M, days)
 func InclusiveStartDateOf( duration types.Duration,  inclusiveEndDate string) (string, error) {
-	switch (duration) {
-	case types.P7D, types.P30D, types.P90D:
-		t, err := time.Parse(types.DEFAULT_DATE_FORMAT, inclusiveEndDate)
-		if err != nil {
-			return "", err
-		}
-		d, err := isoDuration.ParseISO8601(string(duration))
-		if err != nil {
-			return "", err
-		}
-		return t.AddDate(0, 0, -2*d.D).Format(types.DEFAULT_DATE_FORMAT), nil
-	case types.P3M:
-		q, err := QuarterEndDate(inclusiveEndDate)
-		if err != nil {
-			return "", err
-		}
-		qt, err := time.Parse(types.DEFAULT_DATE_FORMAT, q)
-		if err != nil {
-			return "", err
-		}
-		d, err := isoDuration.ParseISO8601(string(duration))
-		if err != nil {
-			return "", err
-		}
-		return qt.AddDate(0, -2*d.M, 0).Format(types.DEFAULT_DATE_FORMAT), nil
+	t, err := time.Parse(types.DEFAULT_DATE_FORMAT, inclusiveEndDate)
+	if err != nil {
+		return "", err
+	}
+	d, err := ParseDuration(duration)
+	if err != nil {
+		return "", err
+	}
+	if d.W != 0 || d.D != 0 {
+		return AddDuration(t, d, -2).Format(types.DEFAULT_DATE_FORMAT), nil
+	}
+	// Month durations compare whole fiscal periods, such as quarters for P3M
+	exclusiveEnd, err := ExclusiveEndDateOf(duration, inclusiveEndDate)
+	if err != nil {
+		return "", err
 	}
-	return "", errors.New(string("duration: " + duration + " unknown"))
+	end, err := time.Parse(types.DEFAULT_DATE_FORMAT, exclusiveEnd)
+	if err != nil {
+		return "", err
+	}
+	return fiscalCalendar().AddMonths(end, -2*(12*d.Y+d.M)).Format(types.DEFAULT_DATE_FORMAT), nil
 }
 
+// ExclusiveEndDateOf
+// returns the day after the last complete period of the duration. Durations in weeks or days
+// end the day after inclusiveEndDate, durations in months end with the last complete fiscal
+// period of that many months, such as the last complete fiscal quarter for P3M.
 func ExclusiveEndDateOf(duration types.Duration, inclusiveEndDate string) (string, error) {
-	switch (duration) {
-	case types.P7D, types.P30D, types.P90D:
-		t, err := time.Parse(types.DEFAULT_DATE_FORMAT, inclusiveEndDate)
-		if err != nil {
-			return "", err
-		}
-		return t.AddDate(0, 0, 1).Format(types.DEFAULT_DATE_FORMAT), nil
-	case types.P3M:
-		q, err := QuarterEndDate(inclusiveEndDate)
-		if err != nil {
-			return "", err
-		}
-		qt, err := time.Parse(types.DEFAULT_DATE_FORMAT, q)
-		if err != nil {
-			return "", err
-		}
-		return qt.AddDate(0, 0, 1).Format(types.DEFAULT_DATE_FORMAT), nil
+	t, err := time.Parse(types.DEFAULT_DATE_FORMAT, inclusiveEndDate)
+	if err != nil {
+		return "", err
+	}
+	d, err := ParseDuration(duration)
+	if err != nil {
+		return "", err
+	}
+	next := t.AddDate(0, 0, 1)
+	if d.W != 0 || d.D != 0 {
+		return next.Format(types.DEFAULT_DATE_FORMAT), nil
 	}
-	return "", errors.New(string("duration: " + duration + " unknown"))
+	periodStart, _ := fiscalCalendar().PeriodOf(next, 12*d.Y+d.M)
+	return periodStart.Format(types.DEFAULT_DATE_FORMAT), nil
 }
 
 func InclusiveEndDateOf(duration types.Duration, inclusiveEndDate string) (string, error) {
@@ -0,0 +1,153 @@
+package utils
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/seizadi/cost-insights-backend/pkg/types"
+)
+
+func TestParseIntervals(t *testing.T) {
+	var tests = []struct {
+		intervals string
+		expected  types.IntervalFields
+		err       bool
+	}{
+		{intervals: "R2/P30D/2021-09-01", expected: types.IntervalFields{Repeats: 2, Duration: "P30D", StartDate: "2021-07-03", EndDate: "2021-09-01"}},
+		{intervals: "R2/P3M/2021-07-01", expected: types.IntervalFields{Repeats: 2, Duration: "P3M", StartDate: "2021-01-01", EndDate: "2021-07-01"}},
+		{intervals: "R6/P1M/2021-09-01", expected: types.IntervalFields{Repeats: 6, Duration: "P1M", StartDate: "2021-03-01", EndDate: "2021-09-01"}},
+		{intervals: "R3/P2W/2021-09-01", expected: types.IntervalFields{Repeats: 3, Duration: "P2W", StartDate: "2021-07-21", EndDate: "2021-09-01"}},
+		{intervals: "R2/P1Y2M/2021-09-01", expected: types.IntervalFields{Repeats: 2, Duration: "P1Y2M", StartDate: "2019-05-01", EndDate: "2021-09-01"}},
+		{intervals: "R2/2020-01-01/P1Y", expected: types.IntervalFields{Repeats: 2, Duration: "P1Y", StartDate: "2020-01-01", EndDate: "2022-01-01"}},
+		{intervals: "R1/P1M/2021-03-31", expected: types.IntervalFields{Repeats: 1, Duration: "P1M", StartDate: "2021-02-28", EndDate: "2021-03-31"}},
+		{intervals: "R1/P6M/2021-08-31", expected: types.IntervalFields{Repeats: 1, Duration: "P6M", StartDate: "2021-01-01", EndDate: "2021-07-01"}},
+		{intervals: "P30D/2021-09-01", err: true},
+		{intervals: "R0/P30D/2021-09-01", err: true},
+		{intervals: "R/P30D/2021-09-01", err: true},
+		{intervals: "R2/PT1H/2021-09-01", err: true},
+		{intervals: "R2/P0D/2021-09-01", err: true},
+		{intervals: "R2/P30D/2021-09", err: true},
+	}
+	for _, test := range tests {
+		actual, err := ParseIntervals(test.intervals)
+		if test.err {
+			if err == nil {
+				t.Errorf("Expected error for intervals %q", test.intervals)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Unexpected error for intervals %q: %v", test.intervals, err)
+			continue
+		}
+		if actual != test.expected {
+			t.Errorf("Output %v not equal to expected %v", actual, test.expected)
+		}
+	}
+}
+
+func TestBuckets(t *testing.T) {
+	interval, err := ParseIntervals("R3/P1M/2021-09-01")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	periods, err := Buckets(interval)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := "[{2021-06-01 2021-07-01} {2021-07-01 2021-08-01} {2021-08-01 2021-09-01}]"
+	if fmt.Sprint(periods) != expected {
+		t.Errorf("Output %v not equal to expected %s", periods, expected)
+	}
+	for date, bucket := range map[string]int{"2021-05-31": -1, "2021-06-01": 0, "2021-07-31": 1, "2021-08-31": 2, "2021-09-01": -1} {
+		if actual := BucketOf(periods, date); actual != bucket {
+			t.Errorf("Output bucket %d of %s not equal to expected %d", actual, date, bucket)
+		}
+	}
+}
+
+func TestBucketsOfMonthEnds(t *testing.T) {
+	var tests = []struct {
+		intervals string
+		expected  string
+	}{
+		{
+			intervals: "R6/P1M/2021-08-31",
+			expected:  "[{2021-02-28 2021-03-31} {2021-03-31 2021-04-30} {2021-04-30 2021-05-31} {2021-05-31 2021-06-30} {2021-06-30 2021-07-31} {2021-07-31 2021-08-31}]",
+		},
+		{
+			intervals: "R2/P1M/2021-03-31",
+			expected:  "[{2021-01-31 2021-02-28} {2021-02-28 2021-03-31}]",
+		},
+		{
+			intervals: "R3/2021-01-31/P1M",
+			expected:  "[{2021-01-31 2021-02-28} {2021-02-28 2021-03-31} {2021-03-31 2021-04-30}]",
+		},
+	}
+	for _, test := range tests {
+		interval, err := ParseIntervals(test.intervals)
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %v", test.intervals, err)
+		}
+		periods, err := Buckets(interval)
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %v", test.intervals, err)
+		}
+		if fmt.Sprint(periods) != test.expected {
+			t.Errorf("Output %v of %s not equal to expected %s", periods, test.intervals, test.expected)
+		}
+	}
+}
+
+func TestPeriodLabel(t *testing.T) {
+	var tests = []struct {
+		period   types.Period
+		duration types.Duration
+		expected string
+	}{
+		{period: types.Period{Start: "2021-07-01", End: "2021-10-01"}, duration: types.P3M, expected: "2021-Q3"},
+		{period: types.Period{Start: "2021-01-01", End: "2022-01-01"}, duration: "P1Y", expected: "2021"},
+		{period: types.Period{Start: "2021-09-01", End: "2021-10-01"}, duration: "P1M", expected: "2021-09"},
+		{period: types.Period{Start: "2021-09-15", End: "2021-10-15"}, duration: "P1M", expected: "2021-09-15"},
+		{period: types.Period{Start: "2021-10-08", End: "2021-10-15"}, duration: "P1W", expected: "2021-10-08"},
+	}
+	for _, test := range tests {
+		actual, err := PeriodLabel(test.period, test.duration)
+		if err != nil {
+			t.Errorf("Unexpected error for period %v: %v", test.period, err)
+			continue
+		}
+		if actual != test.expected {
+			t.Errorf("Output %v not equal to expected %v", actual, test.expected)
+		}
+	}
+}
+
+func TestPeriodDatesOf(t *testing.T) {
+	var tests = []struct {
+		duration     types.Duration
+		inclusiveEnd string
+		start        string
+		exclusiveEnd string
+	}{
+		{duration: types.P7D, inclusiveEnd: "2021-08-31", start: "2021-08-17", exclusiveEnd: "2021-09-01"},
+		{duration: types.P90D, inclusiveEnd: "2021-08-31", start: "2021-03-04", exclusiveEnd: "2021-09-01"},
+		{duration: types.P3M, inclusiveEnd: "2021-08-31", start: "2021-01-01", exclusiveEnd: "2021-07-01"},
+		{duration: types.P3M, inclusiveEnd: "2021-09-30", start: "2021-04-01", exclusiveEnd: "2021-10-01"},
+		{duration: "P1Y", inclusiveEnd: "2021-08-31", start: "2019-01-01", exclusiveEnd: "2021-01-01"},
+		{duration: "P5M", inclusiveEnd: "2021-08-15", start: "2020-10-01", exclusiveEnd: "2021-08-01"},
+	}
+	for _, test := range tests {
+		start, err := InclusiveStartDateOf(test.duration, test.inclusiveEnd)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		end, err := ExclusiveEndDateOf(test.duration, test.inclusiveEnd)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if start != test.start || end != test.exclusiveEnd {
+			t.Errorf("Output %s to %s for %s not equal to expected %s to %s", start, end, test.duration, test.start, test.exclusiveEnd)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Patterns of the fiscal months, calendar months or 4-4-5 week calendars
+const (
+	CalendarMonths = "calendar"
+	Weeks445       = "445"
+	Weeks454       = "454"
+	Weeks544       = "544"
+)
+
+// FiscalCalendar
+// divides the fiscal year, which starts in StartMonth, into twelve fiscal months. The fiscal
+// months are calendar months, or with a week pattern such as 4-4-5 every quarter is 13 weeks
+// split into months of 4, 4 and 5 weeks. A week based year starts on the Sunday nearest the first
+// of StartMonth and the 53rd week of a long year is added to its last month. A fiscal year is
+// named by the calendar year it ends in, so with a February start FY2022 starts in February 2021.
+type FiscalCalendar struct {
+	StartMonth time.Month
+	Pattern    string
+}
+
+// NewFiscalCalendar
+// returns the fiscal calendar of the cost.fiscal.start_month and cost.fiscal.pattern configuration
+func NewFiscalCalendar() (FiscalCalendar, error) {
+	c := FiscalCalendar{
+		StartMonth: time.Month(viper.GetInt("cost.fiscal.start_month")),
+		Pattern:    strings.ToLower(viper.GetString("cost.fiscal.pattern")),
+	}
+	if c.StartMonth == 0 {
+		c.StartMonth = time.January
+	}
+	if c.Pattern == "" {
+		c.Pattern = CalendarMonths
+	}
+	if c.StartMonth < time.January || c.StartMonth > time.December {
+		return c, fmt.Errorf("invalid fiscal start month: %d", c.StartMonth)
+	}
+	if c.weeks() == nil && c.Pattern != CalendarMonths {
+		return c, errors.New("unknown fiscal calendar pattern: " + c.Pattern)
+	}
+	return c, nil
+}
+
+// fiscalCalendar
+// returns the configured fiscal calendar for the date functions, which cannot return its error.
+// Providers validate the configuration with NewFiscalCalendar at startup, calendar quarters are
+// only a fallback for a configuration that was not validated.
+func fiscalCalendar() FiscalCalendar {
+	c, err := NewFiscalCalendar()
+	if err != nil {
+		return FiscalCalendar{StartMonth: time.January, Pattern: CalendarMonths}
+	}
+	return c
+}
+
+// weeks returns the weeks of the months of a quarter, nil for calendar months
+func (c FiscalCalendar) weeks() []int {
+	switch c.Pattern {
+	case Weeks445:
+		return []int{4, 4, 5}
+	case Weeks454:
+		return []int{4, 5, 4}
+	case Weeks544:
+		return []int{5, 4, 4}
+	}
+	return nil
+}
+
+// yearStart returns the first day of the fiscal year named year
+func (c FiscalCalendar) yearStart(year int) time.Time {
+	if c.StartMonth != time.January {
+		year--
+	}
+	first := time.Date(year, c.StartMonth, 1, 0, 0, 0, 0, time.UTC)
+	if c.weeks() == nil {
+		return first
+	}
+	// The Sunday nearest the first of the month
+	offset := -int(first.Weekday())
+	if offset < -3 {
+		offset += 7
+	}
+	return first.AddDate(0, 0, offset)
+}
+
+// yearOf returns the name of the fiscal year of t
+func (c FiscalCalendar) yearOf(t time.Time) int {
+	year := t.Year()
+	if c.StartMonth != time.January && t.Month() >= c.StartMonth {
+		year++
+	}
+	for t.Before(c.yearStart(year)) {
+		year--
+	}
+	for !t.Before(c.yearStart(year + 1)) {
+		year++
+	}
+	return year
+}
+
+// monthStart returns the first day of the fiscal month, month may be outside 0 to 11 to address
+// the months of other years
+func (c FiscalCalendar) monthStart(year int, month int) time.Time {
+	year += month / 12
+	month %= 12
+	if month < 0 {
+		year--
+		month += 12
+	}
+	weeks := c.weeks()
+	if weeks == nil {
+		return c.yearStart(year).AddDate(0, month, 0)
+	}
+	days := 0
+	for i := 0; i < month; i++ {
+		days += 7 * weeks[i%3]
+	}
+	return c.yearStart(year).AddDate(0, 0, days)
+}
+
+// monthOf returns the fiscal year and month of t
+func (c FiscalCalendar) monthOf(t time.Time) (int, int) {
+	year := c.yearOf(t)
+	month := 11
+	for month > 0 && t.Before(c.monthStart(year, month)) {
+		month--
+	}
+	return year, month
+}
+
+// AddMonths
+// returns the first day of the fiscal month n months from the fiscal month of t
+func (c FiscalCalendar) AddMonths(t time.Time, n int) time.Time {
+	year, month := c.monthOf(t)
+	return c.monthStart(year, month+n)
+}
+
+// PeriodOf
+// returns the fiscal period of months months containing t, from start (inclusive) to end
+// (exclusive). Periods align to the fiscal year, periods that do not divide the year align to
+// the largest period that does, such as quarters for nine months.
+func (c FiscalCalendar) PeriodOf(t time.Time, months int) (time.Time, time.Time) {
+	align := gcd(12, months)
+	year, month := c.monthOf(t)
+	month -= month % align
+	return c.monthStart(year, month), c.monthStart(year, month+months)
+}
+
+// QuarterLabel
+// returns the fiscal quarter of t, such as 2021-Q3
+func (c FiscalCalendar) QuarterLabel(t time.Time) string {
+	year, month := c.monthOf(t)
+	return fmt.Sprintf("%d-Q%d", year, month/3+1)
+}
+
+// MonthLabel
+// returns the fiscal month of t, such as 2021-07 for the seventh month of FY2021
+func (c FiscalCalendar) MonthLabel(t time.Time) string {
+	year, month := c.monthOf(t)
+	return fmt.Sprintf("%d-%02d", year, month+1)
+}
+
+func gcd(a int, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
@@ -0,0 +1,120 @@
+package utils
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/seizadi/cost-insights-backend/pkg/types"
+)
+
+func date(s string) time.Time {
+	t, _ := time.Parse(types.DEFAULT_DATE_FORMAT, s)
+	return t
+}
+
+func setFiscalCalendar(t *testing.T, startMonth int, pattern string) {
+	viper.Set("cost.fiscal.start_month", startMonth)
+	viper.Set("cost.fiscal.pattern", pattern)
+	t.Cleanup(func() {
+		viper.Set("cost.fiscal.start_month", 1)
+		viper.Set("cost.fiscal.pattern", CalendarMonths)
+	})
+}
+
+func TestNewFiscalCalendar(t *testing.T) {
+	setFiscalCalendar(t, 0, "")
+	c, err := NewFiscalCalendar()
+	if err != nil || c.StartMonth != time.January || c.Pattern != CalendarMonths {
+		t.Errorf("Output %v %v not equal to the calendar months", c, err)
+	}
+	setFiscalCalendar(t, 13, CalendarMonths)
+	if _, err := NewFiscalCalendar(); err == nil {
+		t.Errorf("Expected error for start month 13")
+	}
+	setFiscalCalendar(t, 2, "4-4-5")
+	if _, err := NewFiscalCalendar(); err == nil {
+		t.Errorf("Expected error for pattern 4-4-5")
+	}
+}
+
+func TestFiscalCalendarMonths(t *testing.T) {
+	c := FiscalCalendar{StartMonth: time.February, Pattern: CalendarMonths}
+	for day, expected := range map[string]string{
+		"2021-01-31": "2021-Q4 2021-12",
+		"2021-02-01": "2022-Q1 2022-01",
+		"2021-07-31": "2022-Q2 2022-06",
+		"2021-12-01": "2022-Q4 2022-11",
+	} {
+		if actual := c.QuarterLabel(date(day)) + " " + c.MonthLabel(date(day)); actual != expected {
+			t.Errorf("Output %s of %s not equal to expected %s", actual, day, expected)
+		}
+	}
+	start, end := c.PeriodOf(date("2021-07-01"), 3)
+	if start != date("2021-05-01") || end != date("2021-08-01") {
+		t.Errorf("Output quarter %v to %v not equal to expected 2021-05-01 to 2021-08-01", start, end)
+	}
+	start, end = c.PeriodOf(date("2021-07-01"), 12)
+	if start != date("2021-02-01") || end != date("2022-02-01") {
+		t.Errorf("Output year %v to %v not equal to expected 2021-02-01 to 2022-02-01", start, end)
+	}
+}
+
+func TestFiscalCalendarWeeks(t *testing.T) {
+	c := FiscalCalendar{StartMonth: time.February, Pattern: Weeks445}
+	// FY2022 starts on the Sunday nearest 2021-02-01, its months are 4, 4 and 5 weeks
+	expected := []string{"2021-01-31", "2021-02-28", "2021-03-28", "2021-05-02"}
+	for i, month := range expected {
+		if actual := c.AddMonths(date("2021-01-31"), i); actual != date(month) {
+			t.Errorf("Output month %d %v not equal to expected %s", i, actual, month)
+		}
+	}
+	if label := c.QuarterLabel(date("2021-01-30")); label != "2021-Q4" {
+		t.Errorf("Output %s not equal to expected 2021-Q4", label)
+	}
+
+	// FY2024 has 53 weeks, the extra week is in its last month
+	if start := c.AddMonths(date("2024-02-03"), 0); start != date("2023-12-24") {
+		t.Errorf("Output %v not equal to expected 2023-12-24", start)
+	}
+	if next := c.AddMonths(date("2023-12-24"), 1); next != date("2024-02-04") {
+		t.Errorf("Output %v not equal to expected 2024-02-04", next)
+	}
+	if label := c.MonthLabel(date("2024-02-03")); label != "2024-12" {
+		t.Errorf("Output %s not equal to expected 2024-12", label)
+	}
+}
+
+func TestFiscalIntervals(t *testing.T) {
+	setFiscalCalendar(t, 2, CalendarMonths)
+	interval, err := ParseIntervals("R2/P3M/2021-07-01")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// The last complete fiscal quarter before July is May to July
+	if interval.StartDate != "2020-11-01" || interval.EndDate != "2021-05-01" {
+		t.Errorf("Output %v not equal to the fiscal quarters", interval)
+	}
+	if end, _ := QuarterEndDate("2021-07-31"); end != "2021-07-31" {
+		t.Errorf("Output %s not equal to expected 2021-07-31", end)
+	}
+	if start, _ := InclusiveStartDateOf(types.P3M, "2021-07-30"); start != "2020-11-01" {
+		t.Errorf("Output %s not equal to expected 2020-11-01", start)
+	}
+
+	setFiscalCalendar(t, 2, Weeks445)
+	interval, err = ParseIntervals("R2/2021-02-01/P3M")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	periods, err := Buckets(interval)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := "[{2021-01-31 2021-05-02} {2021-05-02 2021-08-01}]"
+	if fmt.Sprint(periods) != expected {
+		t.Errorf("Output %v not equal to expected %s", periods, expected)
+	}
+}
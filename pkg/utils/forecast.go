@@ -0,0 +1,191 @@
+package utils
+
+import (
+	"errors"
+	"math"
+)
+
+// Models of the cost forecast
+const (
+	HoltWintersModel = "holt_winters"
+	HoltModel        = "holt"
+	NaiveModel       = "naive"
+)
+
+// weekDays is the season of the Holt-Winters model, daily costs follow the days of the week
+const weekDays = 7
+
+// Forecast
+// is the daily forecast of a cost series, the Amount of each day with the Lower and Upper bound
+// of its confidence band, and the Total of the days with the band of the total.
+type Forecast struct {
+	Model      string
+	Amount     []float64
+	Lower      []float64
+	Upper      []float64
+	Total      float64
+	TotalLower float64
+	TotalUpper float64
+}
+
+// smoothing
+// is an additive Holt-Winters model with a damped trend, ETS(A,Ad,A). A season of one without
+// seasonal smoothing is Holt's linear trend model.
+type smoothing struct {
+	alpha  float64 // level
+	beta   float64 // trend
+	gamma  float64 // season
+	phi    float64 // trend damping
+	season int
+}
+
+// smoothingState
+// is the level, trend and seasonal components after the last day of the series, with the sum
+// of the squared one day ahead errors and their count
+type smoothingState struct {
+	level    float64
+	trend    float64
+	seasonal []float64
+	sse      float64
+	errors   int
+}
+
+// fit
+// runs the model over the series. The components start from the first two seasons, the level
+// is the mean of the first season, the trend the change of the mean per day to the second one
+// and the seasonal components the differences of the first season days from its mean.
+func (s smoothing) fit(series []float64) smoothingState {
+	m := s.season
+	state := smoothingState{seasonal: make([]float64, m)}
+	first := mean(series[:m])
+	state.level = first
+	state.trend = (mean(series[m:2*m]) - first) / float64(m)
+	for i := 0; i < m; i++ {
+		state.seasonal[i] = series[i] - first
+	}
+	if m == 1 {
+		state.seasonal[0] = 0
+	}
+
+	for t := m; t < len(series); t++ {
+		seasonal := state.seasonal[t%m]
+		e := series[t] - (state.level + s.phi*state.trend + seasonal)
+		state.sse += e * e
+		state.errors++
+
+		level := s.alpha*(series[t]-seasonal) + (1-s.alpha)*(state.level+s.phi*state.trend)
+		state.trend = s.beta*(level-state.level) + (1-s.beta)*s.phi*state.trend
+		state.seasonal[t%m] = s.gamma*(series[t]-level) + (1-s.gamma)*seasonal
+		state.level = level
+	}
+	return state
+}
+
+// bestSmoothing
+// returns the model with the smallest one day ahead squared error over a grid of smoothing
+// parameters, a grid search keeps the fit deterministic and fast for a few months of days
+func bestSmoothing(series []float64, season int) (smoothing, smoothingState) {
+	gammas := []float64{0}
+	if season > 1 {
+		gammas = []float64{0.05, 0.1, 0.2, 0.3, 0.5}
+	}
+	var best smoothing
+	var bestState smoothingState
+	found := false
+	for _, alpha := range []float64{0.05, 0.1, 0.2, 0.3, 0.5, 0.7, 0.9} {
+		for _, beta := range []float64{0.01, 0.05, 0.1, 0.2} {
+			for _, gamma := range gammas {
+				for _, phi := range []float64{0.9, 0.95, 0.98, 1} {
+					s := smoothing{alpha: alpha, beta: beta, gamma: gamma, phi: phi, season: season}
+					state := s.fit(series)
+					if !found || state.sse < bestState.sse {
+						best, bestState, found = s, state, true
+					}
+				}
+			}
+		}
+	}
+	return best, bestState
+}
+
+// ForecastOf
+// forecasts the next days of the daily series, which must not have gaps. Two weeks or more of
+// history use the Holt-Winters model with weekday seasonality, a shorter history Holt's linear
+// trend and a single day is repeated. The confidence bands hold the given probability assuming
+// normal one day ahead errors, costs are never forecast below zero.
+func ForecastOf(series []float64, days int, confidence float64) (Forecast, error) {
+	if len(series) == 0 {
+		return Forecast{}, errors.New("no cost history to forecast")
+	}
+	if confidence <= 0 || confidence >= 1 {
+		return Forecast{}, errors.New("forecast confidence must be between 0 and 1")
+	}
+	forecast := Forecast{
+		Amount: make([]float64, days),
+		Lower:  make([]float64, days),
+		Upper:  make([]float64, days),
+	}
+	if len(series) == 1 {
+		forecast.Model = NaiveModel
+		for h := 0; h < days; h++ {
+			forecast.Amount[h], forecast.Lower[h], forecast.Upper[h] = series[0], series[0], series[0]
+		}
+		forecast.Total = series[0] * float64(days)
+		forecast.TotalLower, forecast.TotalUpper = forecast.Total, forecast.Total
+		return forecast, nil
+	}
+
+	season := weekDays
+	forecast.Model = HoltWintersModel
+	if len(series) < 2*weekDays {
+		season = 1
+		forecast.Model = HoltModel
+	}
+	s, state := bestSmoothing(series, season)
+	sigma := 0.0
+	if state.errors > 0 {
+		sigma = math.Sqrt(state.sse / float64(state.errors))
+	}
+	z := math.Sqrt2 * math.Erfinv(confidence)
+
+	// The error of the day h ahead is the sum of the errors of the days before it weighted by
+	// how much they carry forward through the components, c_j for the error j days earlier
+	carry := make([]float64, days)
+	damping := 0.0
+	for j := 1; j < days; j++ {
+		damping += math.Pow(s.phi, float64(j))
+		carry[j] = s.alpha * (1 + s.beta*damping)
+		if j%s.season == 0 {
+			carry[j] += s.gamma * (1 - s.alpha)
+		}
+	}
+
+	damping = 0
+	variance := 0.0
+	for h := 1; h <= days; h++ {
+		damping += math.Pow(s.phi, float64(h))
+		if h > 1 {
+			variance += carry[h-1] * carry[h-1]
+		}
+		amount := state.level + damping*state.trend + state.seasonal[(len(series)-1+h)%s.season]
+		band := z * sigma * math.Sqrt(1+variance)
+		forecast.Amount[h-1] = math.Max(0, amount)
+		forecast.Lower[h-1] = math.Max(0, amount-band)
+		forecast.Upper[h-1] = math.Max(0, amount+band)
+		forecast.Total += forecast.Amount[h-1]
+	}
+
+	// The error of the total weighs the error of each day by the days after it that it carries to
+	totalVariance := 0.0
+	cumulative := 0.0
+	for i := days; i >= 1; i-- {
+		totalVariance += (1 + cumulative) * (1 + cumulative)
+		if days-i+1 < days {
+			cumulative += carry[days-i+1]
+		}
+	}
+	band := z * sigma * math.Sqrt(totalVariance)
+	forecast.TotalLower = math.Max(0, forecast.Total-band)
+	forecast.TotalUpper = forecast.Total + band
+	return forecast, nil
+}
@@ -0,0 +1,120 @@
+package utils
+
+import (
+	"math"
+	"testing"
+)
+
+// weeklySeries
+// returns days of costs growing by 1 a day with a weekend dip, noise is added to every other day
+func weeklySeries(days int, noise float64) []float64 {
+	week := []float64{20, 20, 20, 20, 20, -40, -60}
+	series := make([]float64, days)
+	for t := range series {
+		series[t] = 500 + float64(t) + week[t%7]
+		if t%2 == 1 {
+			series[t] += noise
+		} else {
+			series[t] -= noise
+		}
+	}
+	return series
+}
+
+func TestForecastOfWeeklySeason(t *testing.T) {
+	series := weeklySeries(63, 0)
+	actual := weeklySeries(77, 0)[63:]
+
+	forecast, err := ForecastOf(series, 14, 0.8)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if forecast.Model != HoltWintersModel {
+		t.Errorf("Output model %q not equal to expected %q", forecast.Model, HoltWintersModel)
+	}
+	total := 0.0
+	for h, amount := range actual {
+		if math.Abs(forecast.Amount[h]-amount) > 5 {
+			t.Errorf("Output %v of day %d not equal to expected %v", forecast.Amount[h], h, amount)
+		}
+		total += amount
+	}
+	if math.Abs(forecast.Total-total) > 30 {
+		t.Errorf("Output total %v not equal to expected %v", forecast.Total, total)
+	}
+}
+
+func TestForecastOfBands(t *testing.T) {
+	series := weeklySeries(63, 10)
+
+	forecast, err := ForecastOf(series, 28, 0.8)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for h := range forecast.Amount {
+		if forecast.Lower[h] > forecast.Amount[h] || forecast.Upper[h] < forecast.Amount[h] {
+			t.Errorf("Output %v of day %d outside of its band %v to %v", forecast.Amount[h], h, forecast.Lower[h], forecast.Upper[h])
+		}
+	}
+	first := forecast.Upper[0] - forecast.Lower[0]
+	last := forecast.Upper[27] - forecast.Lower[27]
+	if last <= first {
+		t.Errorf("Output band of the last day %v expected wider than the first %v", last, first)
+	}
+	if forecast.TotalLower >= forecast.Total || forecast.TotalUpper <= forecast.Total {
+		t.Errorf("Output total %v outside of its band %v to %v", forecast.Total, forecast.TotalLower, forecast.TotalUpper)
+	}
+
+	wider, err := ForecastOf(series, 28, 0.95)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if wider.Upper[0]-wider.Lower[0] <= first {
+		t.Errorf("Output band %v at 95%% expected wider than %v at 80%%", wider.Upper[0]-wider.Lower[0], first)
+	}
+}
+
+func TestForecastOfShortHistory(t *testing.T) {
+	forecast, err := ForecastOf([]float64{100, 110, 120, 130, 140}, 3, 0.8)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if forecast.Model != HoltModel {
+		t.Errorf("Output model %q not equal to expected %q", forecast.Model, HoltModel)
+	}
+	for h, amount := range []float64{150, 160, 170} {
+		if math.Abs(forecast.Amount[h]-amount) > 5 {
+			t.Errorf("Output %v of day %d not equal to expected %v", forecast.Amount[h], h, amount)
+		}
+	}
+
+	forecast, err = ForecastOf([]float64{42}, 3, 0.8)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if forecast.Model != NaiveModel || forecast.Total != 126 || forecast.Amount[2] != 42 {
+		t.Errorf("Unexpected naive forecast: %+v", forecast)
+	}
+
+	// Falling costs are not forecast below zero
+	forecast, err = ForecastOf([]float64{40, 30, 20, 10}, 5, 0.8)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for h := range forecast.Amount {
+		if forecast.Amount[h] < 0 || forecast.Lower[h] < 0 {
+			t.Errorf("Output %v with lower bound %v of day %d below zero", forecast.Amount[h], forecast.Lower[h], h)
+		}
+	}
+}
+
+func TestForecastOfErrors(t *testing.T) {
+	if _, err := ForecastOf(nil, 3, 0.8); err == nil {
+		t.Error("Expected error forecasting without history")
+	}
+	for _, confidence := range []float64{0, 1, 1.5} {
+		if _, err := ForecastOf([]float64{1, 2}, 3, confidence); err == nil {
+			t.Errorf("Expected error forecasting with confidence %v", confidence)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package utils
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	errorsPkg "github.com/pkg/errors"
+	"github.com/spf13/viper"
+
+	"github.com/seizadi/cost-insights-backend/pkg/pb"
+	"github.com/seizadi/cost-insights-backend/pkg/types"
+)
+
+// Methods of fitting the trendline
+const (
+	LeastSquares = "least_squares"
+	TheilSen     = "theil_sen"
+)
+
+// TrendlineOf
+// fits a line to the daily amounts in the units of the Backstage plugin, which draws the line as
+// Intercept + Slope * seconds with the seconds since the Unix epoch of the day. The Slope is the
+// change of the daily amount per second, 86400 times the Slope is the change per day. The line is
+// fitted with the cost.trendline.method configuration, least_squares or theil_sen.
+func TrendlineOf(aggregation []*pb.DateAggregation) (*pb.Trendline, error) {
+	method := strings.ToLower(viper.GetString("cost.trendline.method"))
+	if method == "" {
+		method = LeastSquares
+	}
+	return TrendlineWith(method, aggregation)
+}
+
+// TrendlineWith
+// fits the trendline of the daily amounts with the method. An empty aggregation has a zero
+// trendline and a single day a flat line through its amount.
+func TrendlineWith(method string, aggregation []*pb.DateAggregation) (*pb.Trendline, error) {
+	xs := make([]float64, len(aggregation))
+	ys := make([]float64, len(aggregation))
+	for i, day := range aggregation {
+		t, err := time.Parse(types.DEFAULT_DATE_FORMAT, day.Date)
+		if err != nil {
+			return &pb.Trendline{}, errorsPkg.Wrap(err, "failed to parse date: "+day.Date)
+		}
+		xs[i] = float64(t.Unix())
+		ys[i] = day.Amount
+	}
+
+	var slope, intercept float64
+	switch method {
+	case LeastSquares:
+		slope, intercept = leastSquares(xs, ys)
+	case TheilSen:
+		slope, intercept = theilSen(xs, ys)
+	default:
+		return &pb.Trendline{}, errors.New("unknown trendline method: " + method)
+	}
+	return &pb.Trendline{Slope: float32(slope), Intercept: float32(intercept)}, nil
+}
+
+// leastSquares
+// returns the ordinary least squares line of the points. The sums are taken about the means so
+// the squares of the epoch seconds do not lose the precision of the amounts, points all on the
+// same day have a flat line through their mean.
+func leastSquares(xs []float64, ys []float64) (float64, float64) {
+	if len(xs) == 0 {
+		return 0, 0
+	}
+	meanX, meanY := mean(xs), mean(ys)
+	var sxy, sxx float64
+	for i := range xs {
+		dx := xs[i] - meanX
+		sxy += dx * (ys[i] - meanY)
+		sxx += dx * dx
+	}
+	if sxx == 0 {
+		return 0, meanY
+	}
+	slope := sxy / sxx
+	return slope, meanY - slope*meanX
+}
+
+// theilSen
+// returns the Theil-Sen line of the points, the median of the slopes between every pair of days
+// and the median of the intercepts with that slope. Up to about a quarter of the days can be
+// outliers without moving the line.
+func theilSen(xs []float64, ys []float64) (float64, float64) {
+	if len(xs) == 0 {
+		return 0, 0
+	}
+	slopes := []float64{}
+	for i := range xs {
+		for j := i + 1; j < len(xs); j++ {
+			if xs[i] != xs[j] {
+				slopes = append(slopes, (ys[j]-ys[i])/(xs[j]-xs[i]))
+			}
+		}
+	}
+	slope := 0.0
+	if len(slopes) > 0 {
+		slope = median(slopes)
+	}
+	// The intercepts are taken at the first day and moved to the epoch, which keeps the
+	// differences of the amounts rather than of the much larger epoch intercepts
+	intercepts := make([]float64, len(xs))
+	for i := range xs {
+		intercepts[i] = ys[i] - slope*(xs[i]-xs[0])
+	}
+	return slope, median(intercepts) - slope*xs[0]
+}
+
+func mean(values []float64) float64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/seizadi/cost-insights-backend/pkg/pb"
+	"github.com/seizadi/cost-insights-backend/pkg/types"
+)
+
+func TestTrendlineWith(t *testing.T) {
+	// A cost of 100 on 2021-08-01 growing by 2 a day with an outlier on the fifth day
+	start := time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC)
+	aggregation := []*pb.DateAggregation{}
+	for i := 0; i < 10; i++ {
+		amount := 100 + 2*float64(i)
+		if i == 4 {
+			amount = 1000
+		}
+		aggregation = append(aggregation, &pb.DateAggregation{
+			Date:   start.AddDate(0, 0, i).Format(types.DEFAULT_DATE_FORMAT),
+			Amount: amount,
+		})
+	}
+	at := func(trendline *pb.Trendline, day int) float64 {
+		return float64(trendline.Intercept) + float64(trendline.Slope)*float64(start.AddDate(0, 0, day).Unix())
+	}
+
+	trendline, err := TrendlineWith(TheilSen, aggregation)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if perDay := float64(trendline.Slope) * 86400; math.Abs(perDay-2) > 1e-3 {
+		t.Errorf("Output slope of %v per day not equal to expected 2", perDay)
+	}
+	// The float32 intercept at the epoch is within a few cents on the days
+	if math.Abs(at(trendline, 0)-100) > 0.5 || math.Abs(at(trendline, 9)-118) > 0.5 {
+		t.Errorf("Output %v to %v not equal to expected 100 to 118", at(trendline, 0), at(trendline, 9))
+	}
+
+	trendline, err = TrendlineWith(LeastSquares, aggregation)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if at(trendline, 9) >= at(trendline, 0) {
+		t.Errorf("Output %v to %v expected to fall with the outlier", at(trendline, 0), at(trendline, 9))
+	}
+
+	aggregation[4].Amount = 108
+	trendline, err = TrendlineWith(LeastSquares, aggregation)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if math.Abs(at(trendline, 0)-100) > 0.5 || math.Abs(at(trendline, 9)-118) > 0.5 {
+		t.Errorf("Output %v to %v not equal to expected 100 to 118", at(trendline, 0), at(trendline, 9))
+	}
+}
+
+func TestTrendlineWithFewDays(t *testing.T) {
+	for _, method := range []string{LeastSquares, TheilSen} {
+		trendline, err := TrendlineWith(method, nil)
+		if err != nil || trendline.Slope != 0 || trendline.Intercept != 0 {
+			t.Errorf("Output %v, %v for no days not equal to expected zero trendline", trendline, err)
+		}
+		trendline, err = TrendlineWith(method, []*pb.DateAggregation{{Date: "2021-08-01", Amount: 42}})
+		if err != nil || trendline.Slope != 0 || trendline.Intercept != 42 {
+			t.Errorf("Output %v, %v for one day not equal to expected flat trendline", trendline, err)
+		}
+	}
+	if _, err := TrendlineWith("median", nil); err == nil {
+		t.Errorf("Expected error for unknown method")
+	}
+	if _, err := TrendlineWith(LeastSquares, []*pb.DateAggregation{{Date: "2021-08"}}); err == nil {
+		t.Errorf("Expected error for invalid date")
+	}
+}